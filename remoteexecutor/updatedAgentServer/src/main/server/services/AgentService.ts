@@ -6,6 +6,8 @@ import yaml from 'js-yaml';
 import axios, { AxiosResponse } from 'axios';
 import { MarketplaceAgent } from '@codebolt/types/apis/agents';
 import { logger } from '@/main/utils/logger';
+import { watchFileChanges } from '@/main/utils/fileWatcher';
+import { SendMessageToTui } from '../../../tuiLib/tuiMessaging/sendMessageToTui';
 
 export interface AgentInfo {
   agentId: string;
@@ -31,6 +33,8 @@ export class AgentService {
   private static instance: AgentService | null = null;
   private agents: Map<string, AgentInfo>;
   private cliAgentInfo?: AgentInfo;
+  // Marketplace agents the user has installed; the rest are only offered
+  private installedAgentIds: Set<string>;
 
   private readonly defaultAgent: AgentInfo = {
     agentId: 'cli-agent',
@@ -41,6 +45,11 @@ export class AgentService {
   };
   private constructor() {
     this.agents = new Map();
+    this.installedAgentIds = this.readInstalledAgents();
+    // Reload when agents.json is written, by us or by hand, and tell the TUIs
+    watchFileChanges(this.agentsFilePath(), () => {
+      this.reloadMarketplaceAgents();
+    });
     // Load agents from JSON file during initialization
     this.getMarketplaceAgents();
     // this.getLocalAgents();
@@ -61,24 +70,10 @@ export class AgentService {
    */
   public async getMarketplaceAgents(): Promise<AgentInfo[]> {
     try {
-      const configPath = path.join(CodeboltApplicationPath(), 'agents.json');
+      const configPath = this.agentsFilePath();
       
-      // First check if agents.json exists
-      if (fs.existsSync(configPath)) {
-        const configFile = fs.readFileSync(configPath, 'utf8');
-        const config = JSON.parse(configFile);
-        
-        // If we have agents in the file, return them
-        if (config.agents && config.agents.length > 0) {
-          config.agents.forEach((agent: MarketplaceAgent) => {
-            const agentInfo = this.convertToAgentInfo(agent);
-            const key = agentInfo.agentId || agent.unique_id;
-            if (key) {
-              this.agents.set(key, agentInfo);
-            }
-          });
-        }
-      }
+      // First load the agents already in agents.json
+      this.readAgentsFile().forEach((agent) => this.addMarketplaceAgent(agent));
       
       // If no local agents, fetch from API
       const response: AxiosResponse<MarketplaceAgent[]> = await axios.get<MarketplaceAgent[]>('https://api.codebolt.ai/api/agents/list');
@@ -99,13 +94,7 @@ export class AgentService {
       fs.writeFileSync(configPath, JSON.stringify(updatedConfig, null, 2));
       
       // Update the agents map with the new marketplace agents
-      marketplaceAgents.forEach((agent: MarketplaceAgent) => {
-        const agentInfo = this.convertToAgentInfo(agent);
-        const key = agentInfo.agentId || agent.unique_id;
-        if (key) {
-          this.agents.set(key, agentInfo);
-        }
-      });
+      marketplaceAgents.forEach((agent: MarketplaceAgent) => this.addMarketplaceAgent(agent));
       
       return Array.from(this.agents.values()).filter(agent => !agent.isLocal);
     } catch (error) {
@@ -114,8 +103,53 @@ export class AgentService {
     }
   }
 
+  /**
+   * Replace the marketplace agents with the contents of agents.json and
+   * notify the TUIs. Local and CLI agents are kept.
+   */
+  public reloadMarketplaceAgents(): void {
+    let marketplaceAgents: MarketplaceAgent[];
+    try {
+      marketplaceAgents = this.readAgentsFile();
+    } catch (error) {
+      logger.error('Error reloading agents.json:', error);
+      return;
+    }
+
+    this.agents.forEach((agent, key) => {
+      if (this.isMarketplaceAgent(agent)) {
+        this.agents.delete(key);
+      }
+    });
+    marketplaceAgents.forEach((agent) => this.addMarketplaceAgent(agent));
+    new SendMessageToTui().notifyRegistryChange('agents');
+  }
+
+  /**
+   * List every agent. Marketplace agents carry `installed`, which is false
+   * until installAgent is called for them; local and CLI agents are always
+   * usable.
+   */
   public getAgents(): AgentInfo[] {
-    return Array.from(this.agents.values());
+    return Array.from(this.agents.values()).map((agent) => this.withInstallState(agent));
+  }
+
+  /**
+   * Install and enable a marketplace agent so it can be selected, and notify
+   * the TUIs. Returns undefined if no agent has that id.
+   */
+  public installAgent(agentId: string): AgentInfo | undefined {
+    const agent = this.agents.get(agentId);
+    if (!agent) {
+      return undefined;
+    }
+    if (this.isMarketplaceAgent(agent) && !this.installedAgentIds.has(agent.agentId)) {
+      this.installedAgentIds.add(agent.agentId);
+      this.writeInstalledAgents();
+      new SendMessageToTui().notifyRegistryChange('agents');
+    }
+
+    return this.withInstallState(agent);
   }
 
   public async getLocalAgents(): Promise<AgentInfo[]> {
@@ -160,6 +194,7 @@ export class AgentService {
           this.agents.set(agent.agentId, agent);
         }
       });
+      new SendMessageToTui().notifyRegistryChange('agents');
 
       return agentsData;
     } catch (error) {
@@ -199,6 +234,65 @@ export class AgentService {
     };
   }
 
+  private isMarketplaceAgent(agent: AgentInfo): boolean {
+    return !agent.isLocal && agent !== this.cliAgentInfo && agent !== this.defaultAgent;
+  }
+
+  private withInstallState(agent: AgentInfo): AgentInfo {
+    if (!this.isMarketplaceAgent(agent)) {
+      return agent;
+    }
+    return { ...agent, installed: this.installedAgentIds.has(agent.agentId) };
+  }
+
+  private installedAgentsFilePath(): string {
+    return path.join(CodeboltApplicationPath(), 'installed-agents.json');
+  }
+
+  private readInstalledAgents(): Set<string> {
+    try {
+      const configPath = this.installedAgentsFilePath();
+      if (!fs.existsSync(configPath)) {
+        return new Set();
+      }
+      const config = JSON.parse(fs.readFileSync(configPath, 'utf8'));
+      return new Set(Array.isArray(config.installed) ? config.installed : []);
+    } catch (error) {
+      logger.error('Error reading installed-agents.json:', error);
+      return new Set();
+    }
+  }
+
+  private writeInstalledAgents(): void {
+    const configPath = this.installedAgentsFilePath();
+    const dirPath = path.dirname(configPath);
+    if (!fs.existsSync(dirPath)) {
+      fs.mkdirSync(dirPath, { recursive: true });
+    }
+    fs.writeFileSync(configPath, JSON.stringify({ installed: Array.from(this.installedAgentIds) }, null, 2));
+  }
+
+  private agentsFilePath(): string {
+    return path.join(CodeboltApplicationPath(), 'agents.json');
+  }
+
+  private readAgentsFile(): MarketplaceAgent[] {
+    const configPath = this.agentsFilePath();
+    if (!fs.existsSync(configPath)) {
+      return [];
+    }
+    const config = JSON.parse(fs.readFileSync(configPath, 'utf8'));
+    return Array.isArray(config.agents) ? config.agents : [];
+  }
+
+  private addMarketplaceAgent(agent: MarketplaceAgent): void {
+    const agentInfo = this.convertToAgentInfo(agent);
+    const key = agentInfo.agentId || agent.unique_id;
+    if (key) {
+      this.agents.set(key, agentInfo);
+    }
+  }
+
   /**
    * Convert MarketplaceAgent to AgentInfo
    * @param marketplaceAgent The marketplace agent to convert
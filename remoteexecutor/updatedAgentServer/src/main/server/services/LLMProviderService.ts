@@ -5,6 +5,8 @@ import fs from 'fs';
 import path from 'path';
 import { Model } from '@codebolt/types/apis/models';
 import { logger } from '../../utils/logger';
+import { watchFileChanges } from '../../utils/fileWatcher';
+import { SendMessageToTui } from '../../../tuiLib/tuiMessaging/sendMessageToTui';
 
 export class LLMProviderService {
     private static instance: LLMProviderService | null = null;
@@ -13,6 +15,13 @@ export class LLMProviderService {
 
     private constructor() {
         this.llmProviders = new Map();
+        // Provider keys decide which models are listed, so a key written to
+        // config.json changes the model list
+        watchFileChanges(path.join(getUserHomePath(), 'config.json'), () => {
+            this.providersLoaded = false;
+            this.llmProviders.clear();
+            new SendMessageToTui().notifyRegistryChange('models');
+        });
     }
 
     public static getInstance(): LLMProviderService {
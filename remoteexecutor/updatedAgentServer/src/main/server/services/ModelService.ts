@@ -5,6 +5,8 @@ import fs from 'fs';
 import path from 'path';
 import axios from 'axios';
 import { logger } from '@/main/utils/logger';
+import { watchFileChanges } from '@/main/utils/fileWatcher';
+import { SendMessageToTui } from '../../../tuiLib/tuiMessaging/sendMessageToTui';
 import { log } from 'console';
 
 export class ModelService {
@@ -14,6 +16,10 @@ export class ModelService {
 
   private constructor() {
     this.Models = new Map();
+    // Reload when models.json is written, by us or by hand, and tell the TUIs
+    watchFileChanges(path.join(CodeboltApplicationPath(), 'models.json'), () => {
+      this.reloadModels();
+    });
   }
 
   public static getInstance(): ModelService {
@@ -128,12 +134,22 @@ export class ModelService {
     }
   }
 
+  /**
+   * Drop the cached models, load them again and notify the TUIs
+   */
+  public async reloadModels(): Promise<void> {
+    this.modelsLoaded = false;
+    await this.loadModels();
+    new SendMessageToTui().notifyRegistryChange('models');
+  }
+
   /**
    * Get models with providers that have keys added
    * @returns Array of models with valid providers
    */
   public async getModels(): Promise<Model[]> {
     // Get all models
+    await this.loadModels();
     const allModels = Array.from(this.Models.values());
 
     // Get LLM providers service instance
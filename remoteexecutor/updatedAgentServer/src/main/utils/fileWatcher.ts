@@ -0,0 +1,23 @@
+import fs from 'fs';
+
+const watchedFiles = new Set<string>();
+
+/**
+ * Call onChange whenever filePath is created, edited or removed. The file is
+ * polled rather than watched, so a file that does not exist yet, or that an
+ * editor replaces instead of writing in place, is still picked up. Watching
+ * the same path twice is a no-op.
+ */
+export function watchFileChanges(filePath: string, onChange: () => void, intervalMs: number = 2000): void {
+  if (watchedFiles.has(filePath)) {
+    return;
+  }
+  watchedFiles.add(filePath);
+
+  fs.watchFile(filePath, { interval: intervalMs, persistent: false }, (current, previous) => {
+    if (current.mtimeMs === previous.mtimeMs && current.size === previous.size) {
+      return;
+    }
+    onChange();
+  });
+}
@@ -15,12 +15,16 @@ export interface LogEntry {
   data?: any;
 }
 
+export type LogListener = (entry: LogEntry) => void;
+
 export class Logger {
   private static instance: Logger;
   private logFilePath: string;
   private logLevel: LogLevel;
   private enableConsole: boolean;
   private enableFile: boolean;
+  private readonly listeners = new Set<LogListener>();
+  private notifying = false;
 
   private applyRuntimeOptions(options?: {
     logFilePath?: string;
@@ -129,6 +133,7 @@ export class Logger {
   }
 
   private writeLog(level: LogLevel, levelName: string, message: string, data?: any): void {
+    this.notifyListeners(levelName, message, data);
     if (level < this.logLevel) {
       return;
     }
@@ -163,6 +168,36 @@ export class Logger {
     }
   }
 
+  /**
+   * Register a listener for every log record, whatever the log level. Returns
+   * a function that removes it.
+   */
+  public addListener(listener: LogListener): () => void {
+    this.listeners.add(listener);
+    return () => {
+      this.listeners.delete(listener);
+    };
+  }
+
+  /**
+   * Hand a record to the listeners. Records logged while a listener runs are
+   * not passed on, so a listener that logs cannot feed on its own output.
+   */
+  private notifyListeners(level: string, message: string, data?: any): void {
+    if (this.listeners.size === 0 || this.notifying) {
+      return;
+    }
+    this.notifying = true;
+    const entry: LogEntry = { timestamp: new Date().toISOString(), level, message, data };
+    try {
+      this.listeners.forEach((listener) => listener(entry));
+    } catch (error) {
+      console.error('Log listener failed:', error);
+    } finally {
+      this.notifying = false;
+    }
+  }
+
   public debug(message: string, data?: any): void {
     this.writeLog(LogLevel.DEBUG, 'DEBUG', message, data);
   }
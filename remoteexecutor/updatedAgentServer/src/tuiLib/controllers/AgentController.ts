@@ -22,4 +22,22 @@ export class AgentController {
       res.status(500).json({ error: 'Failed to fetch agents' });
     }
   }
+
+  public installAgent(req: Request, res: Response): void {
+    const { agentId } = req.params;
+    try {
+      const agent = this.agentService.installAgent(agentId);
+      if (!agent) {
+        res.status(404).json({ error: `Agent ${agentId} not found` });
+        return;
+      }
+      res.json({ agent });
+      logger.info(formatLogMessage('info', 'AgentController', `Agent ${agentId} installed for ${req.ip}`));
+    } catch (error) {
+      logger.error(
+        formatLogMessage('error', 'AgentController', `Failed to install agent ${agentId}: ${error instanceof Error ? error.message : String(error)}`)
+      );
+      res.status(500).json({ error: 'Failed to install agent' });
+    }
+  }
 }
@@ -14,6 +14,9 @@ export class AgentRoutes {
   private setupRoutes(): void {
     this.router.get('/', this.agentController.getAgents.bind(this.agentController));
 
+    // Install and enable an agent the server offers
+    this.router.post('/:agentId/install', this.agentController.installAgent.bind(this.agentController));
+
     this.router.get('/health', (req: Request, res: Response) => {
       res.json({ success: true });
     });
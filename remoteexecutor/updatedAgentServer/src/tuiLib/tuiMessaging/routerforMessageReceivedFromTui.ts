@@ -14,6 +14,7 @@ import {
   type WriteFileConfirmation,
 } from "../../localexecutions/file/writeFileHandler";
 import { AgentTypeEnum } from "@/types/cli";
+import { TuiLogStream } from "./tuiLogStream";
 
 export class TuiMessageRouter {
   private connectionManager: ConnectionManager;
@@ -46,6 +47,14 @@ export class TuiMessageRouter {
     if (!message.type) {
       return;
     }
+    if (message.type === "subscribeLogs") {
+      TuiLogStream.getInstance().subscribe(tui.id, message.level);
+      return;
+    }
+    if (message.type === "unsubscribeLogs") {
+      TuiLogStream.getInstance().unsubscribe(tui.id);
+      return;
+    }
     if (message.type === "confirmationResponse") {
       this.readFileHandler.handleConfirmation(message as ReadFileConfirmation);
       this.writeFileHandler.handleConfirmation(message as WriteFileConfirmation);
@@ -29,6 +29,17 @@ export class SendMessageToTui {
     return true;
   }
 
+  /**
+   * Tell connected TUIs that the agent or model list changed so they refetch it.
+   */
+  notifyRegistryChange(registry: 'agents' | 'models'): void {
+    this.broadcast({
+      type: 'notification',
+      action: `${registry}-changed`,
+      timestamp: Date.now()
+    });
+  }
+
   sendResponseToTuis(agent: ClientConnection, message: Message): void {
     const payload = {
       ...message,
@@ -0,0 +1,98 @@
+import { ConnectionManager } from '../../main/core/connectionManagers/connectionManager';
+import { LogEntry, LogLevel, logger } from '../../main/utils/logger';
+
+const levels: Record<string, LogLevel> = {
+  debug: LogLevel.DEBUG,
+  info: LogLevel.INFO,
+  warn: LogLevel.WARN,
+  error: LogLevel.ERROR
+};
+
+/**
+ * Streams server log records to the TUIs that asked for them with a
+ * `subscribeLogs` message. Each record is sent as a `serverLog` message.
+ */
+export class TuiLogStream {
+  private static instance: TuiLogStream;
+
+  private readonly subscribers = new Map<string, LogLevel>();
+  private removeListener?: () => void;
+
+  private constructor() {}
+
+  static getInstance(): TuiLogStream {
+    if (!TuiLogStream.instance) {
+      TuiLogStream.instance = new TuiLogStream();
+    }
+
+    return TuiLogStream.instance;
+  }
+
+  /**
+   * Start sending records at or above level (default info) to the TUI.
+   */
+  subscribe(tuiId: string, level?: unknown): void {
+    this.subscribers.set(tuiId, levels[String(level ?? '').toLowerCase()] ?? LogLevel.INFO);
+    if (!this.removeListener) {
+      this.removeListener = logger.addListener((entry) => this.forward(entry));
+    }
+  }
+
+  unsubscribe(tuiId: string): void {
+    this.subscribers.delete(tuiId);
+    if (this.subscribers.size === 0 && this.removeListener) {
+      this.removeListener();
+      this.removeListener = undefined;
+    }
+  }
+
+  private forward(entry: LogEntry): void {
+    const level = levels[entry.level.toLowerCase()] ?? LogLevel.INFO;
+    let payload: string;
+    try {
+      payload = JSON.stringify({
+        type: 'serverLog',
+        level: entry.level.toLowerCase(),
+        message: entry.message,
+        timestamp: entry.timestamp,
+        data: logData(entry.data)
+      });
+    } catch {
+      return;
+    }
+
+    // Written straight to the socket: sendToTui logs every delivery.
+    const tuiManager = ConnectionManager.getInstance().getTuiConnectionManager();
+    this.subscribers.forEach((minLevel, tuiId) => {
+      if (level < minLevel) {
+        return;
+      }
+      const tui = tuiManager.getTui(tuiId);
+      if (!tui) {
+        this.unsubscribe(tuiId);
+        return;
+      }
+      try {
+        tui.ws.send(payload);
+      } catch {
+        this.unsubscribe(tuiId);
+      }
+    });
+  }
+}
+
+/**
+ * The TUI expects data to be an object; other values are wrapped.
+ */
+function logData(data: unknown): Record<string, unknown> | undefined {
+  if (data === undefined || data === null) {
+    return undefined;
+  }
+  if (data instanceof Error) {
+    return { error: data.message };
+  }
+  if (typeof data === 'object' && !Array.isArray(data)) {
+    return data as Record<string, unknown>;
+  }
+  return { value: data };
+}
@@ -3,10 +3,13 @@ package main
 import (
 	"flag"
 	"os"
-	"strconv"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"gotui/internal/app"
+	"gotui/internal/config"
 	"gotui/internal/logging"
 	"gotui/internal/stores"
 
@@ -16,108 +19,151 @@ import (
 )
 
 func main() {
-	// Enable debugging
-	if err := logging.ConfigureFile("/tmp/gotui-debug.log", true); err != nil {
-		logging.Printf("Failed to configure debug log: %v", err)
-	}
-	defer logging.Close()
+	flags := config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
 
-	logging.Printf("=== GOTUI DEBUG SESSION STARTED ===")
-	logging.Printf("Time: %s", time.Now().Format("2006-01-02 15:04:05"))
+	projectPath := os.Getenv("CURRENT_PROJECT_PATH")
+	projectName := os.Getenv("CURRENT_PROJECT_NAME")
+	projectType := os.Getenv("CURRENT_PROJECT_TYPE")
 
-	host := flag.String("host", "localhost", "Server host")
-	port := flag.Int("port", 3001, "Server port")
-	flag.Parse()
+	settings, report := config.Load(config.Options{
+		ProjectPath: projectPath,
+		UserFile:    flags.ConfigFile(),
+		Flags:       flags,
+	})
 
-	hostValue := *host
-	if envHost := os.Getenv("AGENT_SERVER_HOST"); envHost != "" {
-		hostValue = envHost
+	// Enable debugging
+	level, _ := logging.ParseLevel(settings.Log.Level)
+	if err := logging.Configure(logging.Options{
+		Path:         settings.Log.Path,
+		Truncate:     *settings.Log.Truncate,
+		Level:        level,
+		MaxSize:      int64(*settings.Log.MaxSizeMB) << 20,
+		MaxBackups:   *settings.Log.MaxBackups,
+		RedactBodies: *settings.Log.RedactBodies,
+	}); err != nil {
+		logging.Error("failed to configure debug log", "path", settings.Log.Path, "err", err)
 	}
+	defer logging.Close()
 
-	portValue := *port
-	if envPort := os.Getenv("AGENT_SERVER_PORT"); envPort != "" {
-		if parsed, err := strconv.Atoi(envPort); err == nil {
-			portValue = parsed
-		}
+	logging.Info("session started", "time", time.Now().Format("2006-01-02 15:04:05"), "level", settings.Log.Level)
+	for _, line := range report.Lines() {
+		logging.Info(line, "source", "config")
+	}
+	for _, line := range report.ErrorLines() {
+		logging.Error(line, "source", "config")
 	}
 
-	protocol := os.Getenv("AGENT_SERVER_PROTOCOL")
 	tuiID := os.Getenv("TUI_ID")
 	if tuiID == "" {
 		tuiID = uuid.NewString()
 	}
 
-	projectPath := os.Getenv("CURRENT_PROJECT_PATH")
-	projectName := os.Getenv("CURRENT_PROJECT_NAME")
-	projectType := os.Getenv("CURRENT_PROJECT_TYPE")
-
-	agentSelection := stores.AgentSelection{
-		ID:           os.Getenv("SELECTED_AGENT_ID"),
-		Name:         os.Getenv("SELECTED_AGENT_NAME"),
-		AgentType:    os.Getenv("SELECTED_AGENT_TYPE"),
-		AgentDetails: os.Getenv("SELECTED_AGENT_DETAIL"),
+	agentSelection := stores.AgentSelection{}
+	if agent := settings.Defaults.Agent; agent != nil {
+		agentSelection = stores.AgentSelection{
+			ID:           agent.ID,
+			Name:         agent.Name,
+			AgentType:    agent.Type,
+			AgentDetails: agent.Detail,
+		}
 	}
 
-	modelSelection := stores.ModelOption{
-		Name:     os.Getenv("SELECTED_MODEL_NAME"),
-		Provider: os.Getenv("SELECTED_MODEL_PROVIDER"),
-	}
-	if modelSelection.Name == "" {
-		modelSelection.Name = "gpt-4.1-mini"
+	modelSelection := stores.ModelOption{}
+	if model := settings.Defaults.Model; model != nil {
+		modelSelection = stores.ModelOption{Name: model.Name, Provider: model.Provider}
 	}
-	if modelSelection.Provider == "" {
-		modelSelection.Provider = "OpenAI"
+
+	userFile := flags.ConfigFile()
+	if userFile == "" {
+		userFile = config.UserFilePath()
 	}
 
+	profile := settings.ActiveProfile()
+
 	cfg := app.Config{
-		Host:        hostValue,
-		Port:        portValue,
-		Protocol:    protocol,
-		TuiID:       tuiID,
-		ProjectPath: projectPath,
-		ProjectName: projectName,
-		ProjectType: projectType,
-		Agent:       agentSelection,
-		Model:       modelSelection,
+		Host:         profile.Host,
+		Port:         profile.Port,
+		Protocol:     profile.Protocol,
+		TuiID:        tuiID,
+		ProjectPath:  projectPath,
+		ProjectName:  projectName,
+		ProjectType:  projectType,
+		Agent:        agentSelection,
+		Model:        modelSelection,
+		Theme:        settings.Theme,
+		LayoutMode:   settings.Layout,
+		Keymap:       settings.Keybindings,
+		KeymapSource: strings.Join(report.KeymapFiles, ", "),
+		SettingsFile: userFile,
+		StartupLog:   report.Lines(),
+		LogExportDir: filepath.Dir(settings.Log.Path),
+		Profiles:     settings.ServerProfiles(),
+		Profile:      profile.Name,
+
+		Notifications: settings.Notifications,
+		Usage:         settings.Usage,
+		ImagePreviews: settings.ImagePreviews,
+		StartupErrors: report.ErrorLines(),
+	}
+	if model := report.FileDefaults.Model; model != nil {
+		cfg.DefaultModel = &stores.ModelOption{Name: model.Name, Provider: model.Provider}
+	}
+	for _, model := range report.FileDefaults.FavoriteModels {
+		cfg.FavoriteModels = append(cfg.FavoriteModels, stores.ModelOption{Name: model.Name, Provider: model.Provider})
+	}
+	for _, model := range report.FileDefaults.RecentModels {
+		cfg.RecentModels = append(cfg.RecentModels, stores.ModelOption{Name: model.Name, Provider: model.Provider})
+	}
+	for _, model := range append(report.ProjectDefaults.FavoriteModels, report.ProjectDefaults.RecentModels...) {
+		if !slices.Contains(report.UserDefaults.FavoriteModels, model) && !slices.Contains(report.UserDefaults.RecentModels, model) {
+			cfg.ProjectModels = append(cfg.ProjectModels, stores.ModelOption{Name: model.Name, Provider: model.Provider})
+		}
+	}
+	if agent := report.FileDefaults.Agent; agent != nil {
+		cfg.DefaultAgent = &stores.AgentSelection{
+			ID:           agent.ID,
+			Name:         agent.Name,
+			AgentType:    agent.Type,
+			AgentDetails: agent.Detail,
+		}
 	}
 
-	logging.Printf("Config: host=%s, port=%d, protocol=%s, tuiID=%s (client mode)", cfg.Host, cfg.Port, cfg.Protocol, cfg.TuiID)
+	logging.Info("config", "profile", cfg.Profile, "host", cfg.Host, "port", cfg.Port, "protocol", cfg.Protocol, "tuiID", cfg.TuiID)
 
 	zone.NewGlobal()
 	defer zone.Close()
 
-	logging.Printf("Creating model...")
+	logging.Debug("creating model")
 	m := app.NewModel(cfg)
 
-	logging.Printf("Creating tea program...")
-
 	// Create program with full screen options for better terminal usage
 	var p *tea.Program
 
-	logging.Printf("Creating program with full screen support...")
 	// Use alt screen and mouse support for full terminal takeover
 	p = tea.NewProgram(m,
 		tea.WithAltScreen(),       // Use alternate screen buffer for full screen
 		tea.WithMouseCellMotion(), // Enable mouse support
+		tea.WithReportFocus(),     // Alert only when the user looks elsewhere
 	)
 
-	logging.Printf("Starting tea program with full screen...")
+	logging.Debug("starting tea program", "altScreen", true)
 	if _, err := p.Run(); err != nil {
-		logging.Printf("Tea program error with full screen: %v", err)
+		logging.Warn("tea program failed with full screen", "err", err)
 
 		// Try minimal fallback without alt screen
-		logging.Printf("Retrying with minimal options...")
+		logging.Info("retrying with minimal options")
 		p = tea.NewProgram(m)
 		if _, err := p.Run(); err != nil {
-			logging.Printf("Tea program error in fallback mode: %v", err)
-			logging.Printf("Terminal error: %v", err)
-			logging.Printf("Troubleshooting instructions:")
-			logging.Printf("1. Try: TERM=xterm-256color ./gotui")
-			logging.Printf("2. Check terminal size: echo $COLUMNS x $LINES")
-			logging.Printf("3. See debug logs: tail -f /tmp/gotui-debug.log")
+			logging.Error("tea program failed in fallback mode", "err", err)
+			logging.Info("troubleshooting: try TERM=xterm-256color ./gotui")
+			logging.Info("troubleshooting: check terminal size with echo $COLUMNS x $LINES")
+			logging.Info("troubleshooting: see debug logs with tail -f " + settings.Log.Path)
+			m.SaveSession()
 			os.Exit(1)
 		}
 	}
 
-	logging.Printf("Tea program ended normally")
+	m.SaveSession()
+	logging.Info("session ended")
 }
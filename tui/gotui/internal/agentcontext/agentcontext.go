@@ -0,0 +1,209 @@
+// Package agentcontext describes what the next request puts in the agent's
+// context window and estimates how much of the window it fills.
+package agentcontext
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Item kinds.
+const (
+	KindFile  = "file"
+	KindImage = "image"
+	KindTurn  = "turn"
+)
+
+// Item is one piece of the next request's context.
+type Item struct {
+	// Key identifies the item within a conversation, e.g. "file:/abs/path"
+	// or "turn:3", so drops survive rebuilding the list.
+	Key   string
+	Kind  string
+	Label string
+	// Path is the absolute path of files and images.
+	Path string
+	// Attached files are inlined into the message rather than mentioned.
+	Attached bool
+	// Role and Content hold the message of a turn.
+	Role    string
+	Content string
+	Tokens  int
+	// Dropped items are left out of the next request.
+	Dropped bool
+}
+
+// Droppable reports whether the item can be left out of the next request.
+// Prior turns only count towards the estimate: the server, not the request,
+// decides which of them reach the agent.
+func (i Item) Droppable() bool {
+	return i.Kind != KindTurn
+}
+
+// Window is the estimated context of the next request.
+type Window struct {
+	Items []Item
+	// Limit is the selected model's context size in tokens, 0 if unknown.
+	Limit int
+}
+
+// Used returns the estimated tokens of the items that will be sent.
+func (w Window) Used() int {
+	total := 0
+	for _, item := range w.Items {
+		if !item.Dropped {
+			total += item.Tokens
+		}
+	}
+	return total
+}
+
+// Fraction returns how much of the limit is used, or 0 without a limit.
+func (w Window) Fraction() float64 {
+	if w.Limit <= 0 {
+		return 0
+	}
+	return float64(w.Used()) / float64(w.Limit)
+}
+
+// Count returns the number of items of kind, and how many of them are
+// dropped.
+func (w Window) Count(kind string) (total, dropped int) {
+	for _, item := range w.Items {
+		if item.Kind != kind {
+			continue
+		}
+		total++
+		if item.Dropped {
+			dropped++
+		}
+	}
+	return total, dropped
+}
+
+// Request is the context that goes out with the next message: the items of
+// a Window that were not dropped.
+type Request struct {
+	// Files, Attachments and Images are absolute paths. Files are mentioned
+	// for the agent to read; attachments are inlined into the message.
+	Files       []string
+	Attachments []string
+	Images      []string
+}
+
+// Request returns the items that were not dropped.
+func (w Window) Request() Request {
+	var req Request
+	for _, item := range w.Items {
+		if item.Dropped {
+			continue
+		}
+		switch item.Kind {
+		case KindFile:
+			if item.Attached {
+				req.Attachments = append(req.Attachments, item.Path)
+			} else {
+				req.Files = append(req.Files, item.Path)
+			}
+		case KindImage:
+			req.Images = append(req.Images, item.Path)
+		}
+	}
+	return req
+}
+
+// imageTokens is a flat estimate for an image; providers charge roughly this
+// for a typical screenshot.
+const imageTokens = 1_500
+
+// EstimateTokens approximates the tokens in text at four bytes per token,
+// which is close for English prose and code.
+func EstimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (len(text) + 3) / 4
+}
+
+// TurnKey returns the key of the conversation message at index.
+func TurnKey(index int) string { return "turn:" + strconv.Itoa(index) }
+
+// FileItem describes a mentioned file, estimating its tokens from its size.
+func FileItem(path, label string) Item {
+	item := Item{Key: KindFile + ":" + path, Kind: KindFile, Label: label, Path: path}
+	if info, err := os.Stat(path); err == nil {
+		item.Tokens = int((info.Size() + 3) / 4)
+	}
+	return item
+}
+
+// ImageItem describes an attached image.
+func ImageItem(path, label string) Item {
+	return Item{Key: KindImage + ":" + path, Kind: KindImage, Label: label, Path: path, Tokens: imageTokens}
+}
+
+// Mentions returns the files referenced as @path in input, resolved against
+// root. Mentions of paths that are not existing files are ignored.
+func Mentions(input, root string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, field := range strings.FieldsFunc(input, unicode.IsSpace) {
+		if !strings.HasPrefix(field, "@") || len(field) < 2 {
+			continue
+		}
+		name := strings.TrimRight(field[1:], ".,;:!?)\"'`")
+		if name == "" {
+			continue
+		}
+		path := name
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// ParseLimit reads a model's advertised context size such as "128K",
+// "1M", "200k tokens" or "200000". It returns 0 if s is not understood.
+func ParseLimit(s string) int {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimSpace(strings.TrimSuffix(s, "tokens"))
+	s = strings.ReplaceAll(s, ",", "")
+	if s == "" {
+		return 0
+	}
+	multiplier := 1.0
+	last, size := utf8.DecodeLastRuneInString(s)
+	switch last {
+	case 'k':
+		multiplier = 1_000
+	case 'm':
+		multiplier = 1_000_000
+	}
+	if multiplier != 1 {
+		s = strings.TrimSpace(s[:len(s)-size])
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0
+	}
+	return int(value * multiplier)
+}
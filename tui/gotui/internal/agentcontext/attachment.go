@@ -0,0 +1,252 @@
+package agentcontext
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Size limits for attachments. Text is inlined into the message, so it is
+// kept well below typical context sizes; images are base64-encoded into the
+// payload, so a message carries a bounded number and total size of them.
+const (
+	MaxTextAttachment  = 256 << 10
+	MaxImageAttachment = 5 << 20
+	MaxImages          = 8
+	MaxImagesTotal     = 20 << 20
+)
+
+// imageTypes are the image formats models accept, by sniffed MIME type.
+var imageTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// Attachment is a file attached to the next message.
+type Attachment struct {
+	// Path is absolute; Name is how it is shown.
+	Path  string
+	Name  string
+	Image bool
+	// MIMEType is set for images.
+	MIMEType string
+	Size     int64
+	// Temporary marks a file gotui wrote itself, such as a pasted clipboard
+	// image; it is deleted once the attachment is detached or sent.
+	Temporary bool
+}
+
+// Release deletes the file of a temporary attachment.
+func (a Attachment) Release() {
+	if a.Temporary {
+		os.Remove(a.Path)
+	}
+}
+
+// Item describes the attachment as a context item.
+func (a Attachment) Item() Item {
+	if a.Image {
+		return ImageItem(a.Path, a.Name)
+	}
+	item := FileItem(a.Path, a.Name)
+	item.Attached = true
+	return item
+}
+
+// NewAttachment checks that path can be attached: a text file or a PNG,
+// JPEG, GIF or WebP image within the size limits.
+func NewAttachment(path string) (Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	if info.IsDir() {
+		return Attachment{}, fmt.Errorf("%s is a directory", path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	defer file.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return Attachment{}, err
+	}
+	head = head[:n]
+
+	attachment := Attachment{Path: path, Name: filepath.Base(path), Size: info.Size()}
+	if mimeType := http.DetectContentType(head); imageTypes[mimeType] {
+		if info.Size() > MaxImageAttachment {
+			return Attachment{}, fmt.Errorf("%s is %s; images are limited to %s", attachment.Name, FormatSize(info.Size()), FormatSize(MaxImageAttachment))
+		}
+		attachment.Image = true
+		attachment.MIMEType = mimeType
+		return attachment, nil
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return Attachment{}, fmt.Errorf("%s is a binary file; only text and PNG, JPEG, GIF or WebP images can be attached", attachment.Name)
+	}
+	if info.Size() > MaxTextAttachment {
+		return Attachment{}, fmt.Errorf("%s is %s; text attachments are limited to %s", attachment.Name, FormatSize(info.Size()), FormatSize(MaxTextAttachment))
+	}
+	return attachment, nil
+}
+
+// Admit checks that attachment fits in a message next to existing.
+func Admit(existing []Attachment, attachment Attachment) error {
+	if !attachment.Image {
+		return nil
+	}
+	count, total := 1, attachment.Size
+	for _, other := range existing {
+		if other.Image && other.Path != attachment.Path {
+			count++
+			total += other.Size
+		}
+	}
+	if count > MaxImages {
+		return fmt.Errorf("a message can carry at most %d images", MaxImages)
+	}
+	if total > MaxImagesTotal {
+		return fmt.Errorf("images in a message are limited to %s in total", FormatSize(MaxImagesTotal))
+	}
+	return nil
+}
+
+// ImagePath reports whether pasted text is the path of a single image file,
+// as terminals insert when a file is dropped onto them, and returns it
+// resolved against root. Quoted, backslash-escaped and file:// paths are
+// understood.
+func ImagePath(pasted, root string) (string, bool) {
+	path := strings.TrimSpace(pasted)
+	if path == "" || strings.ContainsAny(path, "\r\n") {
+		return "", false
+	}
+	if unquoted, err := strconv.Unquote(path); err == nil {
+		path = unquoted
+	} else {
+		path = strings.Trim(path, "'")
+	}
+	if strings.HasPrefix(path, "file://") {
+		if u, err := url.Parse(path); err == nil {
+			path = u.Path
+		}
+	}
+	path = strings.ReplaceAll(path, "\\ ", " ")
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp":
+	default:
+		return "", false
+	}
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	if info, err := os.Stat(path); err != nil || info.IsDir() {
+		return "", false
+	}
+	return filepath.Clean(path), true
+}
+
+// Within reports whether path lies inside root once symlinks are resolved. A
+// path that does not exist yet is judged by its directory. An empty root
+// contains nothing.
+func Within(path, root string) bool {
+	if root == "" {
+		return false
+	}
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Let the missing file be reported as such if it would be inside.
+		resolved, err = filepath.EvalSymlinks(filepath.Dir(path))
+		resolved = filepath.Join(resolved, filepath.Base(path))
+	}
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(resolvedRoot, resolved)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// Inline appends the text files at paths to content as fenced blocks headed
+// by their paths. Files are held to MaxTextAttachment again, as they may have
+// grown since they were attached.
+func Inline(content string, paths []string) (string, error) {
+	var b strings.Builder
+	b.WriteString(content)
+	for _, path := range paths {
+		data, err := readLimited(path, MaxTextAttachment, "text attachments")
+		if err != nil {
+			return "", err
+		}
+		// The fence must be longer than any backtick run in the file.
+		fence := "```"
+		for strings.Contains(string(data), fence) {
+			fence += "`"
+		}
+		text := strings.TrimRight(string(data), "\n")
+		fmt.Fprintf(&b, "\n\n%s:\n%s\n%s\n%s", path, fence, text, fence)
+	}
+	return b.String(), nil
+}
+
+// DataURL reads the image at path, at most MaxImageAttachment, and encodes
+// it as a base64 data URL.
+func DataURL(path string) (string, error) {
+	data, err := readLimited(path, MaxImageAttachment, "images")
+	if err != nil {
+		return "", err
+	}
+	mimeType := http.DetectContentType(data)
+	if !imageTypes[mimeType] {
+		return "", fmt.Errorf("%s is not a PNG, JPEG, GIF or WebP image", filepath.Base(path))
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// readLimited reads the file at path, failing if it is larger than limit.
+func readLimited(path string, limit int64, kind string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s has grown past %s; %s are limited to that", filepath.Base(path), FormatSize(limit), kind)
+	}
+	return data, nil
+}
+
+// FormatSize formats a byte count, e.g. "1.5 MB".
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
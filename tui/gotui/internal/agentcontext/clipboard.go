@@ -0,0 +1,106 @@
+package agentcontext
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// clipboardCommands read a PNG from the system clipboard, in order of
+// preference for each platform.
+var clipboardCommands = map[string][][]string{
+	"darwin": {{"pngpaste", "-"}},
+	"linux": {
+		{"wl-paste", "--no-newline", "--type", "image/png"},
+		{"xclip", "-selection", "clipboard", "-target", "image/png", "-out"},
+	},
+}
+
+// ErrNoClipboardImage is returned when the clipboard holds no image.
+var ErrNoClipboardImage = errors.New("the clipboard holds no image")
+
+// PasteImage saves the image on the system clipboard to a temporary PNG
+// file and returns its path. It needs pngpaste on macOS, and wl-paste or
+// xclip on Linux.
+func PasteImage() (string, error) {
+	data, err := clipboardImage()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp("", "gotui-clipboard-*.png")
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+func clipboardImage() ([]byte, error) {
+	found := false
+	for _, args := range clipboardCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		found = true
+		data, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		if imageTypes[http.DetectContentType(data)] {
+			return data, nil
+		}
+	}
+	if !found {
+		switch runtime.GOOS {
+		case "darwin":
+			return nil, errors.New("reading images from the clipboard needs pngpaste")
+		case "linux":
+			return nil, errors.New("reading images from the clipboard needs wl-paste (wl-clipboard) or xclip")
+		}
+		return nil, errors.New("reading images from the clipboard is not supported on " + runtime.GOOS)
+	}
+	return nil, ErrNoClipboardImage
+}
+
+// selectionCommands read the current text selection: the primary selection
+// where the platform has one, otherwise the clipboard.
+var selectionCommands = map[string][][]string{
+	"darwin": {{"pbpaste"}},
+	"linux": {
+		{"wl-paste", "--no-newline", "--primary"},
+		{"xclip", "-selection", "primary", "-out"},
+		{"wl-paste", "--no-newline"},
+		{"xclip", "-selection", "clipboard", "-out"},
+	},
+}
+
+// SelectionText returns the text currently selected in other applications,
+// falling back to the clipboard.
+func SelectionText() (string, error) {
+	found := false
+	for _, args := range selectionCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		found = true
+		data, err := exec.Command(args[0], args[1:]...).Output()
+		if err == nil && len(bytes.TrimSpace(data)) > 0 {
+			return string(data), nil
+		}
+	}
+	if !found {
+		return "", errors.New("reading the selection is not supported here; install wl-clipboard or xclip")
+	}
+	return "", errors.New("nothing is selected")
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/components/chat"
+	"gotui/internal/stores"
+)
+
+type agentInstalledMsg struct {
+	option stores.AgentOption
+	err    error
+}
+
+// installAgent asks the server to install an agent picked in the agent
+// browser.
+func (m *Model) installAgent(msg chat.InstallAgentMsg) tea.Cmd {
+	option := msg.Option
+	if chatComp := m.chatComponent(); chatComp != nil {
+		chatComp.AddMessage("system", fmt.Sprintf("⤓ Installing agent %s…", option.Name))
+	}
+	protocol, host, port := m.cfg.Protocol, m.cfg.Host, m.cfg.Port
+	if err := m.transportErr; err != nil {
+		return func() tea.Msg { return agentInstalledMsg{option: option, err: err} }
+	}
+	if m.agentStore == nil {
+		m.agentStore = stores.SharedAgentStore()
+	}
+	store := m.agentStore
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return agentInstalledMsg{option: option, err: store.Install(ctx, protocol, host, port, option.ID)}
+	}
+}
+
+// handleAgentInstalled reports the outcome of an install and shows the agent
+// list Install reloaded, so the browser marks the agent as installed.
+func (m *Model) handleAgentInstalled(msg agentInstalledMsg) tea.Cmd {
+	chatComp := m.chatComponent()
+	if chatComp != nil {
+		chatComp.RefreshAgents()
+	}
+	if msg.err != nil {
+		if chatComp != nil {
+			chatComp.AgentInstallFailed(msg.option.ID)
+		}
+		if m.isAuthFailure(msg.err) {
+			m.reportAuthFailure("Failed to install agent", msg.err)
+			return nil
+		}
+		if chatComp != nil {
+			chatComp.AddMessage("system", fmt.Sprintf("❌ Could not install agent %s: %v", msg.option.Name, msg.err))
+		}
+		if m.logsPage != nil {
+			m.logsPage.LogsPanel().AddError(fmt.Sprintf("❌ Install agent %s failed: %v", msg.option.Name, msg.err))
+		}
+		return nil
+	}
+	if chatComp != nil {
+		chatComp.AddMessage("system", fmt.Sprintf("✅ Installed agent %s", msg.option.Name))
+	}
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🧭 Installed agent %s", msg.option.Name))
+	}
+	return nil
+}
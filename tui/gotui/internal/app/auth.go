@@ -0,0 +1,41 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"gotui/internal/stores"
+	"gotui/internal/transport"
+)
+
+// applyTransport points the HTTP stores at the server's credentials and TLS
+// settings.
+func applyTransport(settings transport.Settings) {
+	client := settings.HTTPClient(5 * time.Second)
+	stores.SharedAIModelStore().SetHTTPClient(client)
+	stores.SharedAgentStore().SetHTTPClient(client)
+	stores.SharedConversationStore().SetHTTPClient(client)
+}
+
+// isAuthFailure reports whether err means the server cannot be reached with
+// the configured credentials or certificates, so retrying will not help.
+func (m *Model) isAuthFailure(err error) bool {
+	return transport.IsAuthError(err) || (m.transportErr != nil && err == m.transportErr)
+}
+
+// reportAuthFailure surfaces a rejected or unusable credential in the logs tab,
+// the connection panel and, once per connection attempt, the chat.
+func (m *Model) reportAuthFailure(source string, err error) {
+	line := fmt.Sprintf("🔐 %s: %v", source, err)
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddError(line)
+		m.logsPage.SetAuthError(err.Error())
+	}
+	if m.authFailed {
+		return
+	}
+	m.authFailed = true
+	if chat := m.chatComponent(); chat != nil {
+		chat.AddMessage("system", fmt.Sprintf("🔐 Could not authenticate with server profile %s: %v\nFix the token or certificates for this profile, then press Ctrl+R to reconnect.", m.cfg.Profile, err))
+	}
+}
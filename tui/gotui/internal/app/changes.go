@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/changes"
+	"gotui/internal/components/chat"
+	"gotui/internal/stores"
+)
+
+type revertResult struct {
+	change stores.FileChange
+	method string
+	err    error
+}
+
+type changesRevertedMsg struct {
+	conversationID string
+	results        []revertResult
+}
+
+// revertChanges restores the requested files in the background.
+func (m *Model) revertChanges(msg chat.RevertChangesMsg) tea.Cmd {
+	store := stores.SharedChangeStore()
+	var targets []stores.FileChange
+	if len(msg.Paths) == 0 {
+		targets = store.Changes(msg.ConversationID)
+	} else {
+		for _, path := range msg.Paths {
+			if change, ok := store.Change(msg.ConversationID, path); ok {
+				targets = append(targets, change)
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	repo := m.gitRepo
+	root := stores.SharedApplicationStateStore().State().ProjectPath
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+		defer cancel()
+
+		results := make([]revertResult, 0, len(targets))
+		for _, change := range targets {
+			method, err := changes.Revert(ctx, repo, root, change, msg.Force)
+			results = append(results, revertResult{change: change, method: method, err: err})
+		}
+		return changesRevertedMsg{conversationID: msg.ConversationID, results: results}
+	}
+}
+
+func (m *Model) handleChangesReverted(msg changesRevertedMsg) tea.Cmd {
+	store := stores.SharedChangeStore()
+	chatComp := m.chatComponent()
+
+	var reverted, failed []string
+	for _, result := range msg.results {
+		name := result.change.Display
+		switch {
+		case result.err == nil:
+			store.Remove(msg.conversationID, result.change.Path)
+			reverted = append(reverted, fmt.Sprintf("%s (%s)", name, result.method))
+			m.logsPage.LogsPanel().AddLine(fmt.Sprintf("↩️  Reverted %s from %s", name, result.method))
+		case errors.Is(result.err, changes.ErrModifiedSince):
+			failed = append(failed, fmt.Sprintf("%s was edited after the agent's change; use /changes and press f to force", name))
+		default:
+			failed = append(failed, fmt.Sprintf("%s: %v", name, result.err))
+			m.logsPage.LogsPanel().AddError(fmt.Sprintf("❌ Revert %s failed: %v", name, result.err))
+		}
+	}
+
+	if chatComp != nil {
+		if len(reverted) > 0 {
+			chatComp.AddMessage("system", "↩️  Reverted "+strings.Join(reverted, ", "))
+		}
+		for _, failure := range failed {
+			chatComp.AddMessage("system", "⚠️  "+failure)
+		}
+		chatComp.RefreshChanges()
+	}
+	return m.refreshGitPanels()
+}
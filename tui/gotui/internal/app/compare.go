@@ -0,0 +1,87 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/components/chat"
+	"gotui/internal/messaging/messagesender"
+	"gotui/internal/stores"
+)
+
+type compareSentMsg struct {
+	conversationID string
+	// source and attached are the conversation the prompt was typed in and
+	// the files attached to it.
+	source   string
+	attached []string
+	err      error
+}
+
+// sendCompare sends a compared prompt to every target on its own thread,
+// routed to the conversation of the model that answers it. Routes end with
+// the run or with compare mode.
+func (m *Model) sendCompare(msg chat.CompareSubmitMsg) tea.Cmd {
+	if m.wsClient == nil || !m.wsClient.IsConnected() || m.messageSender == nil || m.messageHandler == nil {
+		errText := "❌ Not connected to server. Press Ctrl+R to retry."
+		if chat := m.chatComponent(); chat != nil {
+			for _, target := range msg.Targets {
+				chat.AddConversationMessage(target.ConversationID, "error", errText)
+			}
+		}
+		if m.logsPage != nil {
+			m.logsPage.LogsPanel().AddError(errText)
+		}
+		return nil
+	}
+
+	m.compareSeq++
+	sender := m.messageSender.Bound()
+	cmds := make([]tea.Cmd, 0, len(msg.Targets))
+	for _, target := range msg.Targets {
+		conversationID := target.ConversationID
+		threadID := fmt.Sprintf("gotui-compare-%d-%s", m.compareSeq, conversationID)
+		m.messageHandler.Route(threadID, conversationID)
+		m.compareThreads = append(m.compareThreads, threadID)
+		req := messagesender.Request{
+			Content:  msg.Content,
+			ThreadID: threadID,
+			Agent:    target.Agent,
+			Model:    target.Model,
+			Context:  target.Context,
+		}
+		cmds = append(cmds, func() tea.Msg {
+			_, err := sender.SendRequest(req)
+			return compareSentMsg{conversationID: conversationID, source: msg.ConversationID, attached: msg.Attached, err: err}
+		})
+	}
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddLine(fmt.Sprintf("⚖️ Sending prompt to %d models", len(msg.Targets)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// releaseCompareThreads stops routing replies to the compared conversations
+// once compare mode ends.
+func (m *Model) releaseCompareThreads() {
+	for _, threadID := range m.compareThreads {
+		m.messageHandler.Release(threadID)
+	}
+	m.compareThreads = nil
+}
+
+func (m *Model) handleCompareSent(msg compareSentMsg) {
+	if msg.err == nil {
+		// Attachments went out with the first prompt that was sent.
+		stores.SharedContextStore().DetachSent(msg.source, msg.attached)
+		return
+	}
+	errText := fmt.Sprintf("❌ Failed to send message: %v", msg.err)
+	if chat := m.chatComponent(); chat != nil {
+		chat.AddConversationMessage(msg.conversationID, "error", errText)
+	}
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddError(errText)
+	}
+}
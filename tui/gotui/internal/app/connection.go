@@ -8,34 +8,42 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 
+	"gotui/internal/components/chat"
 	"gotui/internal/components/chatcomponents"
 	"gotui/internal/logging"
+	"gotui/internal/messaging/messagesender"
 	"gotui/internal/stores"
 )
 
 type connectMsg struct {
-	success bool
-	err     error
+	generation int
+	success    bool
+	err        error
 }
 
-type tryConnectMsg struct{}
+type tryConnectMsg struct {
+	generation int
+}
 
 type sendUserMessageResult struct {
-	err error
+	conversationID string
+	attached       []string
+	err            error
 }
 
 type modelFetchResult struct {
-	options []chatcomponents.ModelOption
-	err     error
+	generation int
+	options    []chatcomponents.ModelOption
+	err        error
 }
 
 type agentFetchResult struct {
-	options []stores.AgentOption
-	err     error
+	generation int
+	options    []stores.AgentOption
+	err        error
 }
 
 func (m *Model) Init() tea.Cmd {
-	logging.Printf("Init() called")
 	m.logsPage.LogsPanel().AddLine("🚀 Initializing Codebolt Go TUI...")
 
 	var cmds []tea.Cmd
@@ -45,18 +53,21 @@ func (m *Model) Init() tea.Cmd {
 
 	delay := 1 * time.Second
 
+	generation := m.connGeneration
 	cmds = append(cmds, tea.Tick(delay, func(time.Time) tea.Msg {
-		return tryConnectMsg{}
+		return tryConnectMsg{generation: generation}
 	}))
 
 	cmds = append(cmds, m.fetchModelOptions(), m.fetchAgentOptions())
+	cmds = append(cmds, tea.RequestBackgroundColor, m.watchThemes())
+	cmds = append(cmds, m.refreshGitPanels(), m.watchGit())
+	cmds = append(cmds, waitNotification(m.notifications), waitRegistryChange(m.registryChanges))
+	cmds = append(cmds, waitImagePreview())
 
 	termWidth, termHeight := getTerminalSize()
-	logging.Printf("Init: Using terminal size: %dx%d", termWidth, termHeight)
-
 	m.width = termWidth
 	m.height = termHeight
-	logging.Printf("Init: Set model dimensions to %dx%d", m.width, m.height)
+	logging.Debug("model initialized", "width", m.width, "height", m.height)
 
 	m.updateAllComponents()
 
@@ -64,16 +75,26 @@ func (m *Model) Init() tea.Cmd {
 }
 
 func (m *Model) tryConnect() tea.Cmd {
+	client, generation := m.wsClient, m.connGeneration
+	if err := m.transportErr; err != nil {
+		return func() tea.Msg {
+			return connectMsg{generation: generation, err: err}
+		}
+	}
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		err := m.wsClient.Connect(ctx)
-		return connectMsg{success: err == nil, err: err}
+		err := client.Connect(ctx)
+		return connectMsg{generation: generation, success: err == nil, err: err}
 	}
 }
 
 func (m *Model) fetchModelOptions() tea.Cmd {
+	protocol, host, port, generation := m.cfg.Protocol, m.cfg.Host, m.cfg.Port, m.connGeneration
+	if err := m.transportErr; err != nil {
+		return func() tea.Msg { return modelFetchResult{generation: generation, err: err} }
+	}
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -82,16 +103,20 @@ func (m *Model) fetchModelOptions() tea.Cmd {
 			m.modelStore = stores.SharedAIModelStore()
 		}
 
-		options, err := m.modelStore.Fetch(ctx, m.cfg.Protocol, m.cfg.Host, m.cfg.Port)
+		options, err := m.modelStore.Fetch(ctx, protocol, host, port)
 		if err != nil {
-			return modelFetchResult{err: err}
+			return modelFetchResult{generation: generation, err: err}
 		}
 
-		return modelFetchResult{options: options}
+		return modelFetchResult{generation: generation, options: options}
 	}
 }
 
 func (m *Model) fetchAgentOptions() tea.Cmd {
+	protocol, host, port, generation := m.cfg.Protocol, m.cfg.Host, m.cfg.Port, m.connGeneration
+	if err := m.transportErr; err != nil {
+		return func() tea.Msg { return agentFetchResult{generation: generation, err: err} }
+	}
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -100,23 +125,25 @@ func (m *Model) fetchAgentOptions() tea.Cmd {
 			m.agentStore = stores.SharedAgentStore()
 		}
 
-		options, err := m.agentStore.Fetch(ctx, m.cfg.Protocol, m.cfg.Host, m.cfg.Port)
+		options, err := m.agentStore.Fetch(ctx, protocol, host, port)
 		if err != nil {
-			return agentFetchResult{err: err}
+			return agentFetchResult{generation: generation, err: err}
 		}
 
-		return agentFetchResult{options: options}
+		return agentFetchResult{generation: generation, options: options}
 	}
 }
 
-func (m *Model) sendUserMessage(content string) tea.Cmd {
+func (m *Model) sendUserMessage(msg chat.SubmitMsg) tea.Cmd {
+	sender := m.messageSender.Bound()
 	return func() tea.Msg {
-		if m.messageSender == nil {
+		if sender == nil {
 			return sendUserMessageResult{err: errors.New("message sender not initialized")}
 		}
-		if err := m.messageSender.Send(content); err != nil {
+		req := messagesender.Request{Content: msg.Content, Context: msg.Context, Agent: msg.Agent, Model: msg.Model}
+		if _, err := sender.SendRequest(req); err != nil {
 			return sendUserMessageResult{err: err}
 		}
-		return sendUserMessageResult{}
+		return sendUserMessageResult{conversationID: msg.ConversationID, attached: msg.Attached}
 	}
 }
@@ -1,36 +1,189 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
-	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/layout/panels"
+	"gotui/internal/layout/tabpages"
 )
 
-func (m *Model) refreshGitPanels() {
+const (
+	gitWatchInterval  = 2 * time.Second
+	gitCommandTimeout = 15 * time.Second
+	recentCommitCount = 5
+)
+
+type gitWatchMsg struct{}
+
+type gitStatusMsg struct {
+	status    gitrepo.Status
+	commits   []string
+	signature string
+	err       error
+}
+
+type gitDiffMsg struct {
+	item panels.GitStatusItem
+	diff gitrepo.FileDiff
+	err  error
+}
+
+type gitActionMsg struct {
+	req     tabpages.GitRequestMsg
+	summary string
+	err     error
+}
+
+// openGitRepo locates the repository for the project, if there is one.
+func openGitRepo(projectPath string) (*gitrepo.Repo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+	defer cancel()
+	return gitrepo.Open(ctx, projectPath)
+}
+
+// refreshGitPanels reloads the Git tab in the background.
+func (m *Model) refreshGitPanels() tea.Cmd {
 	if m.gitPage == nil {
-		return
+		return nil
+	}
+	if m.gitRepo == nil {
+		m.gitPage.SetStatusError("Not a git repository.")
+		return nil
 	}
+	m.gitSignature = ""
+	return m.loadGitStatus()
+}
 
-	status := m.runGitCommand("status", "--short", "--branch")
-	commits := m.runGitCommand("log", "--oneline", "-n", "5")
+func (m *Model) loadGitStatus() tea.Cmd {
+	repo := m.gitRepo
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+		defer cancel()
 
-	m.gitPage.Refresh(status, commits)
+		status, err := repo.Status(ctx)
+		if err != nil {
+			return gitStatusMsg{err: err}
+		}
+		commits, err := repo.RecentCommits(ctx, recentCommitCount)
+		if err != nil {
+			return gitStatusMsg{err: err}
+		}
+		return gitStatusMsg{status: status, commits: commits, signature: repo.Signature(status)}
+	}
 }
 
-func (m *Model) runGitCommand(args ...string) []string {
-	cmd := exec.Command("git", args...)
-	output, err := cmd.CombinedOutput()
-	trimmed := strings.TrimSpace(string(output))
-	if err != nil {
-		if trimmed == "" {
-			return []string{fmt.Sprintf("Error: %v", err)}
+// watchGit polls the working tree while the Git tab is visible so edits made
+// outside gotui show up without a manual refresh.
+func (m *Model) watchGit() tea.Cmd {
+	return tea.Tick(gitWatchInterval, func(time.Time) tea.Msg { return gitWatchMsg{} })
+}
+
+func (m *Model) handleGitStatus(msg gitStatusMsg) tea.Cmd {
+	if msg.err != nil {
+		m.gitSignature = ""
+		m.gitPage.SetStatusError(fmt.Sprintf("git status failed: %v", msg.err))
+		return nil
+	}
+	if msg.signature == m.gitSignature {
+		return nil
+	}
+	m.gitSignature = msg.signature
+	m.gitPage.SetCommits(msg.commits)
+	return m.gitPage.SetStatus(msg.status)
+}
+
+// runGitRequest performs an operation requested by the Git tab.
+func (m *Model) runGitRequest(req tabpages.GitRequestMsg) tea.Cmd {
+	if m.gitRepo == nil {
+		return nil
+	}
+	switch req.Op {
+	case tabpages.GitOpStatus:
+		return m.refreshGitPanels()
+	case tabpages.GitOpDiff:
+		return m.loadGitDiff(req.Item)
+	case tabpages.GitOpDraftCommit:
+		return m.startGitDraft(false)
+	case tabpages.GitOpDraftPR:
+		return m.startGitDraft(true)
+	case tabpages.GitOpLog, tabpages.GitOpShow, tabpages.GitOpShowFile, tabpages.GitOpBlame:
+		return m.loadGitHistory(req)
+	}
+
+	repo := m.gitRepo
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+		defer cancel()
+
+		path := req.Item.Entry.Path
+		hunk := req.Patch != ""
+		var (
+			summary string
+			err     error
+		)
+		switch req.Op {
+		case tabpages.GitOpStage:
+			if hunk {
+				err = repo.ApplyPatch(ctx, req.Patch, true, false)
+				summary = "Staged hunk of " + path
+			} else {
+				err = repo.Stage(ctx, path)
+				summary = "Staged " + path
+			}
+		case tabpages.GitOpUnstage:
+			if hunk {
+				err = repo.ApplyPatch(ctx, req.Patch, true, true)
+				summary = "Unstaged hunk of " + path
+			} else {
+				err = repo.Unstage(ctx, path)
+				summary = "Unstaged " + path
+			}
+		case tabpages.GitOpDiscard:
+			if hunk {
+				err = repo.ApplyPatch(ctx, req.Patch, false, true)
+				summary = "Discarded hunk of " + path
+			} else {
+				err = repo.Discard(ctx, req.Item.Entry)
+				summary = "Discarded changes to " + path
+			}
+		case tabpages.GitOpStageAll:
+			err = repo.Stage(ctx)
+			summary = "Staged all changes"
+		case tabpages.GitOpCommit:
+			var commit string
+			commit, err = repo.Commit(ctx, req.Message)
+			summary = "Committed " + commit
 		}
-		lines := strings.Split(trimmed, "\n")
-		lines = append(lines, fmt.Sprintf("Error: %v", err))
-		return lines
+		return gitActionMsg{req: req, summary: summary, err: err}
+	}
+}
+
+func (m *Model) loadGitDiff(item panels.GitStatusItem) tea.Cmd {
+	repo := m.gitRepo
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+		defer cancel()
+
+		diff, err := repo.Diff(ctx, item.Entry, item.Staged)
+		return gitDiffMsg{item: item, diff: diff, err: err}
+	}
+}
+
+func (m *Model) handleGitAction(msg gitActionMsg) tea.Cmd {
+	if msg.err != nil {
+		m.gitPage.SetNotice(fmt.Sprintf("❌ %v", msg.err))
+		m.logsPage.LogsPanel().AddError(fmt.Sprintf("❌ Git: %v", msg.err))
+		return nil
 	}
-	if trimmed == "" {
-		return []string{"(no output)"}
+	if msg.req.Op == tabpages.GitOpCommit {
+		m.gitPage.CommitDone()
 	}
-	return strings.Split(trimmed, "\n")
+	m.gitPage.SetNotice("✅ " + msg.summary)
+	m.logsPage.LogsPanel().AddLine("🌿 Git: " + msg.summary)
+	return m.refreshGitPanels()
 }
@@ -0,0 +1,296 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/messaging/messagehandler"
+	"gotui/internal/messaging/messagesender"
+	"gotui/internal/stores"
+)
+
+const (
+	// draftReplyTimeout is how long a draft waits for the next reply before
+	// giving up on a run that never stops.
+	draftReplyTimeout = 90 * time.Second
+	draftTickInterval = time.Second
+	// maxDraftPatchBytes bounds the diff sent to the agent.
+	maxDraftPatchBytes = 48 * 1024
+)
+
+// gitDraft tracks an agent-written commit message or PR description that is
+// streaming into the Git tab's editor.
+type gitDraft struct {
+	id        int
+	pr        bool
+	threadID  string
+	replies   chan messagehandler.Reply
+	done      chan struct{}
+	text      string
+	lastReply time.Time
+}
+
+type gitDraftChangesMsg struct {
+	id      int
+	pr      bool
+	changes gitrepo.Changes
+	err     error
+}
+
+type gitDraftSentMsg struct {
+	id  int
+	err error
+}
+
+type gitDraftReplyMsg struct {
+	id    int
+	reply messagehandler.Reply
+}
+
+type gitDraftTickMsg struct {
+	id int
+}
+
+// startGitDraft collects the changes to describe; the request is sent once
+// they are loaded.
+func (m *Model) startGitDraft(pr bool) tea.Cmd {
+	m.finishGitDraft("")
+	m.gitDraftSeq++
+	id := m.gitDraftSeq
+	repo := m.gitRepo
+	m.gitPage.SetNotice("Collecting changes…")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+		defer cancel()
+
+		var (
+			changes gitrepo.Changes
+			err     error
+		)
+		if pr {
+			changes, err = repo.BranchChanges(ctx)
+		} else {
+			changes, err = repo.StagedChanges(ctx)
+		}
+		return gitDraftChangesMsg{id: id, pr: pr, changes: changes, err: err}
+	}
+}
+
+func (m *Model) handleGitDraftChanges(msg gitDraftChangesMsg) tea.Cmd {
+	if msg.id != m.gitDraftSeq || !m.gitPage.Drafting() {
+		return nil
+	}
+	if msg.err != nil {
+		switch {
+		case errors.Is(msg.err, gitrepo.ErrNothingStaged):
+			m.gitPage.DraftDone("Nothing staged to describe")
+		case errors.Is(msg.err, gitrepo.ErrNoBase):
+			m.gitPage.DraftDone("❌ No main, master or upstream branch to compare against")
+		default:
+			m.gitPage.DraftDone(fmt.Sprintf("❌ %v", msg.err))
+		}
+		return nil
+	}
+	if m.messageSender == nil || m.messageHandler == nil {
+		m.gitPage.DraftDone("❌ Not connected to the agent server")
+		return nil
+	}
+
+	state := stores.SharedApplicationStateStore().State()
+	draft := &gitDraft{
+		id:        msg.id,
+		pr:        msg.pr,
+		threadID:  fmt.Sprintf("gotui-git-draft-%d-%d", time.Now().UnixNano(), msg.id),
+		replies:   make(chan messagehandler.Reply, 64),
+		done:      make(chan struct{}),
+		lastReply: time.Now(),
+	}
+	replies := draft.replies
+	m.messageHandler.Intercept(draft.threadID, func(reply messagehandler.Reply) {
+		select {
+		case replies <- reply:
+		default:
+		}
+	})
+
+	m.gitDraft = draft
+
+	sender := m.messageSender.Bound()
+	req := messagesender.Request{
+		Content:  draftPrompt(msg.changes, msg.pr),
+		ThreadID: draft.threadID,
+		Agent:    state.SelectedAgent,
+		Model:    state.SelectedModel,
+	}
+	send := func() tea.Msg {
+		_, err := sender.SendRequest(req)
+		return gitDraftSentMsg{id: draft.id, err: err}
+	}
+
+	agent := "the agent"
+	if state.SelectedAgent != nil && state.SelectedAgent.Name != "" {
+		agent = state.SelectedAgent.Name
+	}
+	what := "commit message"
+	if msg.pr {
+		what = "pull request description"
+	}
+	m.gitPage.SetNotice(fmt.Sprintf("✨ Drafting %s with %s…", what, agent))
+	m.logsPage.LogsPanel().AddLine(fmt.Sprintf("✨ Git: drafting %s with %s", what, agent))
+	return tea.Batch(send, waitGitDraftReply(draft), gitDraftTick(draft.id))
+}
+
+func (m *Model) handleGitDraftSent(msg gitDraftSentMsg) {
+	if msg.err == nil || m.gitDraft == nil || m.gitDraft.id != msg.id {
+		return
+	}
+	m.finishGitDraft(fmt.Sprintf("❌ %v", msg.err))
+}
+
+func waitGitDraftReply(draft *gitDraft) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case reply := <-draft.replies:
+			return gitDraftReplyMsg{id: draft.id, reply: reply}
+		case <-draft.done:
+			return nil
+		}
+	}
+}
+
+func gitDraftTick(id int) tea.Cmd {
+	return tea.Tick(draftTickInterval, func(time.Time) tea.Msg { return gitDraftTickMsg{id: id} })
+}
+
+func (m *Model) handleGitDraftReply(msg gitDraftReplyMsg) tea.Cmd {
+	draft := m.gitDraft
+	if draft == nil || draft.id != msg.id {
+		return nil
+	}
+	if !m.gitPage.Drafting() {
+		m.finishGitDraft("")
+		return nil
+	}
+	draft.lastReply = time.Now()
+	switch msg.reply.Type {
+	case "error":
+		m.finishGitDraft(fmt.Sprintf("❌ Agent error: %s", strings.TrimSpace(msg.reply.Content)))
+		return nil
+	case "ai":
+		// Each message is complete; the agent's last one is its answer.
+		if text := cleanDraft(msg.reply.Content); text != "" && (!msg.reply.Stopped || draft.text == "") {
+			draft.text = text
+			m.gitPage.SetDraft(text)
+		}
+	}
+	if msg.reply.Stopped {
+		switch {
+		case draft.text == "":
+			m.finishGitDraft(fmt.Sprintf("⚠️  The agent finished without a draft; try again with %s", m.keyMap.GitDraft.Help().Key))
+		case draft.pr:
+			m.finishGitDraft(fmt.Sprintf("✅ Draft ready • %s to copy", m.keyMap.GitCommit.Help().Key))
+		default:
+			m.finishGitDraft(fmt.Sprintf("✅ Draft ready • review, then %s to commit", m.keyMap.GitCommit.Help().Key))
+		}
+		return nil
+	}
+	return waitGitDraftReply(draft)
+}
+
+func (m *Model) handleGitDraftTick(msg gitDraftTickMsg) tea.Cmd {
+	draft := m.gitDraft
+	if draft == nil || draft.id != msg.id {
+		return nil
+	}
+	switch {
+	case !m.gitPage.Drafting():
+		m.finishGitDraft("")
+		return nil
+	case time.Since(draft.lastReply) >= draftReplyTimeout && draft.text == "":
+		m.finishGitDraft(fmt.Sprintf("⚠️  The agent did not reply; try again with %s", m.keyMap.GitDraft.Help().Key))
+		return nil
+	case time.Since(draft.lastReply) >= draftReplyTimeout:
+		m.finishGitDraft("⚠️  The agent stopped replying; the draft may be incomplete")
+		return nil
+	}
+	return gitDraftTick(draft.id)
+}
+
+// finishGitDraft stops routing replies to the editor. An empty notice leaves
+// the page's draft state alone, e.g. when the user already took over.
+func (m *Model) finishGitDraft(notice string) {
+	draft := m.gitDraft
+	if draft == nil {
+		return
+	}
+	m.gitDraft = nil
+	if m.messageHandler != nil {
+		m.messageHandler.Release(draft.threadID)
+	}
+	close(draft.done)
+	if notice != "" {
+		m.gitPage.DraftDone(notice)
+	}
+}
+
+// cleanDraft strips the code fence agents like to wrap their answer in.
+func cleanDraft(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		} else {
+			text = ""
+		}
+		text = strings.TrimSuffix(strings.TrimRight(text, " \n"), "```")
+	}
+	return strings.TrimSpace(text)
+}
+
+func draftPrompt(changes gitrepo.Changes, pr bool) string {
+	var b strings.Builder
+	if pr {
+		b.WriteString("Write a pull request description for the branch below. Start with a one-line title, " +
+			"then a blank line, a short summary of what the change does and why, and a list of notable changes. " +
+			"Reply with the description only, in Markdown, without commentary.\n\n")
+	} else {
+		b.WriteString("Write a git commit message for the staged changes below. Use an imperative summary line " +
+			"of at most 72 characters, then a blank line and a short body explaining what changed and why when " +
+			"it is not obvious from the summary. Reply with the commit message only, without code fences or commentary.\n\n")
+	}
+	if changes.Branch != "" {
+		fmt.Fprintf(&b, "Branch: %s\n", changes.Branch)
+	}
+	if changes.Base != "" {
+		fmt.Fprintf(&b, "Base: %s\n", changes.Base)
+	}
+	if len(changes.Commits) > 0 {
+		b.WriteString("\nCommits:\n")
+		for _, commit := range changes.Commits {
+			fmt.Fprintf(&b, "- %s\n", commit)
+		}
+	}
+	fmt.Fprintf(&b, "\nFiles changed:\n%s\n", strings.TrimRight(changes.Stat, "\n"))
+
+	patch := changes.Patch
+	truncated := 0
+	if len(patch) > maxDraftPatchBytes {
+		cut := strings.LastIndexByte(patch[:maxDraftPatchBytes], '\n')
+		if cut < 0 {
+			cut = maxDraftPatchBytes
+		}
+		truncated = len(patch) - cut
+		patch = patch[:cut]
+	}
+	fmt.Fprintf(&b, "\nDiff:\n%s\n", strings.TrimRight(patch, "\n"))
+	if truncated > 0 {
+		fmt.Fprintf(&b, "… diff truncated (%d more bytes); rely on the file summary above for the rest.\n", truncated)
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/layout/tabpages"
+)
+
+type gitLogMsg struct {
+	req   tabpages.GitRequestMsg
+	lines []gitrepo.LogLine
+	err   error
+}
+
+type gitShowMsg struct {
+	rev    string
+	detail gitrepo.CommitDetail
+	err    error
+}
+
+type gitShowFileMsg struct {
+	rev  string
+	path string
+	diff gitrepo.FileDiff
+	err  error
+}
+
+type gitBlameMsg struct {
+	rev   string
+	path  string
+	lines []gitrepo.BlameLine
+	err   error
+}
+
+// loadGitHistory runs the read-only history requests of the Git tab.
+func (m *Model) loadGitHistory(req tabpages.GitRequestMsg) tea.Cmd {
+	repo := m.gitRepo
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), gitCommandTimeout)
+		defer cancel()
+
+		switch req.Op {
+		case tabpages.GitOpLog:
+			lines, err := repo.Log(ctx, gitrepo.LogOptions{Path: req.Path, Skip: req.Skip, Limit: tabpages.GitLogPageSize})
+			return gitLogMsg{req: req, lines: lines, err: err}
+		case tabpages.GitOpShow:
+			detail, err := repo.Show(ctx, req.Rev)
+			return gitShowMsg{rev: req.Rev, detail: detail, err: err}
+		case tabpages.GitOpShowFile:
+			diff, err := repo.ShowFile(ctx, req.Rev, gitrepo.FileStat{Path: req.Path, OrigPath: req.OrigPath})
+			return gitShowFileMsg{rev: req.Rev, path: req.Path, diff: diff, err: err}
+		case tabpages.GitOpBlame:
+			lines, err := repo.Blame(ctx, req.Rev, req.Path)
+			return gitBlameMsg{rev: req.Rev, path: req.Path, lines: lines, err: err}
+		}
+		return nil
+	}
+}
+
+func (m *Model) handleGitHistory(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case gitLogMsg:
+		if msg.err != nil {
+			m.gitPage.SetLogError(msg.req, fmt.Sprintf("git log failed: %v", msg.err))
+			return nil
+		}
+		return m.gitPage.SetLog(msg.req, msg.lines)
+	case gitShowMsg:
+		if msg.err != nil {
+			m.gitPage.SetCommitError(msg.rev, fmt.Sprintf("git show failed: %v", msg.err))
+			return nil
+		}
+		return m.gitPage.SetCommitDetail(msg.detail)
+	case gitShowFileMsg:
+		if msg.err != nil {
+			m.gitPage.SetCommitFileDiffError(msg.rev, msg.path, fmt.Sprintf("git show failed: %v", msg.err))
+		} else {
+			m.gitPage.SetCommitFileDiff(msg.rev, msg.path, msg.diff)
+		}
+	case gitBlameMsg:
+		if msg.err != nil {
+			m.gitPage.SetBlameError(msg.rev, msg.path, fmt.Sprintf("git blame failed: %v", msg.err))
+		} else {
+			m.gitPage.SetBlame(msg.rev, msg.path, msg.lines)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -10,12 +11,13 @@ import (
 	"golang.org/x/term"
 
 	"gotui/internal/logging"
+	"gotui/internal/stores"
 	"gotui/internal/styles"
 )
 
 func getTerminalSize() (int, int) {
 	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
-		logging.Printf("Terminal size detected: %dx%d", width, height)
+		logging.Debug("terminal size detected", "width", width, "height", height)
 		return width, height
 	}
 
@@ -23,14 +25,14 @@ func getTerminalSize() (int, int) {
 		if width, err := strconv.Atoi(cols); err == nil {
 			if lines := os.Getenv("LINES"); lines != "" {
 				if height, err := strconv.Atoi(lines); err == nil {
-					logging.Printf("Terminal size from env: %dx%d", width, height)
+					logging.Debug("terminal size from env", "width", width, "height", height)
 					return width, height
 				}
 			}
 		}
 	}
 
-	logging.Printf("Using fallback terminal size: 120x40")
+	logging.Debug("using fallback terminal size", "width", 120, "height", 40)
 	return 120, 40
 }
 
@@ -89,6 +91,9 @@ func (m *Model) renderTabs() string {
 		if idx == int(m.activeTab) {
 			style = activeStyle
 		}
+		if tabID(idx) == tabLogs {
+			label += m.notificationBadge()
+		}
 		renderedTab := style.Render(label)
 		tabWidth := lipgloss.Width(renderedTab)
 		if tabWidth < 0 {
@@ -128,6 +133,30 @@ func (m *Model) renderTabs() string {
 		Render(bar)
 }
 
+// notificationBadge returns the unread count shown on the Logs tab, coloured
+// by the most severe unread notification.
+func (m *Model) notificationBadge() string {
+	unread, severity := 0, stores.SeverityInfo
+	for _, n := range stores.SharedNotificationStore().Notifications() {
+		if !n.Read {
+			unread++
+			severity = max(severity, n.Severity)
+		}
+	}
+	if unread == 0 {
+		return ""
+	}
+	theme := styles.CurrentTheme()
+	color := theme.Accent
+	switch severity {
+	case stores.SeverityWarning:
+		color = theme.Warning
+	case stores.SeverityError:
+		color = theme.Error
+	}
+	return lipgloss.NewStyle().Foreground(color).Bold(true).Render(fmt.Sprintf(" ●%d", unread))
+}
+
 func (m *Model) renderActiveTab(theme styles.Theme) string {
 	contentHeight := m.height - tabBarHeight
 	if contentHeight < 1 {
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/layout/panels"
+	"gotui/internal/layout/tabpages"
+	"gotui/internal/wsclient"
+)
+
+type logExportedMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// logEntryFromRecord converts a debug log record for the Logs panel. A
+// "source" attribute picks the panel source; other attributes become fields.
+func logEntryFromRecord(record slog.Record) panels.LogEntry {
+	entry := panels.LogEntry{
+		Time:    record.Time,
+		Level:   panels.LogError,
+		Source:  panels.LogSourceApp,
+		Message: record.Message,
+	}
+	switch {
+	case record.Level < slog.LevelInfo:
+		entry.Level = panels.LogDebug
+	case record.Level < slog.LevelWarn:
+		entry.Level = panels.LogInfo
+	case record.Level < slog.LevelError:
+		entry.Level = panels.LogWarn
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		value := attr.Value.Resolve().String()
+		if attr.Key == "source" {
+			switch value {
+			case panels.LogSourceWS, panels.LogSourceServer, panels.LogSourceAgent:
+				entry.Source = value
+				return true
+			}
+		}
+		entry.Fields = append(entry.Fields, panels.LogField{Key: attr.Key, Value: value})
+		return true
+	})
+	return entry
+}
+
+// serverLogEntry converts a streamed server log record for the Server Logs
+// panel.
+func serverLogEntry(record wsclient.LogRecord) panels.LogEntry {
+	source := panels.LogSourceServer
+	if record.Source == panels.LogSourceAgent {
+		source = panels.LogSourceAgent
+	}
+	entry := panels.LogEntry{
+		Time:    record.Time(),
+		Level:   panels.ParseLogLevel(record.Level),
+		Source:  source,
+		Message: record.Message,
+		Fields:  panels.FieldsFromMap(record.Data),
+	}
+	if record.Source != "" && record.Source != source {
+		entry.Fields = append([]panels.LogField{{Key: "module", Value: record.Source}}, entry.Fields...)
+	}
+	return entry
+}
+
+// subscribeServerLogs starts the server log stream once connected. The
+// server forgets the subscription when the connection drops, so it is sent
+// again on every connect.
+func (m *Model) subscribeServerLogs() tea.Cmd {
+	client, logs := m.wsClient, m.logsPage.LogsPanel()
+	return func() tea.Msg {
+		if err := client.SubscribeLogs("debug"); err != nil {
+			logs.AddWarning(fmt.Sprintf("⚠️  Could not subscribe to server logs: %v", err))
+		}
+		return nil
+	}
+}
+
+// exportLogs writes a log panel's filtered entries next to the debug log.
+func (m *Model) exportLogs(msg tabpages.LogExportMsg) tea.Cmd {
+	dir := m.cfg.LogExportDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	name := strings.ToLower(strings.ReplaceAll(msg.Panel, " ", "-"))
+	path := filepath.Join(dir, fmt.Sprintf("gotui-%s-%s.log", name, time.Now().Format("20060102-150405")))
+	return func() tea.Msg {
+		var b strings.Builder
+		for _, entry := range msg.Entries {
+			b.WriteString(entry.String())
+			b.WriteString("\n")
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return logExportedMsg{err: err}
+		}
+		err := os.WriteFile(path, []byte(b.String()), 0o644)
+		return logExportedMsg{path: path, count: len(msg.Entries), err: err}
+	}
+}
+
+func (m *Model) handleLogExported(msg logExportedMsg) {
+	if msg.err != nil {
+		m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️  %v", msg.err))
+		return
+	}
+	m.logsPage.LogsPanel().AddLine(fmt.Sprintf("💾 Exported %d log entries to %s", msg.count, msg.path))
+}
@@ -2,15 +2,26 @@ package app
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"gotui/internal/components/chat"
 	"gotui/internal/components/widgets"
+	"gotui/internal/config"
+	"gotui/internal/gitrepo"
+	"gotui/internal/imagepreview"
 	"gotui/internal/keybindings"
+	"gotui/internal/layout/panels"
 	"gotui/internal/layout/tabpages"
+	"gotui/internal/logging"
 	"gotui/internal/messaging/messagehandler"
 	"gotui/internal/messaging/messagesender"
+	"gotui/internal/promptcommands"
+	"gotui/internal/prompthistory"
 	"gotui/internal/stores"
+	"gotui/internal/styles"
+	"gotui/internal/transport"
+	"gotui/internal/windowlayout"
 	"gotui/internal/wsclient"
 )
 
@@ -24,6 +35,68 @@ type Config struct {
 	ProjectType string
 	Agent       stores.AgentSelection
 	Model       stores.ModelOption
+
+	// Theme is a preset name or "auto" to follow the terminal background.
+	Theme string
+	// LayoutMode is "panel" or "window".
+	LayoutMode string
+	// Keymap and KeymapSource describe the merged keybinding configuration.
+	Keymap       keybindings.File
+	KeymapSource string
+	// SettingsFile receives application settings changes; empty disables write-back.
+	SettingsFile string
+	// DefaultModel and DefaultAgent seed the application settings store.
+	DefaultModel *stores.ModelOption
+	DefaultAgent *stores.AgentSelection
+	// FavoriteModels and RecentModels seed the models pinned in the picker.
+	FavoriteModels []stores.ModelOption
+	RecentModels   []stores.ModelOption
+	// ProjectModels were pinned by the project config file alone; they are
+	// kept out of the user config file.
+	ProjectModels []stores.ModelOption
+	// StartupLog lines and StartupErrors are shown in the logs tab once the
+	// UI is ready.
+	StartupLog    []string
+	StartupErrors []string
+	// LogExportDir receives log exports from the Logs tab.
+	LogExportDir string
+	// Profiles lists the server profiles that can be switched to at runtime;
+	// Profile names the one Host, Port and Protocol were taken from.
+	Profiles []config.Profile
+	Profile  string
+	// Notifications configures alerts for agent events.
+	Notifications config.Notifications
+	// Usage sets prices and soft budgets for token usage tracking.
+	Usage config.Usage
+	// ImagePreviews selects how images are drawn in the chat.
+	ImagePreviews string
+}
+
+// activeProfile returns the profile the connection settings came from.
+func (cfg Config) activeProfile() config.Profile {
+	for _, profile := range cfg.Profiles {
+		if strings.EqualFold(profile.Name, cfg.Profile) {
+			return profile
+		}
+	}
+	return config.Profile{
+		Name:   config.DefaultProfile,
+		Server: config.Server{Host: cfg.Host, Port: cfg.Port, Protocol: cfg.Protocol},
+	}
+}
+
+func (cfg Config) wsConfig(settings transport.Settings) wsclient.Config {
+	return wsclient.Config{
+		Header:      settings.Credentials.Header(),
+		TLS:         settings.TLS,
+		Host:        cfg.Host,
+		Port:        cfg.Port,
+		Protocol:    cfg.Protocol,
+		TuiID:       cfg.TuiID,
+		ProjectPath: cfg.ProjectPath,
+		ProjectName: cfg.ProjectName,
+		ProjectType: cfg.ProjectType,
+	}
 }
 
 const tabBarHeight = 2
@@ -53,6 +126,11 @@ type Model struct {
 	helpBar  *widgets.HelpBar
 	logsPage *tabpages.LogsPage
 	gitPage  *tabpages.GitPage
+	// gitRepo is nil when the project is not inside a git work tree.
+	gitRepo      *gitrepo.Repo
+	gitSignature string
+	gitDraft     *gitDraft
+	gitDraftSeq  int
 
 	messageSender  *messagesender.Sender
 	messageHandler *messagehandler.Handler
@@ -61,6 +139,13 @@ type Model struct {
 	isRetrying  bool
 	lastError   string
 	chatFocused bool
+	// connGeneration increments on every profile switch so results from the
+	// previous server's connection attempts and fetches can be discarded.
+	connGeneration int
+	// transportErr holds a credential or certificate loading failure for the
+	// active profile; authFailed is set once the server rejects us.
+	transportErr error
+	authFailed   bool
 
 	width      int
 	height     int
@@ -72,6 +157,26 @@ type Model struct {
 
 	modelStore *stores.AIModelStore
 	agentStore *stores.AgentStore
+
+	themeDirs      []string
+	themeSignature string
+	themeChosen    bool
+
+	persistedSettings stores.ApplicationSettings
+
+	// notifications receives notifications added outside the update loop.
+	notifications <-chan stores.Notification
+	// registryChanges receives agent and model registry notifications;
+	// pendingRegistry collects them until the debounced refetch.
+	registryChanges chan registryChange
+	pendingRegistry registryChange
+	// compareThreads are the threads routed to compared conversations;
+	// compareSeq numbers them.
+	compareThreads []string
+	compareSeq     int
+	// terminalFocused is only meaningful once the terminal reported focus.
+	terminalFocused bool
+	focusReported   bool
 }
 
 func (m *Model) chatComponent() *chat.Chat {
@@ -80,16 +185,20 @@ func (m *Model) chatComponent() *chat.Chat {
 	}
 	return m.chatPage.Chat()
 }
+
+// SaveSession keeps the unsent prompts and the window layout for the next
+// session. It runs once the program has exited, however it ended.
+func (m *Model) SaveSession() {
+	if chat := m.chatComponent(); chat != nil {
+		chat.SaveDrafts()
+		chat.SaveWindowLayout()
+	}
+}
+
 func NewModel(cfg Config) *Model {
-	wsClient := wsclient.New(wsclient.Config{
-		Host:        cfg.Host,
-		Port:        cfg.Port,
-		Protocol:    cfg.Protocol,
-		TuiID:       cfg.TuiID,
-		ProjectPath: cfg.ProjectPath,
-		ProjectName: cfg.ProjectName,
-		ProjectType: cfg.ProjectType,
-	})
+	settings, transportErr := cfg.activeProfile().Transport()
+	applyTransport(settings)
+	wsClient := wsclient.New(cfg.wsConfig(settings))
 
 	stateStore := stores.SharedApplicationStateStore()
 	stateStore.SetConnectionInfo(cfg.Host, cfg.Port, cfg.Protocol)
@@ -98,28 +207,20 @@ func NewModel(cfg Config) *Model {
 	stateStore.SetSelectedAgent(&cfg.Agent)
 	stateStore.SetSelectedModel(&cfg.Model)
 
+	keyMap, keymapReport := keybindings.Apply(cfg.Keymap, keybindings.Report{Source: cfg.KeymapSource})
+
 	chatPage := tabpages.NewChatPage()
 	chatComp := chatPage.Chat()
 	helpBarComp := widgets.New()
+	helpBarComp.SetKeyMap(keyMap)
 	if chatComp != nil {
 		chatComp.SetHelpBar(helpBarComp)
 	}
 	logsPage := tabpages.NewLogsPage(wsClient, cfg.Host, cfg.Port)
 	gitPage := tabpages.NewGitPage()
+	gitPage.SetKeyMap(keyMap)
 	tabs := []string{"Chat", "Logs", "Git"}
 
-	wsClient.SetLogger(func(msg string) {
-		logsPage.LogsPanel().AddLine(fmt.Sprintf("[WS] %s", msg))
-	})
-
-	wsClient.OnNotification(func(n wsclient.Notification) {
-		notifLine := fmt.Sprintf("[%s] %s", n.Type, n.Action)
-		if n.Event != "" {
-			notifLine = fmt.Sprintf("[%s] %s", n.Type, n.Event)
-		}
-		logsPage.NotificationsPanel().AddLine(notifLine)
-	})
-
 	sender := messagesender.New(wsClient, cfg.Agent)
 
 	var handler *messagehandler.Handler
@@ -128,10 +229,12 @@ func NewModel(cfg Config) *Model {
 			if strings.TrimSpace(entry) == "" {
 				return
 			}
-			logsPage.LogsPanel().AddLine(entry)
+			logging.Warn(entry, "source", panels.LogSourceAgent)
 		})
-		wsClient.OnMessage(handler.HandleRaw)
 	}
+	logging.SetTee(func(record slog.Record) {
+		logsPage.LogsPanel().Add(logEntryFromRecord(record))
+	})
 
 	modelStore := stores.SharedAIModelStore()
 	agentStore := stores.SharedAgentStore()
@@ -153,18 +256,53 @@ func NewModel(cfg Config) *Model {
 		tabs:           tabs,
 		activeTab:      tabChat,
 		chatFocused:    true,
-		keyMap:         keybindings.DefaultKeyMap(),
+		keyMap:         keyMap,
 		messageSender:  sender,
 		messageHandler: handler,
 		modelStore:     modelStore,
 		agentStore:     agentStore,
+		themeDirs:      styles.ThemeDirs(cfg.ProjectPath),
+		transportErr:   transportErr,
+
+		registryChanges: make(chan registryChange, notificationBuffer),
 	}
+	m.wireWSClient(wsClient)
+	m.subscribeNotifications()
+	stores.SharedUsageStore().Configure(cfg.Usage.PriceTable(), stores.UsageBudgets{
+		Conversation: cfg.Usage.Budgets.Conversation,
+		Session:      cfg.Usage.Budgets.Session,
+	})
+	imagepreview.SetProtocol(cfg.ImagePreviews)
+	logsPage.SetAuth(settings.Credentials.Describe())
+	m.loadThemes()
+	m.applyStartupTheme()
 
 	if chatComp != nil {
 		chatComp.SetModelStore(modelStore)
 		chatComp.SetAgentStore(agentStore)
 		chatComp.SetPreferredAgent(cfg.Agent)
 		chatComp.SetPreferredModel(cfg.Model)
+		chatComp.SetKeyMap(keyMap)
+		chatComp.SetKeymapReport(keymapReport)
+		chatComp.SetServerProfiles(profileOptions(cfg.Profiles), cfg.Profile)
+		history, err := prompthistory.Open(prompthistory.PathFor(cfg.ProjectPath))
+		if err != nil {
+			logging.Warn("loading prompt history failed", "error", err)
+		}
+		chatComp.SetPromptHistory(history)
+		layouts, err := windowlayout.Open(windowlayout.PathFor(cfg.ProjectPath))
+		if err != nil {
+			logging.Warn("loading window layouts failed", "error", err)
+		}
+		chatComp.SetWindowLayouts(layouts)
+		promptCommands, errs := promptcommands.LoadFor(cfg.ProjectPath)
+		for _, err := range errs {
+			logging.Warn("loading custom command failed", "error", err)
+		}
+		chatComp.SetPromptCommands(promptCommands)
+		if cfg.LayoutMode == config.LayoutWindow {
+			chatComp.SetWindowMode(true)
+		}
 		chatComp.Focus()
 	}
 
@@ -172,6 +310,21 @@ func NewModel(cfg Config) *Model {
 	logsPage.LogsPanel().AddLine("🚀 Codebolt TUI Client initialized")
 	logsPage.LogsPanel().AddLine("🔧 WebSocket client created")
 	logsPage.LogsPanel().AddLine("🎨 UI components loaded")
+	for _, line := range cfg.StartupLog {
+		logsPage.LogsPanel().AddLine(line)
+	}
+	for _, line := range cfg.StartupErrors {
+		logsPage.LogsPanel().AddError(line)
+	}
+	for _, line := range keymapReport.Lines() {
+		logsPage.LogsPanel().AddLine(line)
+	}
+	for _, line := range keymapReport.ErrorLines() {
+		logsPage.LogsPanel().AddError(line)
+	}
+	for _, line := range keymapReport.WarningLines() {
+		logsPage.LogsPanel().AddWarning(line)
+	}
 	logsPage.LogsPanel().AddLine("")
 	logsPage.LogsPanel().AddLine("═══ AVAILABLE COMMANDS ═══")
 	logsPage.LogsPanel().AddLine("📖 read <file> - Read file content")
@@ -182,6 +335,9 @@ func NewModel(cfg Config) *Model {
 	logsPage.LogsPanel().AddLine("")
 	logsPage.LogsPanel().AddLine("📡 Client mode - connecting to server")
 	logsPage.LogsPanel().AddLine(fmt.Sprintf("🔗 Target server: %s:%d", cfg.Host, cfg.Port))
+	if len(cfg.Profiles) > 1 {
+		logsPage.LogsPanel().AddLine(fmt.Sprintf("🗂️  Server profile: %s (/profiles to switch)", cfg.Profile))
+	}
 	if cfg.Protocol != "" {
 		logsPage.LogsPanel().AddLine(fmt.Sprintf("🌐 Protocol: %s", strings.ToUpper(cfg.Protocol)))
 	}
@@ -195,7 +351,11 @@ func NewModel(cfg Config) *Model {
 	logsPage.LogsPanel().AddLine("🤖 Fetching available models from server...")
 	logsPage.LogsPanel().AddLine("🧭 Fetching available agents from server...")
 
-	m.refreshGitPanels()
+	m.bindSettingsPersistence()
+
+	if repo, err := openGitRepo(cfg.ProjectPath); err == nil {
+		m.gitRepo = repo
+	}
 
 	return m
 }
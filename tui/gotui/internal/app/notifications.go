@@ -0,0 +1,97 @@
+package app
+
+import (
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/notify"
+	"gotui/internal/stores"
+	"gotui/internal/wsclient"
+)
+
+// notificationBuffer bounds the notifications waiting for the update loop.
+// Overflow only skips alerts; the notification centre keeps everything.
+const notificationBuffer = 32
+
+// notificationMsg delivers a notification added on another goroutine.
+type notificationMsg struct {
+	notification stores.Notification
+}
+
+// serverNotification converts a server push for the notification centre.
+// File watcher events are frequent, so they are filed as already read.
+func serverNotification(n wsclient.Notification) stores.Notification {
+	title := n.Event
+	if title == "" {
+		title = n.Action
+	}
+	notification := stores.Notification{
+		Event:    notify.EventServer,
+		Severity: stores.SeverityInfo,
+		Title:    title,
+		Body:     n.Type,
+		Read:     n.Type == "fsnotify",
+	}
+	if title == "" {
+		notification.Title, notification.Body = n.Type, ""
+	}
+	if n.Timestamp > 0 {
+		notification.At = time.UnixMilli(n.Timestamp)
+	}
+	return notification
+}
+
+// subscribeNotifications forwards new notifications into the update loop.
+func (m *Model) subscribeNotifications() {
+	ch := make(chan stores.Notification, notificationBuffer)
+	m.notifications = ch
+	stores.SharedNotificationStore().Subscribe(func(n stores.Notification) {
+		select {
+		case ch <- n:
+		default:
+		}
+	})
+}
+
+func waitNotification(ch <-chan stores.Notification) tea.Cmd {
+	return func() tea.Msg {
+		return notificationMsg{notification: <-ch}
+	}
+}
+
+func (m *Model) handleNotification(msg notificationMsg) tea.Cmd {
+	m.markNotificationsSeen()
+	cmds := []tea.Cmd{waitNotification(m.notifications)}
+	if alert := m.notificationAlert(msg.notification); alert != "" {
+		cmds = append(cmds, tea.Raw(alert))
+	}
+	return tea.Batch(cmds...)
+}
+
+// notificationAlert returns the escape sequence that alerts the user about
+// n, or "" when the event is not opted in or the user is already looking.
+func (m *Model) notificationAlert(n stores.Notification) string {
+	settings := m.cfg.Notifications
+	if n.Read || !settings.Alerts(n.Event) {
+		return ""
+	}
+	// Without focus reports there is no telling whether the user looks.
+	if settings.OnlyUnfocused != nil && *settings.OnlyUnfocused && m.focusReported && m.terminalFocused {
+		return ""
+	}
+	method := settings.Alert
+	if method == "" || method == notify.MethodAuto {
+		method = notify.DetectMethod(os.Getenv)
+	}
+	return notify.Sequence(method, "Codebolt: "+n.Title, n.Body, os.Getenv)
+}
+
+// markNotificationsSeen marks the notification centre read while it is on
+// screen.
+func (m *Model) markNotificationsSeen() {
+	if m.activeTab == tabLogs && m.logsPage.ShowsNotifications() {
+		stores.SharedNotificationStore().MarkAllRead()
+	}
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/imagepreview"
+)
+
+// imagePreviewMsg reports that image previews finished loading.
+type imagePreviewMsg struct{}
+
+func waitImagePreview() tea.Cmd {
+	return func() tea.Msg {
+		<-imagepreview.Ready()
+		return imagePreviewMsg{}
+	}
+}
+
+// handleImagePreview redraws with the loaded previews and writes kitty
+// image data to the terminal once, outside the frame.
+func (m *Model) handleImagePreview() tea.Cmd {
+	cmds := []tea.Cmd{waitImagePreview()}
+	if data := imagepreview.Transmissions(); data != "" {
+		cmds = append(cmds, tea.Raw(data))
+	}
+	return tea.Batch(cmds...)
+}
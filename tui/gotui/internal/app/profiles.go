@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/components/chatcomponents"
+	"gotui/internal/config"
+	"gotui/internal/layout/panels"
+	"gotui/internal/logging"
+	"gotui/internal/stores"
+	"gotui/internal/wsclient"
+)
+
+func profileOptions(profiles []config.Profile) []chatcomponents.ServerProfileOption {
+	options := make([]chatcomponents.ServerProfileOption, 0, len(profiles))
+	for _, profile := range profiles {
+		options = append(options, chatcomponents.ServerProfileOption{
+			Name:     profile.Name,
+			Endpoint: profile.Endpoint(),
+			Secure:   profile.Secure(),
+			Auth:     hasCredentials(profile),
+		})
+	}
+	return options
+}
+
+// hasCredentials reports whether the profile is configured to authenticate;
+// a token that cannot be read still counts.
+func hasCredentials(profile config.Profile) bool {
+	creds, err := profile.Credentials()
+	return err != nil || !creds.IsZero()
+}
+
+// wireWSClient routes a websocket client's logs, notifications and messages
+// into the UI.
+func (m *Model) wireWSClient(client *wsclient.Client) {
+	logsPage := m.logsPage
+	client.SetLogger(func(msg string) {
+		logging.Info(msg, "source", panels.LogSourceWS)
+	})
+	client.OnLog(func(record wsclient.LogRecord) {
+		logsPage.ServerLogsPanel().Add(serverLogEntry(record))
+	})
+
+	client.OnNotification(func(n wsclient.Notification) {
+		if change := notificationRegistryChange(n); change != 0 {
+			m.queueRegistryChange(change)
+			return
+		}
+		stores.SharedNotificationStore().Add(serverNotification(n))
+	})
+
+	if m.messageHandler != nil {
+		client.OnMessage(m.messageHandler.HandleRaw)
+	}
+}
+
+// switchProfile tears down the current connection and connects to the named
+// server profile, re-fetching models and agents from the new server.
+func (m *Model) switchProfile(name string) tea.Cmd {
+	var target *config.Profile
+	for i := range m.cfg.Profiles {
+		if strings.EqualFold(m.cfg.Profiles[i].Name, name) {
+			target = &m.cfg.Profiles[i]
+			break
+		}
+	}
+	if target == nil {
+		m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️  Unknown server profile %q", name))
+		return nil
+	}
+
+	m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🔀 Switched to server profile %s (%s)", target.Name, target.Endpoint()))
+	return m.connectProfile(*target)
+}
+
+// connectProfile replaces the websocket client and HTTP transport with ones
+// for profile and starts connecting. Credentials are re-read, so it also serves
+// to retry after fixing a rejected token.
+func (m *Model) connectProfile(profile config.Profile) tea.Cmd {
+	if m.wsClient != nil {
+		if err := m.wsClient.Close(); err != nil {
+			m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️  Error closing previous connection: %v", err))
+		}
+	}
+
+	m.cfg.Profile = profile.Name
+	m.cfg.Host = profile.Host
+	m.cfg.Port = profile.Port
+	m.cfg.Protocol = profile.Protocol
+	m.connGeneration++
+	m.retryCount = 0
+	m.isRetrying = true
+	m.lastError = ""
+	m.authFailed = false
+
+	settings, err := profile.Transport()
+	m.transportErr = err
+	applyTransport(settings)
+
+	client := wsclient.New(m.cfg.wsConfig(settings))
+	m.wsClient = client
+	m.wireWSClient(client)
+	if m.messageSender != nil {
+		m.messageSender.SetClient(client)
+	}
+	m.logsPage.SetConnection(client, m.cfg.Host, m.cfg.Port)
+	m.logsPage.SetAuth(settings.Credentials.Describe())
+	m.logsPage.SetRetryInfo(m.retryCount, m.isRetrying, m.lastError)
+
+	stores.SharedApplicationStateStore().SetConnectionInfo(m.cfg.Host, m.cfg.Port, m.cfg.Protocol)
+	stores.SharedConversationStore().ConfigureRemoteSync(m.cfg.Protocol, m.cfg.Host, m.cfg.Port, m.cfg.ProjectPath)
+
+	if chat := m.chatComponent(); chat != nil {
+		chat.SetServerProfiles(profileOptions(m.cfg.Profiles), m.cfg.Profile)
+	}
+
+	m.logsPage.LogsPanel().AddLine("🔌 Attempting to connect...")
+	return tea.Batch(m.tryConnect(), m.fetchModelOptions(), m.fetchAgentOptions())
+}
@@ -0,0 +1,198 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/stores"
+	"gotui/internal/wsclient"
+)
+
+// registryChange says which server lists a notification invalidated.
+type registryChange int
+
+const (
+	registryAgents registryChange = 1 << iota
+	registryModels
+)
+
+// registryDebounce coalesces bursts of registry notifications, e.g. from
+// installing several agents, into one refetch.
+const registryDebounce = 500 * time.Millisecond
+
+// registryEvents maps the notification actions the server broadcasts when
+// its agent or model list changes to the lists to refetch.
+var registryEvents = map[string]registryChange{
+	"agents-changed": registryAgents,
+	"models-changed": registryModels,
+}
+
+// registryChangeMsg delivers a registry notification received on the
+// websocket goroutine.
+type registryChangeMsg struct {
+	change registryChange
+}
+
+// registryRefreshMsg fires once the debounce after a registry notification
+// has passed.
+type registryRefreshMsg struct{}
+
+type modelRefreshResult struct {
+	generation int
+	changed    bool
+	err        error
+}
+
+type agentRefreshResult struct {
+	generation int
+	changed    bool
+	err        error
+}
+
+// notificationRegistryChange reports which lists n invalidates, if any.
+func notificationRegistryChange(n wsclient.Notification) registryChange {
+	if n.Type != "notification" {
+		return 0
+	}
+	return registryEvents[n.Action]
+}
+
+// queueRegistryChange hands a registry change to the update loop without
+// blocking the websocket reader. Dropping one when the buffer is full loses
+// nothing, as the queued ones already trigger a refetch.
+func (m *Model) queueRegistryChange(change registryChange) {
+	select {
+	case m.registryChanges <- change:
+	default:
+	}
+}
+
+func waitRegistryChange(ch <-chan registryChange) tea.Cmd {
+	return func() tea.Msg {
+		return registryChangeMsg{change: <-ch}
+	}
+}
+
+// handleRegistryChange records what to refetch and starts the debounce
+// unless one is already running.
+func (m *Model) handleRegistryChange(msg registryChangeMsg) tea.Cmd {
+	cmds := []tea.Cmd{waitRegistryChange(m.registryChanges)}
+	if m.pendingRegistry == 0 {
+		cmds = append(cmds, tea.Tick(registryDebounce, func(time.Time) tea.Msg { return registryRefreshMsg{} }))
+	}
+	m.pendingRegistry |= msg.change
+	return tea.Batch(cmds...)
+}
+
+// refreshRegistry refetches the lists invalidated since the last refresh.
+func (m *Model) refreshRegistry() tea.Cmd {
+	change := m.pendingRegistry
+	m.pendingRegistry = 0
+	var cmds []tea.Cmd
+	if change&registryModels != 0 {
+		cmds = append(cmds, m.refreshModelOptions())
+	}
+	if change&registryAgents != 0 {
+		cmds = append(cmds, m.refreshAgentOptions())
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) refreshModelOptions() tea.Cmd {
+	protocol, host, port, generation := m.cfg.Protocol, m.cfg.Host, m.cfg.Port, m.connGeneration
+	if m.transportErr != nil {
+		return nil
+	}
+	if m.modelStore == nil {
+		m.modelStore = stores.SharedAIModelStore()
+	}
+	store := m.modelStore
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		changed, err := store.Refresh(ctx, protocol, host, port)
+		return modelRefreshResult{generation: generation, changed: changed, err: err}
+	}
+}
+
+func (m *Model) refreshAgentOptions() tea.Cmd {
+	protocol, host, port, generation := m.cfg.Protocol, m.cfg.Host, m.cfg.Port, m.connGeneration
+	if m.transportErr != nil {
+		return nil
+	}
+	if m.agentStore == nil {
+		m.agentStore = stores.SharedAgentStore()
+	}
+	store := m.agentStore
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		changed, err := store.Refresh(ctx, protocol, host, port)
+		return agentRefreshResult{generation: generation, changed: changed, err: err}
+	}
+}
+
+// handleModelRefresh passes a changed model list on to the chat and its
+// picker, moving conversations off models the server removed.
+func (m *Model) handleModelRefresh(msg modelRefreshResult) {
+	if msg.generation != m.connGeneration {
+		return
+	}
+	if m.isAuthFailure(msg.err) {
+		m.reportAuthFailure("Failed to refresh models", msg.err)
+		return
+	}
+	if msg.err != nil {
+		if m.logsPage != nil {
+			m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️ Failed to refresh models: %v", msg.err))
+		}
+		return
+	}
+	if !msg.changed {
+		return
+	}
+	var removed []string
+	if chat := m.chatComponent(); chat != nil {
+		chat.SetModelOptions(nil)
+		removed = chat.ReplaceRemovedModels()
+	}
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🔄 Models changed on the server, %d available", len(m.modelStore.Models())))
+		if len(removed) > 0 {
+			m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️  %s no longer offered by the server; switched to the default model", strings.Join(removed, ", ")))
+		}
+	}
+}
+
+// handleAgentRefresh passes a changed agent list on to the agent browser.
+func (m *Model) handleAgentRefresh(msg agentRefreshResult) {
+	if msg.generation != m.connGeneration {
+		return
+	}
+	if m.isAuthFailure(msg.err) {
+		m.reportAuthFailure("Failed to refresh agents", msg.err)
+		return
+	}
+	if msg.err != nil {
+		if m.logsPage != nil {
+			m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️ Failed to refresh agents: %v", msg.err))
+		}
+		return
+	}
+	if !msg.changed {
+		return
+	}
+	if chat := m.chatComponent(); chat != nil {
+		chat.RefreshAgents()
+	}
+	if m.logsPage == nil {
+		return
+	}
+	line := fmt.Sprintf("🔄 Agents changed on the server, %d available", len(m.agentStore.Agents()))
+	m.logsPage.AgentPanel().AddLine(line)
+	m.logsPage.LogsPanel().AddLine(line)
+}
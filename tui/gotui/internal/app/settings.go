@@ -0,0 +1,139 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"gotui/internal/config"
+	"gotui/internal/stores"
+	"gotui/internal/styles"
+)
+
+// applyStartupTheme applies the configured theme. "auto" leaves the choice to
+// terminal background detection.
+func (m *Model) applyStartupTheme() {
+	name := strings.TrimSpace(m.cfg.Theme)
+	if name == "" || strings.EqualFold(name, config.ThemeAuto) {
+		return
+	}
+	if styles.SetThemeByName(name) {
+		m.themeChosen = true
+		return
+	}
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️  Unknown theme %q, using %s", name, styles.CurrentThemeName()))
+	}
+}
+
+// bindSettingsPersistence seeds the settings store from the config files and
+// writes later changes back to the user config file.
+func (m *Model) bindSettingsPersistence() {
+	store := stores.SharedApplicationSettingsStore()
+	seed := store.Settings()
+	if m.cfg.DefaultModel != nil {
+		seed.DefaultModel = m.cfg.DefaultModel
+	}
+	if m.cfg.DefaultAgent != nil {
+		seed.DefaultAgent = m.cfg.DefaultAgent
+	}
+	if len(m.cfg.FavoriteModels) > 0 {
+		seed.FavoriteModels = m.cfg.FavoriteModels
+	}
+	if len(m.cfg.RecentModels) > 0 {
+		seed.RecentModels = m.cfg.RecentModels
+	}
+	store.Update(seed)
+	m.persistedSettings = store.Settings()
+
+	if m.cfg.SettingsFile == "" {
+		return
+	}
+	store.Subscribe(m.persistSettings)
+}
+
+func (m *Model) persistSettings(settings stores.ApplicationSettings) {
+	var keys []string
+	if !sameModel(settings.DefaultModel, m.persistedSettings.DefaultModel) {
+		keys = append(keys, config.DefaultsModel)
+	}
+	if !sameAgent(settings.DefaultAgent, m.persistedSettings.DefaultAgent) {
+		keys = append(keys, config.DefaultsAgent)
+	}
+	if !sameModels(settings.FavoriteModels, m.persistedSettings.FavoriteModels) {
+		keys = append(keys, config.DefaultsFavoriteModels)
+	}
+	if !sameModels(settings.RecentModels, m.persistedSettings.RecentModels) {
+		keys = append(keys, config.DefaultsRecentModels)
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	var defaults config.Defaults
+	if model := settings.DefaultModel; model != nil {
+		defaults.Model = &config.ModelRef{Name: model.Name, Provider: model.Provider}
+	}
+	if agent := settings.DefaultAgent; agent != nil {
+		defaults.Agent = &config.AgentRef{
+			ID:     agent.ID,
+			Name:   agent.Name,
+			Type:   agent.AgentType,
+			Detail: agent.AgentDetails,
+		}
+	}
+	defaults.FavoriteModels = m.userModelRefs(settings.FavoriteModels)
+	defaults.RecentModels = m.userModelRefs(settings.RecentModels)
+
+	if err := config.UpdateUserDefaults(m.cfg.SettingsFile, defaults, keys...); err != nil {
+		if m.logsPage != nil {
+			m.logsPage.LogsPanel().AddError(fmt.Sprintf("❌ Failed to save settings: %v", err))
+		}
+		return
+	}
+	m.persistedSettings = settings.Clone()
+	if m.logsPage != nil {
+		m.logsPage.LogsPanel().AddLine(fmt.Sprintf("💾 Settings saved to %s", m.cfg.SettingsFile))
+	}
+}
+
+func sameModel(a, b *stores.ModelOption) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name && a.Provider == b.Provider
+}
+
+func sameModels(a, b []stores.ModelOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !stores.SameModel(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// userModelRefs converts models for the user config file, leaving out those
+// only the project config file pinned.
+func (m *Model) userModelRefs(models []stores.ModelOption) []config.ModelRef {
+	var refs []config.ModelRef
+	for _, model := range models {
+		if slices.ContainsFunc(m.cfg.ProjectModels, func(project stores.ModelOption) bool {
+			return stores.SameModel(project, model)
+		}) {
+			continue
+		}
+		refs = append(refs, config.ModelRef{Name: model.Name, Provider: model.Provider})
+	}
+	return refs
+}
+
+func sameAgent(a, b *stores.AgentSelection) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
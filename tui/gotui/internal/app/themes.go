@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/configfile"
+	"gotui/internal/styles"
+)
+
+// themeWatchInterval controls how often theme directories are polled for edits.
+const themeWatchInterval = 2 * time.Second
+
+type themeWatchMsg struct{}
+
+// loadThemes (re)reads user and project theme files and reports any problems in
+// the logs tab. It returns true when the set of files changed since the last load.
+func (m *Model) loadThemes() bool {
+	signature := configfile.Signature(m.themeDirs...)
+	if signature == m.themeSignature {
+		return false
+	}
+	m.themeSignature = signature
+
+	presets, errs := styles.LoadThemeFiles(m.themeDirs...)
+	styles.SetCustomThemes(presets)
+
+	if m.logsPage != nil {
+		if len(presets) > 0 {
+			m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🎨 Loaded %d custom theme(s)", len(presets)))
+		}
+		for _, err := range errs {
+			m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️  Theme error: %v", err))
+		}
+	}
+	return true
+}
+
+func (m *Model) watchThemes() tea.Cmd {
+	return tea.Tick(themeWatchInterval, func(time.Time) tea.Msg {
+		return themeWatchMsg{}
+	})
+}
+
+// refreshThemedViews re-renders content that bakes theme colors into its output.
+func (m *Model) refreshThemedViews() {
+	if chat := m.chatComponent(); chat != nil {
+		chat.RefreshTheme()
+	}
+}
+
+func (m *Model) handleBackgroundColor(msg tea.BackgroundColorMsg) {
+	if m.themeChosen {
+		return
+	}
+	name := styles.SelectThemeForBackground(msg.IsDark())
+	if name == "" {
+		return
+	}
+	m.refreshThemedViews()
+	if m.logsPage != nil {
+		appearance := "light"
+		if msg.IsDark() {
+			appearance = "dark"
+		}
+		m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🎨 Detected %s terminal background, using %s", appearance, name))
+	}
+}
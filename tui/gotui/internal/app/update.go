@@ -9,6 +9,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea/v2"
 
 	"gotui/internal/components/chat"
+	"gotui/internal/layout/tabpages"
+	"gotui/internal/stores"
 )
 
 func (m *Model) toggleChatFocus() {
@@ -32,7 +34,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tryConnectMsg:
-		if m.wsClient == nil {
+		if msg.generation != m.connGeneration || m.wsClient == nil {
 			return m, nil
 		}
 		if m.wsClient.IsConnected() {
@@ -54,14 +56,28 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.tryConnect()
 
 	case connectMsg:
+		if msg.generation != m.connGeneration {
+			return m, nil
+		}
 		m.isRetrying = false
 		if msg.success {
 			m.retryCount = 0
 			m.lastError = ""
+			m.authFailed = false
 			if m.logsPage != nil {
+				m.logsPage.SetAuthError("")
 				m.logsPage.SetRetryInfo(m.retryCount, m.isRetrying, m.lastError)
 				m.logsPage.LogsPanel().AddLine("✅ Connected to agent server")
 			}
+			return m, m.subscribeServerLogs()
+		}
+
+		if m.isAuthFailure(msg.err) {
+			m.lastError = msg.err.Error()
+			if m.logsPage != nil {
+				m.logsPage.SetRetryInfo(m.retryCount, m.isRetrying, m.lastError)
+			}
+			m.reportAuthFailure("Connection refused", msg.err)
 			return m, nil
 		}
 
@@ -72,14 +88,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.lastError = "connection failed"
 		}
 		if m.logsPage != nil {
-			m.logsPage.LogsPanel().AddLine(fmt.Sprintf("❌ Connection failed: %v", msg.err))
+			m.logsPage.LogsPanel().AddError(fmt.Sprintf("❌ Connection failed: %v", msg.err))
 		}
 
 		if m.retryCount >= 60 {
 			m.isRetrying = false
 			if m.logsPage != nil {
 				m.logsPage.SetRetryInfo(m.retryCount, m.isRetrying, m.lastError)
-				m.logsPage.LogsPanel().AddLine("🚫 Max retry attempts reached. Press Ctrl+R to retry.")
+				m.logsPage.LogsPanel().AddError("🚫 Max retry attempts reached. Press Ctrl+R to retry.")
 			}
 			return m, nil
 		}
@@ -93,12 +109,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.logsPage.SetRetryInfo(m.retryCount, m.isRetrying, m.lastError)
 			m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🔁 Retrying in %s", delay))
 		}
-		return m, tea.Tick(delay, func(time.Time) tea.Msg { return tryConnectMsg{} })
+		generation := m.connGeneration
+		return m, tea.Tick(delay, func(time.Time) tea.Msg { return tryConnectMsg{generation: generation} })
 
 	case modelFetchResult:
+		if msg.generation != m.connGeneration {
+			return m, nil
+		}
+		if m.isAuthFailure(msg.err) {
+			m.reportAuthFailure("Failed to load models", msg.err)
+			return m, nil
+		}
 		if msg.err != nil {
 			if m.logsPage != nil {
-				m.logsPage.LogsPanel().AddLine(fmt.Sprintf("⚠️ Failed to load models: %v", msg.err))
+				m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️ Failed to load models: %v", msg.err))
 			}
 			return m, nil
 		}
@@ -116,13 +140,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case agentFetchResult:
+		if msg.generation != m.connGeneration {
+			return m, nil
+		}
+		if m.isAuthFailure(msg.err) {
+			m.reportAuthFailure("Failed to load agents", msg.err)
+			return m, nil
+		}
 		if msg.err != nil {
 			if m.logsPage != nil {
 				m.logsPage.AgentPanel().AddLine(fmt.Sprintf("⚠️ Failed to load agents: %v", msg.err))
-				m.logsPage.LogsPanel().AddLine(fmt.Sprintf("⚠️ Failed to load agents: %v", msg.err))
+				m.logsPage.LogsPanel().AddWarning(fmt.Sprintf("⚠️ Failed to load agents: %v", msg.err))
 			}
 			return m, nil
 		}
+		if chat := m.chatComponent(); chat != nil {
+			chat.RefreshAgents()
+		}
 		if m.logsPage != nil {
 			total := len(msg.options)
 			if total == 0 {
@@ -152,11 +186,39 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if m.activeTab == tabLogs && m.logsPage.CapturesInput() && !key.Matches(msg, m.keyMap.Quit) {
+			if press, ok := msg.(tea.KeyPressMsg); ok {
+				logsCmd, _ := m.logsPage.HandleKey(press)
+				return m, logsCmd
+			}
+		}
+		if m.activeTab == tabGit && m.gitPage.CapturesInput() && !key.Matches(msg, m.keyMap.Quit) {
+			if press, ok := msg.(tea.KeyPressMsg); ok {
+				gitCmd, _ := m.gitPage.HandleKey(press)
+				return m, gitCmd
+			}
+		}
+
+		// Window keys take every key but quit until they are turned off, so
+		// letters move windows instead of reaching the input or the panels.
+		if m.activeTab == tabChat && !key.Matches(msg, m.keyMap.Quit) {
+			if chat := m.chatComponent(); chat != nil && chat.WindowKeysActive() {
+				if press, ok := msg.(tea.KeyPressMsg); ok {
+					return m, chat.HandleWindowKey(press)
+				}
+			}
+		}
+
 		switch {
 		case key.Matches(msg, m.keyMap.Retry):
 			if m.wsClient == nil {
 				return m, nil
 			}
+			if m.authFailed || m.transportErr != nil {
+				// Re-read credentials so a fixed token file takes effect.
+				m.logsPage.LogsPanel().AddLine("🔄 Reloading credentials and reconnecting")
+				return m, m.connectProfile(m.cfg.activeProfile())
+			}
 			if m.wsClient.IsConnected() {
 				if m.logsPage != nil {
 					m.logsPage.LogsPanel().AddLine("ℹ️  Already connected to server")
@@ -185,6 +247,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return m, nil
+		case key.Matches(msg, m.keyMap.WindowKeys) && m.activeTab == tabChat && m.chatComponent() != nil && m.chatComponent().WindowModeActive():
+			// Outside window mode the key stays with the input.
+			if m.chatComponent().ToggleWindowKeys() && m.logsPage != nil {
+				m.logsPage.LogsPanel().AddLine("⌨️  Window keys on; Esc to finish")
+			}
+			return m, nil
 		case key.Matches(msg, m.keyMap.ToggleAutoTile):
 			if m.activeTab == tabChat {
 				if chat := m.chatComponent(); chat != nil && chat.WindowModeActive() {
@@ -229,6 +297,42 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.activeTab == tabLogs && m.logsPage != nil {
+			toggled := true
+			switch {
+			case key.Matches(msg, m.keyMap.ToggleStatus):
+				m.logsPage.ToggleStatus()
+			case key.Matches(msg, m.keyMap.ToggleLogs):
+				m.logsPage.ToggleLogs()
+			case key.Matches(msg, m.keyMap.ToggleServer):
+				m.logsPage.ToggleServer()
+			case key.Matches(msg, m.keyMap.ToggleAgent):
+				m.logsPage.ToggleAgent()
+			case key.Matches(msg, m.keyMap.ToggleNotifs):
+				m.logsPage.ToggleNotifications()
+				m.markNotificationsSeen()
+			default:
+				toggled = false
+			}
+			if toggled {
+				m.updateLayout()
+				return m, nil
+			}
+			if press, ok := msg.(tea.KeyPressMsg); ok {
+				if logsCmd, handled := m.logsPage.HandleKey(press); handled {
+					return m, logsCmd
+				}
+			}
+		}
+
+		if m.activeTab == tabGit {
+			if press, ok := msg.(tea.KeyPressMsg); ok {
+				if gitCmd, handled := m.gitPage.HandleKey(press); handled {
+					return m, gitCmd
+				}
+			}
+		}
+
 		if chat := m.chatComponent(); chat != nil && m.activeTab == tabChat {
 			if key.Matches(msg, m.keyMap.ShowCommands) {
 				chat.ToggleCommandPalette()
@@ -258,7 +362,108 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🧭 Active agent set to %s", msg.Option.Name))
 		}
 
+	case themeWatchMsg:
+		if m.loadThemes() {
+			m.refreshThemedViews()
+		}
+		return m, m.watchThemes()
+
+	case tea.BackgroundColorMsg:
+		m.handleBackgroundColor(msg)
+		return m, nil
+
+	case tea.FocusMsg:
+		m.focusReported, m.terminalFocused = true, true
+		return m, nil
+
+	case tea.BlurMsg:
+		m.focusReported, m.terminalFocused = true, false
+		return m, nil
+
+	case notificationMsg:
+		return m, m.handleNotification(msg)
+
+	case imagePreviewMsg:
+		return m, m.handleImagePreview()
+
+	case registryChangeMsg:
+		return m, m.handleRegistryChange(msg)
+
+	case registryRefreshMsg:
+		return m, m.refreshRegistry()
+
+	case modelRefreshResult:
+		m.handleModelRefresh(msg)
+		return m, nil
+
+	case agentRefreshResult:
+		m.handleAgentRefresh(msg)
+		return m, nil
+
+	case chat.ProfileSelectedMsg:
+		return m, m.switchProfile(msg.Name)
+
+	case chat.InstallAgentMsg:
+		return m, m.installAgent(msg)
+
+	case agentInstalledMsg:
+		return m, m.handleAgentInstalled(msg)
+
+	case chat.RevertChangesMsg:
+		return m, m.revertChanges(msg)
+
+	case changesRevertedMsg:
+		return m, m.handleChangesReverted(msg)
+
+	case tabpages.LogExportMsg:
+		return m, m.exportLogs(msg)
+
+	case logExportedMsg:
+		m.handleLogExported(msg)
+		return m, nil
+
+	case tabpages.GitRequestMsg:
+		return m, m.runGitRequest(msg)
+
+	case gitStatusMsg:
+		return m, m.handleGitStatus(msg)
+
+	case gitDiffMsg:
+		if msg.err != nil {
+			m.gitPage.SetDiffError(msg.item, fmt.Sprintf("git diff failed: %v", msg.err))
+		} else {
+			m.gitPage.SetDiff(msg.item, msg.diff)
+		}
+		return m, nil
+
+	case gitActionMsg:
+		return m, m.handleGitAction(msg)
+
+	case gitLogMsg, gitShowMsg, gitShowFileMsg, gitBlameMsg:
+		return m, m.handleGitHistory(msg)
+
+	case gitDraftChangesMsg:
+		return m, m.handleGitDraftChanges(msg)
+
+	case gitDraftSentMsg:
+		m.handleGitDraftSent(msg)
+		return m, nil
+
+	case gitDraftReplyMsg:
+		return m, m.handleGitDraftReply(msg)
+
+	case gitDraftTickMsg:
+		return m, m.handleGitDraftTick(msg)
+
+	case gitWatchMsg:
+		cmds := []tea.Cmd{m.watchGit()}
+		if m.activeTab == tabGit && m.gitRepo != nil {
+			cmds = append(cmds, m.loadGitStatus())
+		}
+		return m, tea.Batch(cmds...)
+
 	case chat.ThemeSelectedMsg:
+		m.themeChosen = true
 		if m.logsPage != nil {
 			m.logsPage.LogsPanel().AddLine(fmt.Sprintf("🎨 Theme changed to %s", msg.Preset.Name))
 		}
@@ -275,11 +480,22 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				chat.AddMessage("error", errText)
 			}
 			if m.logsPage != nil {
-				m.logsPage.LogsPanel().AddLine(errText)
+				m.logsPage.LogsPanel().AddError(errText)
 			}
 			return m, nil
 		}
-		return m, m.sendUserMessage(content)
+		return m, m.sendUserMessage(msg)
+
+	case chat.CompareSubmitMsg:
+		return m, m.sendCompare(msg)
+
+	case compareSentMsg:
+		m.handleCompareSent(msg)
+		return m, nil
+
+	case chat.CompareEndedMsg:
+		m.releaseCompareThreads()
+		return m, nil
 
 	case sendUserMessageResult:
 		if msg.err != nil {
@@ -288,10 +504,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				chat.AddMessage("error", errText)
 			}
 			if m.logsPage != nil {
-				m.logsPage.LogsPanel().AddLine(errText)
+				m.logsPage.LogsPanel().AddError(errText)
 			}
 			return m, nil
 		}
+		stores.SharedContextStore().DetachSent(msg.conversationID, msg.attached)
 		if m.logsPage != nil {
 			m.logsPage.LogsPanel().AddLine("📨 Message sent to agent server")
 		}
@@ -353,6 +570,7 @@ func (m *Model) switchTab(target tabID) tea.Cmd {
 	}
 
 	m.activeTab = target
+	m.markNotificationsSeen()
 
 	if target == tabChat {
 		m.chatFocused = true
@@ -364,7 +582,9 @@ func (m *Model) switchTab(target tabID) tea.Cmd {
 	}
 
 	if target == tabGit {
-		m.refreshGitPanels()
+		if cmd := m.refreshGitPanels(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	m.updateAllComponents()
@@ -0,0 +1,207 @@
+// Package changes captures agent file edits and reverts them.
+package changes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/gitrepo"
+	"gotui/internal/stores"
+)
+
+// Revert methods, reported back to the user.
+const (
+	MethodSnapshot = "snapshot"
+	MethodPatch    = "patch"
+)
+
+// ErrModifiedSince is returned when a file no longer holds the content the
+// agent wrote, so reverting would also throw away later edits.
+var ErrModifiedSince = errors.New("file changed since the agent edited it")
+
+// ErrNoBaseline is returned when there is nothing to revert a file from.
+var ErrNoBaseline = errors.New("no snapshot or diff to revert from")
+
+// ErrOutsideProject is returned for a file outside the project root, which
+// is never touched.
+var ErrOutsideProject = errors.New("file is outside the project")
+
+// beforeKeys are metadata fields servers use for the previous file content.
+var beforeKeys = []string{"old_content", "original_content", "previous_content", "before"}
+
+// EditFromEvent builds the edit recorded for a write_file message. When the
+// event does not carry the previous content, the file is snapshotted from
+// disk if the write has not landed yet. Once it has, the previous content is
+// unknown and the edit has no baseline. Edits of files outside projectPath,
+// directly or through a symlink, are not recorded.
+func EditFromEvent(projectPath string, metadata map[string]any, content string) (stores.FileEdit, bool) {
+	display := firstString(metadata, "file_path", "filePath", "path")
+	if display == "" {
+		return stores.FileEdit{}, false
+	}
+	path := display
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(projectPath, path)
+	}
+	path = filepath.Clean(path)
+	if !agentcontext.Within(path, projectPath) {
+		return stores.FileEdit{}, false
+	}
+	edit := stores.FileEdit{
+		Path:      path,
+		Display:   display,
+		Operation: firstString(metadata, "operation"),
+		Diff:      firstString(metadata, "diff"),
+		At:        time.Now(),
+	}
+	if edit.Operation != "append" && content != "" {
+		after := content
+		edit.After = &after
+	}
+
+	for _, key := range beforeKeys {
+		if before, ok := metadata[key].(string); ok {
+			edit.Before = &before
+			edit.Existed = true
+			edit.Baseline = stores.BaselineEvent
+			return edit, true
+		}
+	}
+
+	data, err := os.ReadFile(edit.Path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		edit.Baseline = stores.BaselineDisk
+	case err != nil:
+	case pending(edit, content, string(data)):
+		// The write is still pending, e.g. awaiting confirmation.
+		before := string(data)
+		edit.Before = &before
+		edit.Existed = true
+		edit.Baseline = stores.BaselineDisk
+	}
+	return edit, true
+}
+
+// pending reports whether the file on disk shows no sign of the edit yet. An
+// append that may already have landed is not pending.
+func pending(edit stores.FileEdit, content, onDisk string) bool {
+	if edit.Operation == "append" {
+		return content != "" && !strings.HasSuffix(onDisk, content)
+	}
+	return edit.After != nil && onDisk != *edit.After
+}
+
+// Revert restores change.Path to its state before the conversation's first
+// edit. Unless force is set, a file edited since the agent's last write is left
+// alone and ErrModifiedSince returned. Without a snapshot or a diff for every
+// edit nothing is touched and ErrNoBaseline returned: the file may hold the
+// user's own work. A file outside root is never touched and ErrOutsideProject
+// returned. repo may be nil outside a git work tree.
+func Revert(ctx context.Context, repo *gitrepo.Repo, root string, change stores.FileChange, force bool) (string, error) {
+	if !agentcontext.Within(change.Path, root) {
+		return "", ErrOutsideProject
+	}
+	if !force && change.After != nil {
+		data, err := os.ReadFile(change.Path)
+		if err == nil && string(data) != *change.After {
+			return "", ErrModifiedSince
+		}
+	}
+
+	if change.Baseline == stores.BaselineEvent {
+		return MethodSnapshot, restoreSnapshot(change)
+	}
+	var patchErr error
+	if len(change.Diffs) > 0 && len(change.Diffs) == change.Edits {
+		if patchErr = reverseDiffs(ctx, repo, change); patchErr == nil {
+			return MethodPatch, nil
+		}
+	}
+	if change.Baseline == stores.BaselineDisk {
+		return MethodSnapshot, restoreSnapshot(change)
+	}
+	if patchErr != nil {
+		return "", patchErr
+	}
+	return "", ErrNoBaseline
+}
+
+// Source names what Revert will restore change from, assuming its diffs apply,
+// or "" when it cannot be reverted.
+func Source(change stores.FileChange) string {
+	switch {
+	case change.Baseline == stores.BaselineEvent:
+		return MethodSnapshot
+	case len(change.Diffs) > 0 && len(change.Diffs) == change.Edits:
+		return MethodPatch
+	case change.Baseline == stores.BaselineDisk:
+		return MethodSnapshot
+	}
+	return ""
+}
+
+func restoreSnapshot(change stores.FileChange) error {
+	if !change.Existed {
+		if err := os.Remove(change.Path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(change.Path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(change.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(change.Path, []byte(*change.Before), mode)
+}
+
+// reverseDiffs applies the recorded diffs in reverse, newest first.
+func reverseDiffs(ctx context.Context, repo *gitrepo.Repo, change stores.FileChange) error {
+	dir := filepath.Dir(change.Path)
+	if repo != nil {
+		dir = repo.Dir
+	}
+	rel, err := filepath.Rel(dir, change.Path)
+	if err != nil {
+		return err
+	}
+	runner := gitrepo.New(dir)
+	for i := len(change.Diffs) - 1; i >= 0; i-- {
+		patch := withFileHeader(change.Diffs[i], filepath.ToSlash(rel))
+		if err := runner.ApplyPatch(ctx, patch, false, true); err != nil {
+			return fmt.Errorf("reverse diff: %w", err)
+		}
+	}
+	return nil
+}
+
+// withFileHeader adds ---/+++ lines to hunk-only diffs so git apply knows
+// which file to patch.
+func withFileHeader(diff, rel string) string {
+	if !strings.HasSuffix(diff, "\n") {
+		diff += "\n"
+	}
+	if strings.HasPrefix(diff, "diff --git") || strings.HasPrefix(diff, "--- ") {
+		return diff
+	}
+	return fmt.Sprintf("--- a/%s\n+++ b/%s\n%s", rel, rel, diff)
+}
+
+func firstString(metadata map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if s, ok := metadata[key].(string); ok && strings.TrimSpace(s) != "" {
+			return s
+		}
+	}
+	return ""
+}
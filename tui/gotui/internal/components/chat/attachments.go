@@ -0,0 +1,272 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/stores"
+	"gotui/internal/styles"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+)
+
+// editorFinishedMsg reports that the external editor opened on path exited.
+type editorFinishedMsg struct {
+	path string
+	err  error
+}
+
+// editorCommand returns the user's editor from $VISUAL or $EDITOR, falling
+// back to vi. The value may carry arguments, e.g. "code --wait".
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{"vi"}
+}
+
+// openEditor suspends the program and opens the current input in the
+// user's editor. The edited text replaces the input when the editor exits.
+func (c *Chat) openEditor() tea.Cmd {
+	file, err := os.CreateTemp("", "gotui-prompt-*.md")
+	if err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ Could not open the editor: %v", err))
+		return nil
+	}
+	path := file.Name()
+	_, err = file.WriteString(c.input.RawValue())
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		c.AddMessage("system", fmt.Sprintf("❌ Could not open the editor: %v", err))
+		return nil
+	}
+
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, err: err}
+	})
+}
+
+// finishEditor loads the edited prompt back into the input and removes the
+// temporary file. A failed editor leaves the input untouched.
+func (c *Chat) finishEditor(msg editorFinishedMsg) {
+	defer os.Remove(msg.path)
+	if msg.err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ Editor exited with an error, input left unchanged: %v", msg.err))
+		return
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ Could not read the edited prompt: %v", err))
+		return
+	}
+	c.input.SetValue(strings.TrimRight(string(data), "\n"))
+	c.refreshSlashMenu()
+}
+
+// projectRoot is the directory relative paths are resolved against.
+func (c *Chat) projectRoot() string {
+	root := ""
+	if state := c.ensureApplicationStateStore(); state != nil {
+		root = state.State().ProjectPath
+	}
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	return root
+}
+
+// resolvePath resolves a path typed by the user against the project root,
+// expanding a leading ~/.
+func (c *Chat) resolvePath(path string) string {
+	path = strings.Trim(strings.TrimSpace(path), `"'`)
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[1:])
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.projectRoot(), path)
+	}
+	return filepath.Clean(path)
+}
+
+// attachFile handles "/attach [path]": it attaches path to the next message,
+// or lists the attachments when no path is given.
+func (c *Chat) attachFile(arg string) {
+	c.ClearInput()
+	store := stores.SharedContextStore()
+	if strings.TrimSpace(arg) == "" {
+		attachments := store.Attachments(c.activeConversationID)
+		if len(attachments) == 0 {
+			c.AddMessage("system", "📎 Nothing attached. Use /attach <path> to attach a file to the next message.")
+			return
+		}
+		var b strings.Builder
+		b.WriteString("📎 Attached to the next message\n")
+		for _, attachment := range attachments {
+			b.WriteString(fmt.Sprintf("  %s  %s\n", attachment.Path, agentcontext.FormatSize(attachment.Size)))
+		}
+		c.AddMessage("system", strings.TrimRight(b.String(), "\n"))
+		return
+	}
+
+	c.attachPath(c.resolvePath(arg), strings.TrimSpace(arg), false)
+}
+
+// attachPath attaches the file at path, reporting problems under name. A
+// temporary file is deleted once it is no longer attached.
+func (c *Chat) attachPath(path, name string, temporary bool) {
+	store := stores.SharedContextStore()
+	attachment, err := agentcontext.NewAttachment(path)
+	if err == nil {
+		attachment.Temporary = temporary
+		err = agentcontext.Admit(store.Attachments(c.activeConversationID), attachment)
+	}
+	if err != nil {
+		if temporary {
+			os.Remove(path)
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("%s does not exist", name)
+		}
+		c.AddMessage("system", fmt.Sprintf("❌ Could not attach: %v", err))
+		return
+	}
+	if !store.Attach(c.activeConversationID, attachment) {
+		c.AddMessage("system", fmt.Sprintf("📎 %s is already attached", attachment.Name))
+	}
+}
+
+// clipboardImageMsg carries an image read from the system clipboard.
+type clipboardImageMsg struct {
+	path string
+	err  error
+}
+
+// pasteImage reads the clipboard image in the background.
+func (c *Chat) pasteImage() tea.Cmd {
+	return func() tea.Msg {
+		path, err := agentcontext.PasteImage()
+		return clipboardImageMsg{path: path, err: err}
+	}
+}
+
+func (c *Chat) finishPasteImage(msg clipboardImageMsg) {
+	if msg.err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ Could not paste an image: %v", msg.err))
+		return
+	}
+	c.attachPath(msg.path, "the clipboard image", true)
+}
+
+// handlePaste attaches a pasted image path, as terminals insert when an
+// image is dropped onto them. It reports false for any other text.
+func (c *Chat) handlePaste(text string) bool {
+	path, ok := agentcontext.ImagePath(text, c.projectRoot())
+	if !ok {
+		return false
+	}
+	c.attachPath(path, filepath.Base(path), false)
+	return true
+}
+
+// detachFile handles "/detach [name]": it removes the attachment matching
+// name by file name or path, or all attachments when no name is given.
+func (c *Chat) detachFile(arg string) {
+	c.ClearInput()
+	store := stores.SharedContextStore()
+	arg = strings.TrimSpace(arg)
+	if arg == "" {
+		store.ClearAttachments(c.activeConversationID)
+		return
+	}
+	path := c.resolvePath(arg)
+	for _, attachment := range store.Attachments(c.activeConversationID) {
+		if attachment.Name == arg || attachment.Path == path {
+			store.Detach(c.activeConversationID, attachment.Path)
+			return
+		}
+	}
+	c.AddMessage("system", fmt.Sprintf("📎 %s is not attached", arg))
+}
+
+// commandArgument returns the text after command in input if input invokes
+// it, e.g. commandArgument("/attach a.go", "/attach") is "a.go", true.
+func commandArgument(input, command string) (string, bool) {
+	if len(input) < len(command) || !strings.EqualFold(input[:len(command)], command) {
+		return "", false
+	}
+	rest := input[len(command):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// renderAttachmentChips renders the attachments of the next message as a
+// row of chips, or "" when nothing is attached.
+func (c *Chat) renderAttachmentChips(width int) string {
+	attachments := stores.SharedContextStore().Attachments(c.activeConversationID)
+	if len(attachments) == 0 || width <= 0 {
+		return ""
+	}
+	theme := styles.CurrentTheme()
+	chip := lipgloss.NewStyle().
+		Foreground(theme.Foreground).
+		Background(theme.Border).
+		Padding(0, 1)
+	chips := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		icon := "📎"
+		if attachment.Image {
+			icon = "🖼"
+		}
+		chips = append(chips, chip.Render(icon+" "+attachment.Name))
+	}
+	hint := lipgloss.NewStyle().Foreground(theme.Muted).Render("/detach to remove")
+	row := strings.Join(chips, " ") + "  " + hint
+	return lipgloss.NewStyle().Width(width).MaxWidth(width).MaxHeight(1).Render(row)
+}
+
+// attachedPaths returns the paths attached to the next message.
+func (c *Chat) attachedPaths() []string {
+	attachments := stores.SharedContextStore().Attachments(c.activeConversationID)
+	paths := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		paths = append(paths, attachment.Path)
+	}
+	return paths
+}
+
+// attachmentSummary is appended to the transcript copy of a sent message so
+// the conversation records what was attached to it.
+func (c *Chat) attachmentSummary() string {
+	store := stores.SharedContextStore()
+	attachments := store.Attachments(c.activeConversationID)
+	if len(attachments) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(attachments))
+	for _, attachment := range attachments {
+		if !store.IsDropped(c.activeConversationID, attachment.Item().Key) {
+			names = append(names, attachment.Name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return "\n📎 " + strings.Join(names, ", ")
+}
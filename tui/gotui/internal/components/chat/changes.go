@@ -0,0 +1,91 @@
+package chat
+
+import (
+	"fmt"
+
+	"gotui/internal/changes"
+	"gotui/internal/components/chatcomponents"
+	"gotui/internal/components/dialogs"
+	"gotui/internal/stores"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// RevertChangesMsg asks the app to revert agent file changes made during a
+// conversation. An empty Paths reverts every change.
+type RevertChangesMsg struct {
+	ConversationID string
+	Paths          []string
+	// Force reverts files even if they were edited after the agent's write.
+	Force bool
+}
+
+// RefreshChanges reloads the changes dialog after the change set moved on,
+// e.g. once a revert finished.
+func (c *Chat) RefreshChanges() {
+	if c.changesDialog == nil {
+		return
+	}
+	options := c.changeOptions()
+	c.changesDialog.SetOptions(options)
+}
+
+func (c *Chat) changeOptions() []chatcomponents.ChangeOption {
+	list := stores.SharedChangeStore().Changes(c.activeConversationID)
+	options := make([]chatcomponents.ChangeOption, 0, len(list))
+	for _, change := range list {
+		added, removed := change.DiffStat()
+		options = append(options, chatcomponents.ChangeOption{
+			Path:      change.Path,
+			Display:   change.Display,
+			Operation: change.Operation,
+			Edits:     change.Edits,
+			Added:     added,
+			Removed:   removed,
+			Source:    changes.Source(change),
+		})
+	}
+	return options
+}
+
+func (c *Chat) openChangesDialog() {
+	c.input.SetValueAndCursor("", 0)
+	c.slashMenu.Close()
+	c.commandPalette.Close()
+	c.modelPicker.Close()
+	c.themePicker.Close()
+	if c.agentBrowser != nil {
+		c.agentBrowser.Close()
+	}
+	c.profilePicker.Close()
+	c.changesDialog.SetOptions(c.changeOptions())
+	c.changesDialog.Open()
+}
+
+func (c *Chat) handleChangeAction(action dialogs.ChangeAction, option chatcomponents.ChangeOption) tea.Cmd {
+	msg := RevertChangesMsg{ConversationID: c.activeConversationID}
+	switch action {
+	case dialogs.ChangeActionRevert:
+		msg.Paths = []string{option.Path}
+	case dialogs.ChangeActionForceRevert:
+		msg.Paths = []string{option.Path}
+		msg.Force = true
+	case dialogs.ChangeActionRevertAll:
+	default:
+		return nil
+	}
+	return func() tea.Msg { return msg }
+}
+
+// undoLastChange reverts the most recently edited file in the conversation.
+func (c *Chat) undoLastChange() tea.Cmd {
+	list := stores.SharedChangeStore().Changes(c.activeConversationID)
+	if len(list) == 0 {
+		c.AddMessage("system", "↩️  No agent changes to undo in this conversation")
+		return nil
+	}
+	latest := list[0]
+	c.AddMessage("system", fmt.Sprintf("↩️  Undoing agent changes to %s", latest.Display))
+	msg := RevertChangesMsg{ConversationID: c.activeConversationID, Paths: []string{latest.Path}}
+	return func() tea.Msg { return msg }
+}
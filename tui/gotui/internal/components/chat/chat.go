@@ -3,14 +3,19 @@ package chat
 import (
 	"strings"
 
+	"gotui/internal/agentcontext"
 	"gotui/internal/components/chat/windows"
 	"gotui/internal/components/chatcomponents"
 	"gotui/internal/components/chattemplates"
 	"gotui/internal/components/dialogs"
 	"gotui/internal/components/widgets"
+	"gotui/internal/keybindings"
 	"gotui/internal/layout/panels"
+	"gotui/internal/promptcommands"
+	"gotui/internal/prompthistory"
 	"gotui/internal/stores"
 	"gotui/internal/styles"
+	"gotui/internal/windowlayout"
 
 	"github.com/charmbracelet/bubbles/v2/viewport"
 	tea "github.com/charmbracelet/bubbletea/v2"
@@ -33,8 +38,12 @@ type Chat struct {
 	templateManager *chattemplates.TemplateManager
 	slashMenu       *chatcomponents.SlashMenu
 	modelPicker     *chatcomponents.ModelPicker
-	agentPicker     *chatcomponents.AgentPicker
+	agentBrowser    *chatcomponents.AgentBrowser
 	themePicker     *dialogs.ThemePicker
+	profilePicker   *chatcomponents.ProfilePicker
+	changesDialog   *chatcomponents.ChangesDialog
+	contextDialog   *chatcomponents.ContextDialog
+	argumentDialog  *chatcomponents.ArgumentDialog
 	settingsDialog  *chatcomponents.ApplicationSettingsDialog
 	commandPalette  *chatcomponents.CommandPalette
 	selectedModel   *chatcomponents.ModelOption
@@ -81,17 +90,41 @@ type Chat struct {
 	contextZoneID        string
 
 	windowManager      *windows.Manager
+	compare            *compareSession
 	pendingCmds        []tea.Cmd
 	subAgentSelections map[string]int
 	subAgentMessages   map[string]map[int][]chattemplates.MessageTemplateData
+
+	keyMap       keybindings.KeyMap
+	keymapReport keybindings.Report
+
+	promptHistory *prompthistory.History
+	windowLayouts *windowlayout.Store
+
+	promptCommands []promptcommands.Command
+	pendingPrompt  *pendingPromptCommand
+	// outsideRootPrompt waits for the user to allow a project command to
+	// read files outside the project.
+	outsideRootPrompt *pendingPromptCommand
 }
 
 func defaultSlashCommands() []chatcomponents.SlashCommand {
 	return []chatcomponents.SlashCommand{
 		{Name: "models", Description: "Switch active AI model", Usage: "/models"},
-		{Name: "agents", Description: "Switch active agent", Usage: "/agents"},
+		{Name: "agents", Description: "Browse, install and switch agents", Usage: "/agents"},
 		{Name: "theme", Description: "Switch TUI color theme", Usage: "/theme"},
 		{Name: "settings", Description: "Configure application defaults", Usage: "/settings"},
+		{Name: "profiles", Description: "Switch agent server profile", Usage: "/profiles"},
+		{Name: "changes", Description: "Review or revert files the agent changed", Usage: "/changes"},
+		{Name: "context", Description: "Review or drop what the next request sends", Usage: "/context"},
+		{Name: "attach", Description: "Attach a file or image to the next message", Usage: "/attach <path>"},
+		{Name: "paste", Description: "Attach the image on the clipboard", Usage: "/paste"},
+		{Name: "detach", Description: "Remove an attachment, or all of them", Usage: "/detach [name]"},
+		{Name: "usage", Description: "Show token usage and cost of this chat", Usage: "/usage"},
+		{Name: "compare", Description: "Ask several models side by side", Usage: "/compare [model, model…|off]"},
+		{Name: "promote", Description: "Keep one compared answer in this chat", Usage: "/promote [column]"},
+		{Name: "layout", Description: "Toggle panel/window layout, or save and switch window layouts", Usage: "/layout [save|load|delete <name>|list]"},
+		{Name: "keys", Description: "Show effective key bindings", Usage: "/keys"},
 		{Name: "help", Description: "Show available commands", Usage: "/help"},
 	}
 }
@@ -110,8 +143,12 @@ func New() *Chat {
 		templateManager:       templateManager,
 		slashMenu:             chatcomponents.NewSlashMenu(defaultSlashCommands()),
 		modelPicker:           chatcomponents.NewModelPicker(nil),
-		agentPicker:           chatcomponents.NewAgentPicker(nil),
+		agentBrowser:          chatcomponents.NewAgentBrowser(nil),
 		themePicker:           dialogs.NewThemePicker(styles.PresetThemes()),
+		profilePicker:         chatcomponents.NewProfilePicker(nil),
+		changesDialog:         chatcomponents.NewChangesDialog(),
+		contextDialog:         chatcomponents.NewContextDialog(),
+		argumentDialog:        chatcomponents.NewArgumentDialog(),
 		settingsDialog:        chatcomponents.NewApplicationSettingsDialog(),
 		commandPalette:        chatcomponents.NewCommandPalette(defaultSlashCommands()),
 		conversationBar:       NewConversationBar(),
@@ -129,10 +166,12 @@ func New() *Chat {
 		windowManager:         windows.NewManager(templateManager),
 		subAgentSelections:    make(map[string]int),
 		subAgentMessages:      make(map[string]map[int][]chattemplates.MessageTemplateData),
+		keyMap:                keybindings.DefaultKeyMap(),
+		keymapReport:          keybindings.Report{Preset: keybindings.PresetDefault},
 	}
 	chat.modelStatusWidget = widgets.NewModelStatusWidget(nil, nil)
 	chat.modelStatusWidget.SetStateStore(chat.applicationState)
-	chat.commandPalette.UpdateCommands(chat.slashMenu.Commands())
+	chat.SetKeyMap(chat.keyMap)
 	chat.createInitialConversation()
 	chat.loadActiveConversation()
 	if chat.windowManager != nil {
@@ -167,8 +206,8 @@ func (c *Chat) SetAgentStore(store *stores.AgentStore) {
 		return
 	}
 	c.agentStore = store
-	if c.agentPicker != nil {
-		c.agentPicker.BindStore(store)
+	if c.agentBrowser != nil {
+		c.agentBrowser.BindStore(store)
 	}
 	if c.modelStatusWidget != nil {
 		c.modelStatusWidget.SetAgentStore(store)
@@ -177,6 +216,22 @@ func (c *Chat) SetAgentStore(store *stores.AgentStore) {
 	c.refreshConversationsFromStore(true)
 }
 
+// RefreshAgents lists the agent store's current agents in the browser.
+func (c *Chat) RefreshAgents() {
+	if c == nil || c.agentBrowser == nil || c.agentStore == nil {
+		return
+	}
+	c.agentBrowser.SetAgents(c.agentStore.Agents())
+}
+
+// AgentInstallFailed lets the agent browser offer to install id again.
+func (c *Chat) AgentInstallFailed(id string) {
+	if c == nil || c.agentBrowser == nil {
+		return
+	}
+	c.agentBrowser.InstallFailed(id)
+}
+
 // SetPreferredAgent records the agent provided via configuration to seed new conversations.
 func (c *Chat) SetPreferredAgent(agent stores.AgentSelection) {
 	if c == nil {
@@ -217,6 +272,9 @@ func (c *Chat) SetHelpBar(bar *widgets.HelpBar) {
 		return
 	}
 	c.helpBar = bar
+	if bar != nil {
+		bar.SetKeyMap(c.keyMap)
+	}
 }
 
 // SetRightSidebarPanels assigns the info panels rendered in the context drawer.
@@ -307,6 +365,17 @@ func (c *Chat) SetModelOptions(options []chatcomponents.ModelOption) {
 // SubmitMsg is sent when a message is submitted.
 type SubmitMsg struct {
 	Content string
+	// Context is what goes out with the message besides its text.
+	Context agentcontext.Request
+	// Agent and Model override the current selection for this message, as
+	// custom slash commands may.
+	Agent *stores.AgentSelection
+	Model *stores.ModelOption
+	// ConversationID and Attached name the conversation and the files
+	// attached to it at submit time; they are detached once the message is
+	// sent, and kept for another try if sending fails.
+	ConversationID string
+	Attached       []string
 }
 
 // ModelSelectedMsg is sent when the user selects a model from the picker.
@@ -319,6 +388,12 @@ type AgentSelectedMsg struct {
 	Option chatcomponents.AgentOption
 }
 
+// InstallAgentMsg asks the app to install an agent the server offers but
+// has not installed yet.
+type InstallAgentMsg struct {
+	Option chatcomponents.AgentOption
+}
+
 // ThemeSelectedMsg is emitted when a theme preset is chosen.
 type ThemeSelectedMsg struct {
 	Preset styles.ThemePreset
@@ -370,12 +445,36 @@ func (c *Chat) View() string {
 		}
 	}
 
-	if c.agentPicker != nil && c.agentPicker.IsVisible() {
-		if layer := c.agentPicker.Layer(c.width, c.height); layer != nil {
+	if c.agentBrowser != nil && c.agentBrowser.IsVisible() {
+		if layer := c.agentBrowser.Layer(c.width, c.height); layer != nil {
 			overlayLayers = append(overlayLayers, layer.Z(25))
 		}
 	}
 
+	if c.profilePicker != nil && c.profilePicker.IsVisible() {
+		if layer := c.profilePicker.Layer(c.width, c.height); layer != nil {
+			overlayLayers = append(overlayLayers, layer.Z(24))
+		}
+	}
+
+	if c.changesDialog != nil && c.changesDialog.IsVisible() {
+		if layer := c.changesDialog.Layer(c.width, c.height); layer != nil {
+			overlayLayers = append(overlayLayers, layer.Z(23))
+		}
+	}
+
+	if c.contextDialog != nil && c.contextDialog.IsVisible() {
+		if layer := c.contextDialog.Layer(c.width, c.height); layer != nil {
+			overlayLayers = append(overlayLayers, layer.Z(23))
+		}
+	}
+
+	if c.argumentDialog != nil && c.argumentDialog.IsVisible() {
+		if layer := c.argumentDialog.Layer(c.width, c.height); layer != nil {
+			overlayLayers = append(overlayLayers, layer.Z(26))
+		}
+	}
+
 	if c.settingsDialog != nil && c.settingsDialog.IsVisible() {
 		if layer := c.settingsDialog.Layer(c.width, c.height); layer != nil {
 			overlayLayers = append(overlayLayers, layer.Z(22))
@@ -0,0 +1,299 @@
+package chat
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/components/chattemplates"
+	"gotui/internal/stores"
+)
+
+// maxCompareModels bounds a comparison; more columns get too narrow to read.
+const maxCompareModels = 4
+
+// compareSession fans prompts out to conversations that differ only in their
+// model.
+type compareSession struct {
+	// sourceID is the conversation the comparison started from, where
+	// /promote puts the chosen answer.
+	sourceID string
+	// ids are the compared conversations, left to right.
+	ids []string
+}
+
+// CompareTarget is one conversation a compared prompt goes to.
+type CompareTarget struct {
+	ConversationID string
+	Context        agentcontext.Request
+	Agent          *stores.AgentSelection
+	Model          *stores.ModelOption
+}
+
+// CompareSubmitMsg asks the app to send the same prompt to every compared
+// conversation and route each reply back to its conversation.
+type CompareSubmitMsg struct {
+	Content string
+	Targets []CompareTarget
+	// ConversationID and Attached are as in SubmitMsg.
+	ConversationID string
+	Attached       []string
+}
+
+// CompareEndedMsg tells the app that compare mode ended, so replies stop
+// going to the compared conversations.
+type CompareEndedMsg struct{}
+
+// Comparing reports whether prompts currently fan out to several models.
+func (c *Chat) Comparing() bool {
+	return c != nil && c.compare != nil
+}
+
+// runCompareCommand handles "/compare [model, model…|off]".
+func (c *Chat) runCompareCommand(args string) {
+	c.ClearInput()
+	c.slashMenu.Close()
+	if strings.EqualFold(args, "off") {
+		if c.compare == nil {
+			c.AddMessage("system", "⚖️ Not comparing models")
+			return
+		}
+		c.endCompare()
+		return
+	}
+	if c.compare != nil {
+		c.AddMessage("system", "⚖️ Already comparing models; /promote keeps an answer and /compare off stops")
+		return
+	}
+	models, err := c.compareModels(args)
+	if err != nil {
+		c.AddMessage("error", "❌ "+err.Error())
+		return
+	}
+	c.startCompare(models)
+}
+
+// compareModels resolves the comma-separated model names in args, or the
+// favourite models when args is empty.
+func (c *Chat) compareModels(args string) ([]stores.ModelOption, error) {
+	var models []stores.ModelOption
+	if strings.TrimSpace(args) == "" {
+		models = stores.SharedApplicationSettingsStore().Settings().FavoriteModels
+	}
+	for _, name := range strings.Split(args, ",") {
+		model, err := c.resolvePromptModel(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		if model != nil {
+			models = append(models, *model)
+		}
+	}
+	switch {
+	case len(models) < 2:
+		return nil, fmt.Errorf("name at least two models to compare, e.g. /compare gpt-4o, claude-sonnet-4, or mark favourites with Ctrl+F in /models")
+	case len(models) > maxCompareModels:
+		return nil, fmt.Errorf("compare at most %d models at a time", maxCompareModels)
+	}
+	return models, nil
+}
+
+// startCompare creates a conversation per model with the active conversation's
+// agent and shows them side by side.
+func (c *Chat) startCompare(models []stores.ModelOption) {
+	store := c.ensureConversationStore()
+	source := store.ActiveConversation()
+	if source == nil {
+		return
+	}
+	c.saveDraft()
+
+	session := &compareSession{sourceID: source.ID}
+	for i, model := range models {
+		opts := stores.ConversationOptions{SelectedModel: &model, SelectedAgent: source.Options.SelectedAgent}
+		conv := store.CreateConversation(fmt.Sprintf("Compare %d · %s", i+1, model.Name), opts)
+		if conv == nil {
+			continue
+		}
+		store.AppendMessage(conv.ID, c.newMessageData("system", fmt.Sprintf("⚖️ Comparing %s. Prompts you send go to every compared model.", modelLabel(model)), nil, nil))
+		session.ids = append(session.ids, conv.ID)
+	}
+	if len(session.ids) == 0 {
+		return
+	}
+	store.SetActive(session.ids[0])
+	c.compare = session
+	c.restoreDraft()
+
+	c.refreshConversationsFromStore(true)
+	c.refreshActiveConversationView()
+	if c.windowManager != nil {
+		c.windowManager.SetSize(c.width, c.height)
+		c.windowManager.StartCompare(session.ids)
+	}
+}
+
+// endCompare leaves compare mode and returns to the conversation the
+// comparison started from. The compared conversations stay in the list.
+func (c *Chat) endCompare() {
+	session := c.compare
+	if session == nil {
+		return
+	}
+	c.compare = nil
+	if c.windowManager != nil {
+		c.windowManager.StopCompare()
+	}
+	c.enqueueCmd(func() tea.Msg { return CompareEndedMsg{} })
+	c.switchConversation(session.sourceID)
+	c.AddMessage("system", "⚖️ Stopped comparing models; the compared conversations stay in the list")
+}
+
+// comparedConversation reports whether id is part of the running comparison.
+func (c *Chat) comparedConversation(id string) bool {
+	return c.compare != nil && slices.Contains(c.compare.ids, id)
+}
+
+// submitCompare records input in every compared conversation and sends it
+// to each with that conversation's own model. agent overrides the agent when
+// a custom command names one.
+func (c *Chat) submitCompare(input string, agent *stores.AgentSelection) tea.Cmd {
+	store := c.ensureConversationStore()
+	context := c.ContextWindow().Request()
+	var metadata map[string]interface{}
+	if len(context.Images) > 0 {
+		metadata = map[string]interface{}{"images": context.Images}
+	}
+	shown := input + c.attachmentSummary()
+
+	msg := CompareSubmitMsg{Content: input, ConversationID: c.activeConversationID, Attached: c.attachedPaths()}
+	for _, id := range c.compare.ids {
+		conv := store.Conversation(id)
+		if conv == nil {
+			continue
+		}
+		target := CompareTarget{
+			ConversationID: id,
+			Context:        context,
+			Agent:          conv.Options.SelectedAgent,
+			Model:          conv.Options.SelectedModel,
+		}
+		if agent != nil {
+			target.Agent = agent
+		}
+		msg.Targets = append(msg.Targets, target)
+		store.AppendMessage(id, c.newMessageData("user", shown, metadata, nil))
+	}
+	c.refreshConversationsFromStore(true)
+	c.refreshActiveConversationView()
+	return func() tea.Msg { return msg }
+}
+
+// AddConversationMessage adds a message to the conversation with the given
+// ID, which need not be the active one.
+func (c *Chat) AddConversationMessage(conversationID, msgType, content string) {
+	c.AddConversationMessageWithMetadata(conversationID, msgType, content, nil, nil)
+}
+
+// AddConversationMessageWithMetadata adds a message with metadata to the
+// conversation with the given ID. A message that repeats the message ID of
+// the conversation's last message replaces it, so a reply the server resends
+// as it grows is shown once.
+func (c *Chat) AddConversationMessageWithMetadata(conversationID, msgType, content string, metadata map[string]interface{}, buttons []chattemplates.MessageButton) {
+	if c == nil {
+		return
+	}
+	store := c.ensureConversationStore()
+	if _, ok := store.PutMessage(conversationID, c.newMessageData(msgType, content, metadata, buttons)); !ok {
+		return
+	}
+	c.refreshConversationsFromStore(true)
+	if conversationID == c.activeConversationID {
+		c.refreshActiveConversationView()
+	}
+}
+
+// promoteAnswer copies the last prompt and answer of a compared
+// conversation into the conversation the comparison started from and ends
+// compare mode. args picks the column; by default the focused window is used.
+func (c *Chat) promoteAnswer(args string) {
+	c.ClearInput()
+	c.slashMenu.Close()
+	session := c.compare
+	if session == nil {
+		c.AddMessage("system", "⚖️ Nothing to promote; /compare asks several models side by side")
+		return
+	}
+
+	id := c.activeConversationID
+	if c.windowManager != nil && c.comparedConversation(c.windowManager.FocusedID()) {
+		id = c.windowManager.FocusedID()
+	}
+	if args != "" {
+		column, err := strconv.Atoi(args)
+		if err != nil || column < 1 || column > len(session.ids) {
+			c.AddMessage("error", fmt.Sprintf("❌ /promote takes a column from 1 to %d", len(session.ids)))
+			return
+		}
+		id = session.ids[column-1]
+	}
+	if !c.comparedConversation(id) {
+		id = session.ids[0]
+	}
+
+	store := c.ensureConversationStore()
+	conv := store.Conversation(id)
+	if conv == nil {
+		return
+	}
+	exchange := lastExchange(conv.Messages)
+	if exchange == nil {
+		c.AddMessage("system", fmt.Sprintf("⚖️ %s has no answer to promote yet", conv.Title))
+		return
+	}
+	for _, message := range exchange {
+		store.AppendMessage(session.sourceID, message)
+	}
+	label := conv.Title
+	if model := conv.Options.SelectedModel; model != nil {
+		label = modelLabel(*model)
+	}
+	store.AppendMessage(session.sourceID, c.newMessageData("system", fmt.Sprintf("⬆️ Kept the answer of %s", label), nil, nil))
+	c.endCompare()
+}
+
+// lastExchange returns the last user prompt and the answers that followed
+// it, or nil when nothing has answered it yet.
+func lastExchange(messages []chattemplates.MessageTemplateData) []chattemplates.MessageTemplateData {
+	start := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Type == "user" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+	exchange := []chattemplates.MessageTemplateData{messages[start]}
+	for _, message := range messages[start+1:] {
+		if turnTypes[message.Type] == "assistant" {
+			exchange = append(exchange, message)
+		}
+	}
+	if len(exchange) == 1 {
+		return nil
+	}
+	return exchange
+}
+
+func modelLabel(model stores.ModelOption) string {
+	if model.Provider == "" {
+		return model.Name
+	}
+	return fmt.Sprintf("%s (%s)", model.Name, model.Provider)
+}
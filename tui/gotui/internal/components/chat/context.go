@@ -0,0 +1,154 @@
+package chat
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/components/dialogs"
+	"gotui/internal/stores"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// turnTypes are the message types sent back to the agent as prior turns.
+var turnTypes = map[string]string{
+	"user": "user",
+	"ai":   "assistant",
+}
+
+// ContextWindow estimates what the next request sends: files attached with
+// /attach or mentioned in the input as @path, the conversation's prior turns,
+// and the selected model's context size. Items the user dropped are marked.
+func (c *Chat) ContextWindow() agentcontext.Window {
+	window := agentcontext.Window{}
+	if c == nil {
+		return window
+	}
+	dropped := stores.SharedContextStore()
+	conversationID := c.activeConversationID
+
+	root := c.projectRoot()
+	attached := make(map[string]bool)
+	for _, attachment := range dropped.Attachments(conversationID) {
+		attached[attachment.Path] = true
+		item := attachment.Item()
+		item.Dropped = dropped.IsDropped(conversationID, item.Key)
+		window.Items = append(window.Items, item)
+	}
+	for _, path := range agentcontext.Mentions(c.input.RawValue(), root) {
+		if attached[path] {
+			continue
+		}
+		label := path
+		if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+			label = rel
+		}
+		item := agentcontext.FileItem(path, label)
+		item.Dropped = dropped.IsDropped(conversationID, item.Key)
+		window.Items = append(window.Items, item)
+	}
+
+	window.Items = append(window.Items, c.turnItems(conversationID)...)
+
+	if model := c.selectedModel; model != nil {
+		window.Limit = agentcontext.ParseLimit(model.Context)
+		if c.modelStore != nil && window.Limit == 0 {
+			if known, ok := c.modelStore.ModelByNameProvider(model.Name, model.Provider); ok {
+				window.Limit = agentcontext.ParseLimit(known.Context)
+			}
+		}
+	}
+	return window
+}
+
+// ContextKey changes whenever ContextWindow may return something else, so
+// callers can skip rebuilding it.
+func (c *Chat) ContextKey() string {
+	if c == nil {
+		return ""
+	}
+	key := fmt.Sprintf("%s@%d|%s", c.activeConversationID, stores.SharedContextStore().Version(), c.input.RawValue())
+	for _, conv := range c.conversations {
+		if conv != nil && conv.ID == c.activeConversationID {
+			key += fmt.Sprintf("|%d@%d", len(conv.Messages), conv.UpdatedAt.UnixNano())
+		}
+	}
+	if model := c.selectedModel; model != nil {
+		key += "|" + model.Provider + "/" + model.Name + "/" + model.Context
+	}
+	return key
+}
+
+// turnItems lists the prior turns of a conversation.
+func (c *Chat) turnItems(conversationID string) []agentcontext.Item {
+	var items []agentcontext.Item
+	for _, conv := range c.conversations {
+		if conv == nil || conv.ID != conversationID {
+			continue
+		}
+		for i, message := range conv.Messages {
+			role, ok := turnTypes[message.Type]
+			if !ok || strings.TrimSpace(message.Content) == "" {
+				continue
+			}
+			item := agentcontext.Item{
+				Key:     agentcontext.TurnKey(i),
+				Kind:    agentcontext.KindTurn,
+				Label:   turnLabel(role, message.Content),
+				Role:    role,
+				Content: message.Content,
+				Tokens:  agentcontext.EstimateTokens(message.Content),
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// turnLabel is a one-line summary of a prior turn.
+func turnLabel(role, content string) string {
+	line := strings.TrimSpace(content)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = strings.TrimSpace(line[:i]) + " …"
+	}
+	if runes := []rune(line); len(runes) > 60 {
+		line = string(runes[:59]) + "…"
+	}
+	if role == "user" {
+		return "You: " + line
+	}
+	return "Agent: " + line
+}
+
+func (c *Chat) openContextDialog() {
+	c.input.SetValueAndCursor("", 0)
+	c.slashMenu.Close()
+	c.commandPalette.Close()
+	c.modelPicker.Close()
+	c.themePicker.Close()
+	if c.agentBrowser != nil {
+		c.agentBrowser.Close()
+	}
+	c.profilePicker.Close()
+	c.contextDialog.SetWindow(c.ContextWindow())
+	c.contextDialog.Open()
+}
+
+func (c *Chat) handleContextAction(action dialogs.ContextAction, item agentcontext.Item) tea.Cmd {
+	store := stores.SharedContextStore()
+	switch action {
+	case dialogs.ContextActionToggle:
+		if !item.Droppable() {
+			return nil
+		}
+		store.SetDropped(c.activeConversationID, item.Key, !item.Dropped)
+	case dialogs.ContextActionRestoreAll:
+		store.RestoreAll(c.activeConversationID)
+	default:
+		return nil
+	}
+	c.contextDialog.SetWindow(c.ContextWindow())
+	return nil
+}
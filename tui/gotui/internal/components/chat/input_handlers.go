@@ -5,9 +5,14 @@ import (
 	"unicode"
 
 	"gotui/internal/components/chatcomponents"
+	"gotui/internal/components/dialogs"
 	"gotui/internal/layout/panels"
+	"gotui/internal/logging"
+	"gotui/internal/prompthistory"
+	"gotui/internal/stores"
 	"gotui/internal/styles"
 
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	zone "github.com/lrstanley/bubblezone"
 )
@@ -52,7 +57,7 @@ func (c *Chat) ClearInput() {
 }
 
 func (c *Chat) refreshSlashMenu() {
-	if !c.focused || c.modelPicker.IsVisible() || (c.agentPicker != nil && c.agentPicker.IsVisible()) || c.themePicker.IsVisible() || c.commandPalette.IsVisible() {
+	if !c.focused || c.modelPicker.IsVisible() || (c.agentBrowser != nil && c.agentBrowser.IsVisible()) || c.themePicker.IsVisible() || c.profilePicker.IsVisible() || c.changesDialog.IsVisible() || c.contextDialog.IsVisible() || c.argumentDialog.IsVisible() || c.commandPalette.IsVisible() {
 		c.slashMenu.Close()
 		return
 	}
@@ -94,7 +99,7 @@ func (c *Chat) applySlashCommand(cmd chatcomponents.SlashCommand) tea.Cmd {
 		c.input.SetValueAndCursor("", 0)
 		c.slashMenu.Close()
 		c.commandPalette.Close()
-		c.modelPicker.Open()
+		c.openModelPicker(dialogs.ModelScopeChat)
 		return nil
 	}
 
@@ -103,8 +108,8 @@ func (c *Chat) applySlashCommand(cmd chatcomponents.SlashCommand) tea.Cmd {
 		c.slashMenu.Close()
 		c.commandPalette.Close()
 		c.modelPicker.Close()
-		if c.agentPicker != nil {
-			c.agentPicker.Open()
+		if c.agentBrowser != nil {
+			c.agentBrowser.Open()
 		}
 		return nil
 	}
@@ -114,21 +119,50 @@ func (c *Chat) applySlashCommand(cmd chatcomponents.SlashCommand) tea.Cmd {
 		c.slashMenu.Close()
 		c.commandPalette.Close()
 		c.modelPicker.Close()
-		if c.agentPicker != nil {
-			c.agentPicker.Close()
+		if c.agentBrowser != nil {
+			c.agentBrowser.Close()
 		}
 		c.themePicker.SetOptions(styles.PresetThemes())
 		c.themePicker.Open(styles.CurrentThemeName())
 		return nil
 	}
 
+	if cmd.Name == "profiles" {
+		c.openProfilePicker()
+		return nil
+	}
+
+	if cmd.Name == "changes" {
+		c.openChangesDialog()
+		return nil
+	}
+
+	if name, ok := strings.CutPrefix(cmd.Name, loadLayoutCommand); ok {
+		c.input.SetValueAndCursor("", 0)
+		c.slashMenu.Close()
+		c.commandPalette.Close()
+		return c.loadNamedLayout(name)
+	}
+
+	if cmd.Name == "layout" || cmd.Name == "keys" {
+		c.input.SetValueAndCursor("", 0)
+		c.slashMenu.Close()
+		c.commandPalette.Close()
+		if cmd.Name == "layout" {
+			c.toggleLayoutFromCommand()
+		} else {
+			c.showKeyBindings()
+		}
+		return nil
+	}
+
 	if cmd.Name == "settings" {
 		c.input.SetValueAndCursor("", 0)
 		c.slashMenu.Close()
 		c.commandPalette.Close()
 		c.modelPicker.Close()
-		if c.agentPicker != nil {
-			c.agentPicker.Close()
+		if c.agentBrowser != nil {
+			c.agentBrowser.Close()
 		}
 		c.settingsDialog.Open()
 		return nil
@@ -414,7 +448,35 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 			skipContextViewport = true
 		}
 
+	case editorFinishedMsg:
+		c.finishEditor(msg)
+		return c, nil
+
+	case clipboardImageMsg:
+		c.finishPasteImage(msg)
+		return c, nil
+
+	case promptExpandedMsg:
+		return c, c.finishPromptCommand(msg)
+
+	case tea.PasteMsg:
+		if c.argumentDialog.IsVisible() {
+			c.argumentDialog.Paste(string(msg))
+			return c, nil
+		}
+		if c.focused && c.handlePaste(string(msg)) {
+			return c, nil
+		}
+
 	case tea.KeyPressMsg:
+		if c.outsideRootPrompt != nil {
+			return c, c.confirmOutsideRoot(msg)
+		}
+		if c.focused && c.input.Searching() {
+			c.input.HandleSearchKey(msg, key.Matches(msg, c.keyMap.HistorySearch))
+			return c, nil
+		}
+
 		if msg.String() == "tab" {
 			if c.focused {
 				c.FocusSidebar()
@@ -450,6 +512,15 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 				return c, nil
 			}
 		}
+		if c.argumentDialog.IsVisible() {
+			if handled, values, ok := c.argumentDialog.HandleKey(msg); handled {
+				if ok || !c.argumentDialog.IsVisible() {
+					return c, c.finishArguments(values, ok)
+				}
+				return c, nil
+			}
+		}
+
 		if msg.String() == "ctrl+p" {
 			if c.commandPalette.IsVisible() {
 				c.commandPalette.Close()
@@ -481,6 +552,28 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 			}
 		}
 
+		if c.profilePicker.IsVisible() {
+			handled, option, ok := c.profilePicker.HandleKey(msg)
+			if handled {
+				if ok {
+					return c, c.handleProfileSelection(option)
+				}
+				return c, nil
+			}
+		}
+
+		if c.changesDialog.IsVisible() {
+			if handled, action, option := c.changesDialog.HandleKey(msg); handled {
+				return c, c.handleChangeAction(action, option)
+			}
+		}
+
+		if c.contextDialog.IsVisible() {
+			if handled, action, item := c.contextDialog.HandleKey(msg); handled {
+				return c, c.handleContextAction(action, item)
+			}
+		}
+
 		if c.settingsDialog.IsVisible() {
 			handled, option, ok := c.settingsDialog.HandleKey(msg)
 			if handled {
@@ -488,11 +581,11 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 					switch option.Key {
 					case "default_model":
 						c.pendingPreference = preferenceTargetDefaultModel
-						c.modelPicker.Open()
+						c.openModelPicker(dialogs.ModelScopeDefault)
 					case "default_agent":
 						c.pendingPreference = preferenceTargetDefaultAgent
-						if c.agentPicker != nil {
-							c.agentPicker.Open()
+						if c.agentBrowser != nil {
+							c.agentBrowser.Open()
 						}
 					}
 				}
@@ -503,16 +596,21 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 			}
 		}
 
-		if c.agentPicker != nil && c.agentPicker.IsVisible() {
-			handled, selection, applyDefault, ok := c.agentPicker.HandleKey(msg)
+		if c.agentBrowser != nil && c.agentBrowser.IsVisible() {
+			handled, action, option := c.agentBrowser.HandleKey(msg)
 			if handled {
-				if ok {
-					if applyDefault || c.pendingPreference == preferenceTargetDefaultAgent {
-						applyDefault = true
-					}
-					cmd := c.handleAgentSelection(selection, applyDefault)
+				switch action {
+				case dialogs.AgentBrowserActionSelect, dialogs.AgentBrowserActionSelectDefault:
+					applyDefault := action == dialogs.AgentBrowserActionSelectDefault ||
+						c.pendingPreference == preferenceTargetDefaultAgent
+					cmd := c.handleAgentSelection(option, applyDefault)
 					c.pendingPreference = preferenceTargetNone
 					return c, cmd
+				case dialogs.AgentBrowserActionInstall:
+					return c, func() tea.Msg { return InstallAgentMsg{Option: option} }
+				}
+				if !c.agentBrowser.IsVisible() {
+					c.pendingPreference = preferenceTargetNone
 				}
 				return c, nil
 			}
@@ -533,6 +631,10 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 			}
 		}
 
+		if key.Matches(msg, c.keyMap.UndoChange) {
+			return c, c.undoLastChange()
+		}
+
 		refreshMenu = true
 		if c.focused {
 			if handled, selection, ok := c.slashMenu.HandleKey(msg); handled {
@@ -546,24 +648,36 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 			}
 		}
 		if c.focused {
-			switch msg.String() {
-			case "ctrl+j":
+			switch {
+			case key.Matches(msg, c.keyMap.Newline):
 				c.input.InsertRune('\n')
 				return c, nil
-			case "enter":
+			case key.Matches(msg, c.keyMap.HistorySearch):
+				c.input.StartSearch()
+				return c, nil
+			case key.Matches(msg, c.keyMap.ComposeEditor):
+				return c, c.openEditor()
+			case key.Matches(msg, c.keyMap.PasteImage):
+				return c, c.pasteImage()
+			case msg.String() == "up" && c.input.HistoryPrev():
+				return c, nil
+			case msg.String() == "down" && c.input.HistoryNext():
+				return c, nil
+			case key.Matches(msg, c.keyMap.Submit):
 				input := c.GetInput()
 				trimmed := strings.TrimSpace(input)
 				if trimmed == "" {
 					c.ClearInput()
 					return c, nil
 				}
+				c.rememberPrompt(input)
 
 				if strings.EqualFold(trimmed, "/models") {
 					c.ClearInput()
 					c.slashMenu.Close()
 					c.commandPalette.Close()
 					c.themePicker.Close()
-					c.modelPicker.Open()
+					c.openModelPicker(dialogs.ModelScopeChat)
 					return c, nil
 				}
 
@@ -573,12 +687,70 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 					c.commandPalette.Close()
 					c.modelPicker.Close()
 					c.themePicker.Close()
-					if c.agentPicker != nil {
-						c.agentPicker.Open()
+					if c.agentBrowser != nil {
+						c.agentBrowser.Open()
 					}
 					return c, nil
 				}
 
+				if strings.EqualFold(trimmed, "/keys") {
+					c.ClearInput()
+					c.slashMenu.Close()
+					c.showKeyBindings()
+					return c, nil
+				}
+
+				if strings.EqualFold(trimmed, "/profiles") {
+					c.openProfilePicker()
+					return c, nil
+				}
+
+				if strings.EqualFold(trimmed, "/changes") {
+					c.openChangesDialog()
+					return c, nil
+				}
+
+				if strings.EqualFold(trimmed, "/context") {
+					c.openContextDialog()
+					return c, nil
+				}
+
+				if arg, ok := commandArgument(trimmed, "/attach"); ok {
+					c.attachFile(arg)
+					return c, nil
+				}
+
+				if strings.EqualFold(trimmed, "/paste") {
+					c.ClearInput()
+					return c, c.pasteImage()
+				}
+
+				if arg, ok := commandArgument(trimmed, "/detach"); ok {
+					c.detachFile(arg)
+					return c, nil
+				}
+
+				if strings.EqualFold(trimmed, "/usage") {
+					c.ClearInput()
+					c.slashMenu.Close()
+					c.showUsage()
+					return c, nil
+				}
+
+				if arg, ok := commandArgument(trimmed, "/compare"); ok {
+					c.runCompareCommand(arg)
+					return c, nil
+				}
+
+				if arg, ok := commandArgument(trimmed, "/promote"); ok {
+					c.promoteAnswer(arg)
+					return c, nil
+				}
+
+				if arg, ok := commandArgument(trimmed, "/layout"); ok {
+					return c, c.runLayoutCommand(arg)
+				}
+
 				if strings.EqualFold(trimmed, "/theme") {
 					c.ClearInput()
 					c.slashMenu.Close()
@@ -589,11 +761,12 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 					return c, nil
 				}
 
-				c.AddMessage("user", input)
+				if command, args, ok := c.matchPromptCommand(trimmed); ok {
+					return c, c.runPromptCommand(command, args)
+				}
+
 				c.ClearInput()
-				return c, tea.Cmd(func() tea.Msg {
-					return SubmitMsg{Content: input}
-				})
+				return c, c.submitPrompt(input, nil, nil)
 			}
 		}
 	}
@@ -638,6 +811,32 @@ func (c *Chat) Update(msg tea.Msg) (*Chat, tea.Cmd) {
 	return c, tea.Batch(cmds...)
 }
 
+// submitPrompt records input in the transcript with what is attached to it
+// and sends it, to agent and model when they are set.
+func (c *Chat) submitPrompt(input string, agent *stores.AgentSelection, model *stores.ModelOption) tea.Cmd {
+	if c.comparedConversation(c.activeConversationID) {
+		// Every compared conversation keeps its own model.
+		return c.submitCompare(input, agent)
+	}
+	context := c.ContextWindow().Request()
+	if len(context.Images) > 0 {
+		c.AddMessageWithMetadata("user", input+c.attachmentSummary(), map[string]interface{}{"images": context.Images}, nil)
+	} else {
+		c.AddMessage("user", input+c.attachmentSummary())
+	}
+	submit := SubmitMsg{
+		Content:        input,
+		Context:        context,
+		Agent:          agent,
+		Model:          model,
+		ConversationID: c.activeConversationID,
+		Attached:       c.attachedPaths(),
+	}
+	return tea.Cmd(func() tea.Msg {
+		return submit
+	})
+}
+
 func isKeyPress(msg tea.Msg) bool {
 	_, ok := msg.(tea.KeyPressMsg)
 	return ok
@@ -648,7 +847,10 @@ func (c *Chat) shouldSkipInputUpdate(msg tea.Msg) bool {
 		if c.modelPicker.IsVisible() {
 			return true
 		}
-		if c.agentPicker != nil && c.agentPicker.IsVisible() {
+		if c.agentBrowser != nil && c.agentBrowser.IsVisible() {
+			return true
+		}
+		if c.profilePicker.IsVisible() || c.changesDialog.IsVisible() || c.contextDialog.IsVisible() || c.argumentDialog.IsVisible() {
 			return true
 		}
 	}
@@ -765,3 +967,52 @@ func splitCommand(rest string) (fragment string, remainder string) {
 func runeLen(s string) int {
 	return len([]rune(s))
 }
+
+// SetPromptHistory loads the project's prompt history into the input and
+// records new prompts in it. The draft left in the active conversation last
+// session goes back into the input; drafts of other conversations, which are
+// not restored, can be recalled like sent prompts.
+func (c *Chat) SetPromptHistory(history *prompthistory.History) {
+	c.promptHistory = history
+	drafts := history.Drafts()
+	c.input.SetHistory(append(history.Entries(), drafts.Other...))
+	if drafts.Active != "" {
+		if id := c.ensureConversationStore().ActiveID(); id != "" {
+			c.ensureConversationStore().SetDraft(id, drafts.Active)
+		}
+		c.restoreDraft()
+	}
+}
+
+// SaveDrafts stores the unsent input of every conversation so it survives
+// the end of the session.
+func (c *Chat) SaveDrafts() {
+	if c == nil || c.promptHistory == nil {
+		return
+	}
+	c.saveDraft()
+	store := c.ensureConversationStore()
+	var drafts prompthistory.Drafts
+	for _, conv := range store.Conversations() {
+		if conv.ID == store.ActiveID() {
+			drafts.Active = conv.Draft
+		} else if conv.Draft != "" {
+			drafts.Other = append(drafts.Other, conv.Draft)
+		}
+	}
+	if err := c.promptHistory.SaveDrafts(drafts); err != nil {
+		logging.Warn("saving prompt drafts failed", "error", err)
+	}
+}
+
+// rememberPrompt records a sent prompt for recall. Slash commands are left
+// out so the history holds only what was said to the agent.
+func (c *Chat) rememberPrompt(prompt string) {
+	if strings.HasPrefix(strings.TrimSpace(prompt), "/") {
+		return
+	}
+	c.input.Remember(prompt)
+	if err := c.promptHistory.Add(prompt); err != nil {
+		logging.Warn("saving prompt history failed", "error", err)
+	}
+}
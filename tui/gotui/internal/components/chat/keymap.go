@@ -0,0 +1,90 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gotui/internal/components/chatcomponents"
+	"gotui/internal/keybindings"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+)
+
+// slashCommandBindings ties slash commands to the key map action that performs
+// the same operation so menus can advertise the effective shortcut.
+var slashCommandBindings = map[string]func(keybindings.KeyMap) key.Binding{
+	"layout": func(km keybindings.KeyMap) key.Binding { return km.ToggleMode },
+	"keys":   func(km keybindings.KeyMap) key.Binding { return km.Help },
+}
+
+// SetKeyMap applies the effective key map to the chat input, help bar and menus.
+func (c *Chat) SetKeyMap(km keybindings.KeyMap) {
+	if c == nil {
+		return
+	}
+	c.keyMap = km
+	if c.helpBar != nil {
+		c.helpBar.SetKeyMap(km)
+	}
+	if c.windowManager != nil {
+		c.windowManager.SetKeyMap(km)
+	}
+	commands := c.slashCommands()
+	c.slashMenu.SetCommands(commands)
+	c.commandPalette.UpdateCommands(commands)
+}
+
+// SetKeymapReport records how the key map was built so /keys can show problems.
+func (c *Chat) SetKeymapReport(report keybindings.Report) {
+	if c == nil {
+		return
+	}
+	c.keymapReport = report
+}
+
+func (c *Chat) slashCommands() []chatcomponents.SlashCommand {
+	commands := defaultSlashCommands()
+	for i := range commands {
+		lookup, ok := slashCommandBindings[commands[i].Name]
+		if !ok {
+			continue
+		}
+		binding := lookup(c.keyMap)
+		if binding.Enabled() {
+			commands[i].Shortcut = binding.Help().Key
+		}
+	}
+	commands = append(commands, c.layoutSlashCommands()...)
+	return append(commands, c.customSlashCommands()...)
+}
+
+func (c *Chat) showKeyBindings() {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("⌨️  Key bindings (%s preset)\n", c.keymapReport.Preset))
+	for _, action := range keybindings.Actions() {
+		binding := action.Binding(&c.keyMap)
+		keys := "(disabled)"
+		if binding.Enabled() && len(binding.Keys()) > 0 {
+			keys = strings.Join(binding.Keys(), ", ")
+		}
+		b.WriteString(fmt.Sprintf("  %-24s %-24s %s\n", action.Name, keys, binding.Help().Desc))
+	}
+	for _, conflict := range c.keymapReport.Conflicts {
+		b.WriteString("  ⚠️  " + conflict.String() + "\n")
+	}
+	for _, warning := range c.keymapReport.Warnings {
+		b.WriteString("  ⚠️  " + warning + "\n")
+	}
+	for _, err := range c.keymapReport.Errors {
+		b.WriteString("  ❌ " + err + "\n")
+	}
+	c.AddMessage("system", strings.TrimRight(b.String(), "\n"))
+}
+
+func (c *Chat) toggleLayoutFromCommand() {
+	if c.ToggleLayoutMode() {
+		c.AddMessage("system", "🪟 Switched to window mode")
+		return
+	}
+	c.AddMessage("system", "🪟 Returned to panel mode")
+}
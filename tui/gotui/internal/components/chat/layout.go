@@ -264,7 +264,13 @@ func (c *Chat) renderChatArea(mainWidth int) (string, int) {
 
 	helpbarView, helpbarHeight := c.renderHelpBar(chatWidth)
 
-	viewportHeight := c.chatHeight - statusHeight
+	chipsView := c.renderAttachmentChips(chatWidth)
+	chipsHeight := 0
+	if chipsView != "" {
+		chipsHeight = lipgloss.Height(chipsView)
+	}
+
+	viewportHeight := c.chatHeight - statusHeight - chipsHeight
 	if viewportHeight < 1 {
 		viewportHeight = 1
 	}
@@ -282,6 +288,9 @@ func (c *Chat) renderChatArea(mainWidth int) (string, int) {
 	if statusView != "" {
 		sections = append(sections, statusView)
 	}
+	if chipsView != "" {
+		sections = append(sections, chipsView)
+	}
 	sections = append(sections, inputArea)
 	if helpbarView != "" {
 		sections = append(sections, helpbarView)
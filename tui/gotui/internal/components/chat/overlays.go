@@ -4,18 +4,44 @@ import (
 	"fmt"
 
 	"gotui/internal/components/chatcomponents"
+	"gotui/internal/components/dialogs"
+	"gotui/internal/components/uicomponents/markdown"
 	"gotui/internal/stores"
 	"gotui/internal/styles"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
+// openModelPicker opens the model picker on the active conversation's model
+// or, for dialogs.ModelScopeDefault, on the default for new conversations.
+func (c *Chat) openModelPicker(scope dialogs.ModelPickerScope) {
+	var current *stores.ModelOption
+	if c.selectedModel != nil {
+		model := stores.ModelOption(*c.selectedModel)
+		current = &model
+	}
+	c.modelPicker.Open(current, scope)
+}
+
+// handleModelSelection applies a picked model to the active conversation
+// or, with applyDefault, makes it the default for new conversations and
+// leaves the active one alone.
 func (c *Chat) handleModelSelection(option chatcomponents.ModelOption, applyDefault bool) tea.Cmd {
 	c.modelPicker.Close()
 	c.commandPalette.Close()
 	c.settingsDialog.Close()
 	c.input.SetValueAndCursor("", 0)
 	c.slashMenu.Close()
+	c.pendingPreference = preferenceTargetNone
+	settings := stores.SharedApplicationSettingsStore()
+	settings.RecordRecentModel(stores.ModelOption(option))
+	if applyDefault {
+		copy := stores.ModelOption(option)
+		settings.SetDefaultModel(&copy)
+		c.AddMessage("system", fmt.Sprintf("📌 Default model for new chats set to %s (%s)", option.Name, option.Provider))
+		return nil
+	}
+
 	opt := option
 	c.selectedModel = &opt
 	if store := c.ensureConversationStore(); store != nil {
@@ -27,21 +53,15 @@ func (c *Chat) handleModelSelection(option chatcomponents.ModelOption, applyDefa
 		}
 	}
 	c.refreshConversationsFromStore(true)
-	c.AddMessage("system", fmt.Sprintf("🤖 Model set to %s (%s)", option.Name, option.Provider))
-	if applyDefault {
-		copy := stores.ModelOption(option)
-		stores.SharedApplicationSettingsStore().SetDefaultModel(&copy)
-		c.AddMessage("system", fmt.Sprintf("📌 Default model updated to %s", option.Name))
-	}
-	c.pendingPreference = preferenceTargetNone
+	c.AddMessage("system", fmt.Sprintf("🤖 Model for this chat set to %s (%s)", option.Name, option.Provider))
 	return tea.Cmd(func() tea.Msg {
 		return ModelSelectedMsg{Option: option}
 	})
 }
 
 func (c *Chat) handleAgentSelection(option chatcomponents.AgentOption, applyDefault bool) tea.Cmd {
-	if c.agentPicker != nil {
-		c.agentPicker.Close()
+	if c.agentBrowser != nil {
+		c.agentBrowser.Close()
 	}
 	c.commandPalette.Close()
 	c.settingsDialog.Close()
@@ -83,12 +103,27 @@ func (c *Chat) handleThemeSelection(preset styles.ThemePreset) tea.Cmd {
 	if !styles.SetThemeByName(preset.Name) {
 		styles.SetTheme(preset.Theme)
 	}
+	c.RefreshTheme()
 	c.AddMessage("system", fmt.Sprintf("🎨 Theme set to %s", preset.Name))
 	return tea.Cmd(func() tea.Msg {
 		return ThemeSelectedMsg{Preset: preset}
 	})
 }
 
+// RefreshTheme re-renders the messages of the active conversation and of
+// every conversation window, in window and compare mode alike, after the
+// active theme or the set of available themes changes.
+func (c *Chat) RefreshTheme() {
+	markdown.ResetCache()
+	c.themePicker.SetOptions(styles.PresetThemes())
+	if c.viewport != nil {
+		c.viewport.RefreshTheme()
+	}
+	if c.windowManager != nil {
+		c.windowManager.RefreshTheme()
+	}
+}
+
 // ToggleCommandPalette toggles the global command picker overlay.
 func (c *Chat) ToggleCommandPalette() {
 	if c.commandPalette == nil {
@@ -99,8 +134,8 @@ func (c *Chat) ToggleCommandPalette() {
 		return
 	}
 	c.modelPicker.Close()
-	if c.agentPicker != nil {
-		c.agentPicker.Close()
+	if c.agentBrowser != nil {
+		c.agentBrowser.Close()
 	}
 	c.slashMenu.Close()
 	c.commandPalette.UpdateCommands(c.slashMenu.Commands())
@@ -0,0 +1,44 @@
+package chat
+
+import (
+	"fmt"
+
+	"gotui/internal/components/chatcomponents"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// ProfileSelectedMsg is emitted when a server profile is chosen in the picker.
+type ProfileSelectedMsg struct {
+	Name string
+}
+
+// SetServerProfiles updates the profiles offered by the profile picker.
+func (c *Chat) SetServerProfiles(options []chatcomponents.ServerProfileOption, active string) {
+	if c.profilePicker == nil {
+		return
+	}
+	c.profilePicker.SetOptions(options, active)
+}
+
+func (c *Chat) openProfilePicker() {
+	c.input.SetValueAndCursor("", 0)
+	c.slashMenu.Close()
+	c.commandPalette.Close()
+	c.modelPicker.Close()
+	c.themePicker.Close()
+	if c.agentBrowser != nil {
+		c.agentBrowser.Close()
+	}
+	c.profilePicker.Open()
+}
+
+func (c *Chat) handleProfileSelection(option chatcomponents.ServerProfileOption) tea.Cmd {
+	c.profilePicker.Close()
+	c.commandPalette.Close()
+	c.slashMenu.Close()
+	c.AddMessage("system", fmt.Sprintf("🔌 Switching to server profile %s (%s)", option.Name, option.Endpoint))
+	return tea.Cmd(func() tea.Msg {
+		return ProfileSelectedMsg{Name: option.Name}
+	})
+}
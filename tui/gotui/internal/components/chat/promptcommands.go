@@ -0,0 +1,226 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/components/chatcomponents"
+	"gotui/internal/gitrepo"
+	"gotui/internal/promptcommands"
+	"gotui/internal/stores"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+// promptExpandedMsg carries a custom command's template once its variables
+// have been filled in.
+type promptExpandedMsg struct {
+	command promptcommands.Command
+	values  map[string]string
+	content string
+	err     error
+}
+
+// SetPromptCommands installs the user-defined slash commands. Commands named
+// like a built-in one are ignored.
+func (c *Chat) SetPromptCommands(commands []promptcommands.Command) {
+	if c == nil {
+		return
+	}
+	c.promptCommands = commands
+	all := c.slashCommands()
+	c.slashMenu.SetCommands(all)
+	c.commandPalette.UpdateCommands(all)
+}
+
+// customSlashCommands lists the user-defined commands for the menus.
+func (c *Chat) customSlashCommands() []chatcomponents.SlashCommand {
+	builtin := make(map[string]bool)
+	for _, command := range defaultSlashCommands() {
+		builtin[strings.ToLower(command.Name)] = true
+	}
+	var commands []chatcomponents.SlashCommand
+	for _, command := range c.promptCommands {
+		if builtin[strings.ToLower(command.Name)] {
+			continue
+		}
+		description := command.Description
+		if description == "" {
+			description = "Custom prompt"
+		}
+		commands = append(commands, chatcomponents.SlashCommand{
+			Name:        command.Name,
+			Description: description,
+			Usage:       command.Usage(),
+		})
+	}
+	return commands
+}
+
+// matchPromptCommand reports whether input invokes a user-defined command
+// and returns it with the text typed after it.
+func (c *Chat) matchPromptCommand(input string) (promptcommands.Command, string, bool) {
+	for _, command := range c.customSlashCommands() {
+		if arg, ok := commandArgument(input, "/"+command.Name); ok {
+			for _, prompt := range c.promptCommands {
+				if strings.EqualFold(prompt.Name, command.Name) {
+					return prompt, arg, true
+				}
+			}
+		}
+	}
+	return promptcommands.Command{}, "", false
+}
+
+// runPromptCommand parses the arguments of a user-defined command, asking
+// for required ones that were not given, and expands it.
+func (c *Chat) runPromptCommand(command promptcommands.Command, args string) tea.Cmd {
+	c.ClearInput()
+	values, missing := command.ParseArgs(args)
+	if len(missing) == 0 {
+		return c.expandPromptCommand(command, values)
+	}
+	fields := make([]chatcomponents.ArgumentField, 0, len(missing))
+	for _, arg := range missing {
+		fields = append(fields, chatcomponents.ArgumentField{Name: arg.Name, Description: arg.Description})
+	}
+	c.pendingPrompt = &pendingPromptCommand{command: command, values: values}
+	c.argumentDialog.Open(command.Usage(), fields)
+	return nil
+}
+
+// pendingPromptCommand is a command waiting for the argument dialog.
+type pendingPromptCommand struct {
+	command promptcommands.Command
+	values  map[string]string
+}
+
+// finishArguments expands the pending command with the arguments entered in
+// the dialog, or drops it when the dialog was cancelled.
+func (c *Chat) finishArguments(values map[string]string, ok bool) tea.Cmd {
+	pending := c.pendingPrompt
+	c.pendingPrompt = nil
+	if !ok || pending == nil {
+		return nil
+	}
+	for name, value := range values {
+		pending.values[name] = value
+	}
+	return c.expandPromptCommand(pending.command, pending.values)
+}
+
+// confirmOutsideRoot answers the question whether a project command may read
+// a file outside the project: y expands it again with the file, any other
+// key drops it.
+func (c *Chat) confirmOutsideRoot(msg tea.KeyPressMsg) tea.Cmd {
+	pending := c.outsideRootPrompt
+	c.outsideRootPrompt = nil
+	if msg.String() != "y" && msg.String() != "Y" {
+		c.AddMessage("system", fmt.Sprintf("🚫 /%s cancelled", pending.command.Name))
+		return nil
+	}
+	return c.expandPromptCommandOutside(pending.command, pending.values, true)
+}
+
+// expandPromptCommand fills in the template in the background, since the
+// selection and the git diff come from external programs.
+func (c *Chat) expandPromptCommand(command promptcommands.Command, values map[string]string) tea.Cmd {
+	return c.expandPromptCommandOutside(command, values, false)
+}
+
+func (c *Chat) expandPromptCommandOutside(command promptcommands.Command, values map[string]string, allowOutside bool) tea.Cmd {
+	root := c.projectRoot()
+	vars := promptcommands.Variables{
+		Root:         root,
+		AllowOutside: allowOutside,
+		Selection:    agentcontext.SelectionText,
+		Diff: func() (string, error) {
+			return workingTreeDiff(root)
+		},
+	}
+	return func() tea.Msg {
+		content, err := command.Expand(values, vars)
+		return promptExpandedMsg{command: command, values: values, content: content, err: err}
+	}
+}
+
+// workingTreeDiff returns the uncommitted changes in root, staged or not.
+func workingTreeDiff(root string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	repo := gitrepo.New(root)
+	diff, err := repo.Run(ctx, "diff", "HEAD")
+	if err != nil {
+		// A repository without commits has no HEAD to compare against.
+		diff, err = repo.Run(ctx, "diff", "--cached")
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "", errors.New("there are no uncommitted changes")
+	}
+	return strings.TrimRight(diff, "\n"), nil
+}
+
+// finishPromptCommand sends an expanded template to the command's agent and
+// model, or the current ones.
+func (c *Chat) finishPromptCommand(msg promptExpandedMsg) tea.Cmd {
+	var outside *promptcommands.OutsideRootError
+	if errors.As(msg.err, &outside) {
+		c.outsideRootPrompt = &pendingPromptCommand{command: msg.command, values: msg.values}
+		c.AddMessage("system", fmt.Sprintf("⚠️  /%s is defined by this project and wants to send %s, which is outside the project. Send it? (y/N)", msg.command.Name, outside.Path))
+		return nil
+	}
+	if msg.err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ /%s: %v", msg.command.Name, msg.err))
+		return nil
+	}
+	agent, err := c.resolvePromptAgent(msg.command.Agent)
+	if err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ /%s: %v", msg.command.Name, err))
+		return nil
+	}
+	model, err := c.resolvePromptModel(msg.command.Model)
+	if err != nil {
+		c.AddMessage("system", fmt.Sprintf("❌ /%s: %v", msg.command.Name, err))
+		return nil
+	}
+	return c.submitPrompt(msg.content, agent, model)
+}
+
+func (c *Chat) resolvePromptAgent(name string) (*stores.AgentSelection, error) {
+	if name == "" {
+		return nil, nil
+	}
+	if c.agentStore != nil {
+		for _, agent := range c.agentStore.Agents() {
+			if strings.EqualFold(agent.ID, name) || strings.EqualFold(agent.Name, name) {
+				return &stores.AgentSelection{
+					ID:           agent.ID,
+					Name:         agent.Name,
+					AgentType:    "",
+					AgentDetails: agent.Description,
+				}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("agent %q is not available", name)
+}
+
+func (c *Chat) resolvePromptModel(name string) (*stores.ModelOption, error) {
+	if name == "" {
+		return nil, nil
+	}
+	for _, option := range c.modelOptions {
+		if strings.EqualFold(option.Name, name) || strings.EqualFold(option.Provider+"/"+option.Name, name) {
+			model := option
+			return &model, nil
+		}
+	}
+	return nil, fmt.Errorf("model %q is not available", name)
+}
@@ -1,6 +1,7 @@
 package chat
 
 import (
+	"slices"
 	"strings"
 	"time"
 
@@ -149,6 +150,49 @@ func (c *Chat) applyDefaultModelToAllConversations() {
 	}
 }
 
+// ReplaceRemovedModels moves conversations whose model the server no longer
+// lists to the default model, or the first listed one if the default is gone
+// too. It returns the names of the replaced models.
+func (c *Chat) ReplaceRemovedModels() []string {
+	if c == nil || c.modelStore == nil {
+		return nil
+	}
+	models := c.modelStore.Models()
+	if len(models) == 0 {
+		return nil
+	}
+	listed := func(model *stores.ModelOption) bool {
+		return model != nil && slices.ContainsFunc(models, func(m stores.ModelOption) bool {
+			return m.Name == model.Name && m.Provider == model.Provider
+		})
+	}
+	fallback := models[0]
+	if settings := stores.SharedApplicationSettingsStore().Settings(); listed(settings.DefaultModel) {
+		fallback = *settings.DefaultModel
+	} else if listed(c.preferredModel) {
+		fallback = *c.preferredModel
+	}
+
+	store := c.ensureConversationStore()
+	var removed []string
+	for _, conv := range store.Conversations() {
+		model := conv.Options.SelectedModel
+		if model == nil || listed(model) {
+			continue
+		}
+		if !slices.Contains(removed, model.Name) {
+			removed = append(removed, model.Name)
+		}
+		copy := fallback
+		store.SetSelectedModel(conv.ID, &copy)
+		stores.SharedConversationStateStore().SetSelectedModel(conv.ID, &copy)
+	}
+	if len(removed) > 0 {
+		c.refreshConversationsFromStore(true)
+	}
+	return removed
+}
+
 func (c *Chat) defaultAgentSelection() *stores.AgentSelection {
 	if c == nil {
 		return nil
@@ -353,10 +397,12 @@ func sampleDiffLines() []diffview.DiffLine {
 
 func (c *Chat) createNewConversation() tea.Cmd {
 	store := c.ensureConversationStore()
+	c.saveDraft()
 	conv := store.CreateConversation("", stores.ConversationOptions{})
 	if conv == nil {
 		return nil
 	}
+	c.restoreDraft()
 
 	settings := stores.SharedApplicationSettingsStore().Settings()
 	if stateStore := c.ensureApplicationStateStore(); stateStore != nil {
@@ -433,9 +479,11 @@ func (c *Chat) switchConversation(conversationID string) bool {
 		return false
 	}
 	store := c.ensureConversationStore()
+	c.saveDraft()
 	if !store.SetActive(conversationID) {
 		return false
 	}
+	c.restoreDraft()
 
 	c.refreshConversationsFromStore(true)
 	c.refreshActiveConversationView()
@@ -446,6 +494,25 @@ func (c *Chat) switchConversation(conversationID string) bool {
 	return true
 }
 
+// saveDraft keeps the unsent input with the active conversation.
+func (c *Chat) saveDraft() {
+	if c.input.Searching() {
+		c.input.HandleSearchKey(tea.KeyPressMsg{Code: tea.KeyEnter}, false)
+	}
+	if id := c.ensureConversationStore().ActiveID(); id != "" {
+		c.ensureConversationStore().SetDraft(id, c.input.RawValue())
+	}
+}
+
+// restoreDraft puts the active conversation's unsent input back.
+func (c *Chat) restoreDraft() {
+	c.input.Clear()
+	if conv := c.ensureConversationStore().ActiveConversation(); conv != nil && conv.Draft != "" {
+		c.input.SetValue(conv.Draft)
+	}
+	c.slashMenu.Close()
+}
+
 func (c *Chat) getActiveConversation() *Conversation {
 	store := c.ensureConversationStore()
 	conv := store.ActiveConversation()
@@ -0,0 +1,44 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	"gotui/internal/stores"
+	"gotui/internal/usage"
+)
+
+// showUsage prints the active conversation's usage per reply, followed by
+// the conversation and session totals and any budgets.
+func (c *Chat) showUsage() {
+	store := stores.SharedUsageStore()
+	conversationID := stores.SharedConversationStore().ActiveID()
+	records := store.Records(conversationID)
+	session := store.Session()
+	if len(records) == 0 && session.IsZero() {
+		c.AddMessage("system", "📊 No token usage reported yet. Usage appears once the server includes it in its replies.")
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("📊 Token usage\n")
+	for _, record := range records {
+		model := record.Model
+		if model == "" {
+			model = "unknown model"
+		}
+		b.WriteString(fmt.Sprintf("  %s  %-24s %s\n", record.At.Format("15:04:05"), model, usage.Describe(record.Usage)))
+	}
+	conversation := store.Conversation(conversationID)
+	b.WriteString("  This chat: " + usage.Describe(conversation) + "\n")
+	b.WriteString("  Session:   " + usage.Describe(session) + "\n")
+
+	budgets := store.Budgets()
+	if !budgets.Conversation.IsZero() {
+		b.WriteString(fmt.Sprintf("  Chat budget:    %.0f%% of %s\n", budgets.Conversation.Fraction(conversation)*100, budgets.Conversation))
+	}
+	if !budgets.Session.IsZero() {
+		b.WriteString(fmt.Sprintf("  Session budget: %.0f%% of %s\n", budgets.Session.Fraction(session)*100, budgets.Session))
+	}
+	c.AddMessage("system", strings.TrimRight(b.String(), "\n"))
+}
@@ -3,6 +3,7 @@ package chat
 import (
 	"strings"
 
+	"github.com/charmbracelet/bubbles/v2/key"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 
@@ -14,6 +15,10 @@ func (c *Chat) ToggleLayoutMode() bool {
 	if c.windowManager == nil {
 		return false
 	}
+	if c.compare != nil {
+		c.endCompare()
+		return c.isWindowModeActive()
+	}
 	c.windowManager.SetSize(c.width, c.height)
 	mode := c.windowManager.ToggleMode()
 	if mode == windows.ModeWindow && c.windowManager.AutoTileEnabled() {
@@ -35,7 +40,9 @@ func (c *Chat) ToggleAutoTile() (bool, tea.Cmd) {
 		}
 		return c.windowManager.AutoTileEnabled(), nil
 	}
-	return c.windowManager.ToggleAutoTile()
+	enabled, cmd := c.windowManager.ToggleAutoTile()
+	c.saveWindowLayoutIfChanged()
+	return enabled, cmd
 }
 
 func (c *Chat) isWindowModeActive() bool {
@@ -97,6 +104,7 @@ func (c *Chat) handleWindowModeMsg(msg tea.Msg) (tea.Cmd, bool) {
 	}
 
 	if cmd, handled := c.windowManager.HandleMessage(msg); handled {
+		c.saveWindowLayoutIfChanged()
 		return cmd, true
 	}
 
@@ -110,3 +118,40 @@ func (c *Chat) handleWindowModeMsg(msg tea.Msg) (tea.Cmd, bool) {
 
 	return nil, false
 }
+
+// WindowKeysActive reports whether keys move and resize windows instead of
+// going to the input.
+func (c *Chat) WindowKeysActive() bool {
+	return c.windowManager != nil && c.windowManager.KeyMode()
+}
+
+// ToggleWindowKeys turns window keys on or off in window mode and reports
+// whether they are on.
+func (c *Chat) ToggleWindowKeys() bool {
+	if c.windowManager == nil {
+		return false
+	}
+	return c.windowManager.SetKeyMode(!c.windowManager.KeyMode())
+}
+
+// HandleWindowKey applies a key while window keys are on; the window keys
+// binding itself turns them off again.
+func (c *Chat) HandleWindowKey(msg tea.KeyPressMsg) tea.Cmd {
+	if c.windowManager == nil {
+		return nil
+	}
+	if key.Matches(msg, c.keyMap.WindowKeys) {
+		c.windowManager.SetKeyMode(false)
+		return nil
+	}
+	cmd := c.windowManager.HandleKey(msg)
+	c.saveWindowLayoutIfChanged()
+	return cmd
+}
+
+// SetWindowMode switches to the window layout when enabled, or back to panels.
+func (c *Chat) SetWindowMode(enabled bool) {
+	if c.isWindowModeActive() != enabled {
+		c.ToggleLayoutMode()
+	}
+}
@@ -120,7 +120,13 @@ func (c *Chat) renderWindowChatColumn(win *windows.ConversationWindow, width, he
 		vp.SetSize(width, viewportHeight)
 	}
 	if messages := c.windowMessagesForAgent(win.ID, agentIndex, width); messages != nil {
+		// Setting messages jumps to the bottom; keep the place of a window
+		// the user scrolled up in.
+		follow, offset := vp.AtBottom(), vp.ScrollOffset()
 		vp.SetMessages(messages)
+		if !follow {
+			vp.SetScrollOffset(offset)
+		}
 	}
 
 	history := lipgloss.NewStyle().
@@ -206,10 +212,16 @@ func (c *Chat) windowInputView(width int, agentIndex int, active bool) (string,
 	if active {
 		c.input.SetSize(width, height)
 		view := lipgloss.NewStyle().Width(width).Render(c.input.View())
+		if chips := c.renderAttachmentChips(width); chips != "" {
+			view = lipgloss.JoinVertical(lipgloss.Left, chips, view)
+		}
 		return view, lipgloss.Height(view)
 	}
 	theme := styles.CurrentTheme()
 	label := fmt.Sprintf("Activate to chat with agent %d", agentIndex)
+	if c.comparedConversation(c.activeConversationID) {
+		label = "Prompts go to every compared model"
+	}
 	pl := lipgloss.NewStyle().
 		Width(width).
 		Height(height).
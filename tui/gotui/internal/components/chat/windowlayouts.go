@@ -0,0 +1,163 @@
+package chat
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/components/chatcomponents"
+	"gotui/internal/logging"
+	"gotui/internal/windowlayout"
+)
+
+// loadLayoutCommand prefixes the menu entries that switch to a saved layout.
+const loadLayoutCommand = "layout load "
+
+// SetWindowLayouts restores the window positions of the last session and
+// offers the project's saved layouts in the menus.
+func (c *Chat) SetWindowLayouts(store *windowlayout.Store) {
+	if c == nil {
+		return
+	}
+	c.windowLayouts = store
+	if layout, ok := store.Last(); ok && c.windowManager != nil {
+		c.enqueueCmd(c.windowManager.ApplyLayout(layout))
+	}
+	c.refreshSlashCommands()
+}
+
+// SaveWindowLayout records the window positions for the next session.
+func (c *Chat) SaveWindowLayout() {
+	if c == nil || c.windowManager == nil || c.windowLayouts == nil {
+		return
+	}
+	if err := c.windowLayouts.SetLast(c.windowManager.Layout()); err != nil {
+		logging.Warn("saving window layout failed", "error", err)
+	}
+}
+
+// saveWindowLayoutIfChanged saves the window positions after the user moved
+// a window or toggled auto-tiling.
+func (c *Chat) saveWindowLayoutIfChanged() {
+	if c.windowManager != nil && c.windowManager.LayoutChanged() {
+		c.SaveWindowLayout()
+	}
+}
+
+// runLayoutCommand handles "/layout [save|load|delete <name>|list]"; without
+// arguments it toggles between panels and windows.
+func (c *Chat) runLayoutCommand(args string) tea.Cmd {
+	c.ClearInput()
+	c.slashMenu.Close()
+	verb, name := splitCommand(args)
+	name = strings.TrimSpace(name)
+	switch strings.ToLower(verb) {
+	case "":
+		c.toggleLayoutFromCommand()
+	case "save":
+		c.saveNamedLayout(name)
+	case "load":
+		return c.loadNamedLayout(name)
+	case "delete":
+		c.deleteNamedLayout(name)
+	case "list":
+		c.listLayouts()
+	default:
+		c.AddMessage("error", fmt.Sprintf("❌ Unknown /layout command %q; use save, load, delete or list", verb))
+	}
+	return nil
+}
+
+func (c *Chat) saveNamedLayout(name string) {
+	if name == "" {
+		c.AddMessage("error", "❌ Name the layout to save, e.g. /layout save review")
+		return
+	}
+	if c.windowManager == nil || c.windowLayouts == nil {
+		return
+	}
+	if err := c.windowLayouts.Save(name, c.windowManager.Layout()); err != nil {
+		c.AddMessage("error", fmt.Sprintf("❌ Failed to save window layout: %v", err))
+		return
+	}
+	c.refreshSlashCommands()
+	c.AddMessage("system", fmt.Sprintf("🪟 Saved window layout %q; switch back with /layout load %s or the command palette", name, name))
+}
+
+// loadNamedLayout moves the windows to a saved layout, switching to window
+// mode if needed.
+func (c *Chat) loadNamedLayout(name string) tea.Cmd {
+	if name == "" {
+		c.AddMessage("error", "❌ Name the layout to load, e.g. /layout load review")
+		return nil
+	}
+	if c.windowManager == nil || c.windowLayouts == nil {
+		return nil
+	}
+	layout, stored, ok := c.windowLayouts.Named(name)
+	if !ok {
+		c.AddMessage("error", fmt.Sprintf("❌ No window layout named %q; /layout list shows the saved ones", name))
+		return nil
+	}
+	c.endCompare()
+	if !c.isWindowModeActive() {
+		c.ToggleLayoutMode()
+	}
+	cmd := c.windowManager.ApplyLayout(layout)
+	c.SaveWindowLayout()
+	c.AddMessage("system", fmt.Sprintf("🪟 Switched to window layout %q", stored))
+	return cmd
+}
+
+func (c *Chat) deleteNamedLayout(name string) {
+	if name == "" {
+		c.AddMessage("error", "❌ Name the layout to delete, e.g. /layout delete review")
+		return
+	}
+	deleted, err := c.windowLayouts.Delete(name)
+	switch {
+	case err != nil:
+		c.AddMessage("error", fmt.Sprintf("❌ Failed to delete window layout: %v", err))
+	case !deleted:
+		c.AddMessage("error", fmt.Sprintf("❌ No window layout named %q", name))
+	default:
+		c.refreshSlashCommands()
+		c.AddMessage("system", fmt.Sprintf("🪟 Deleted window layout %q", name))
+	}
+}
+
+func (c *Chat) listLayouts() {
+	names := c.windowLayouts.Names()
+	if len(names) == 0 {
+		c.AddMessage("system", "🪟 No saved window layouts; /layout save <name> saves the current one")
+		return
+	}
+	var b strings.Builder
+	b.WriteString("🪟 Saved window layouts\n")
+	for _, name := range names {
+		b.WriteString("  • " + name + "\n")
+	}
+	b.WriteString("Switch with /layout load <name> or the command palette (Ctrl+P)")
+	c.AddMessage("system", b.String())
+}
+
+// layoutSlashCommands lists a menu entry per saved layout.
+func (c *Chat) layoutSlashCommands() []chatcomponents.SlashCommand {
+	names := c.windowLayouts.Names()
+	commands := make([]chatcomponents.SlashCommand, 0, len(names))
+	for _, name := range names {
+		commands = append(commands, chatcomponents.SlashCommand{
+			Name:        loadLayoutCommand + name,
+			Description: "Switch to this saved window layout",
+			Usage:       "/" + loadLayoutCommand + name,
+		})
+	}
+	return commands
+}
+
+func (c *Chat) refreshSlashCommands() {
+	all := c.slashCommands()
+	c.slashMenu.SetCommands(all)
+	c.commandPalette.UpdateCommands(all)
+}
@@ -0,0 +1,183 @@
+package windows
+
+import (
+	tea "github.com/charmbracelet/bubbletea/v2"
+)
+
+const compareInstructions = "Compare mode – prompts go to every model, Shift+↑/↓/PgUp/PgDn scroll together, TAB to cycle, /promote keeps an answer, Ctrl+T to exit"
+
+// StartCompare shows only the windows of ids, side by side in that order, and
+// scrolls them together until StopCompare.
+func (m *Manager) StartCompare(ids []string) {
+	if m.mode != ModeCompare {
+		layout := m.Layout()
+		m.beforeCompare = &layout
+		m.prevMode = m.mode
+	}
+	m.mode = ModeCompare
+	m.keyMode = false
+	m.compareIDs = append([]string(nil), ids...)
+	for _, id := range ids {
+		delete(m.hidden, id)
+	}
+	m.dragging = nil
+	m.animations = make(map[string]*animation)
+	if len(ids) > 0 {
+		m.focusWindow(ids[0])
+	}
+	m.layoutCompare()
+}
+
+// StopCompare returns to the mode and window positions from before
+// StartCompare.
+func (m *Manager) StopCompare() {
+	if m.mode != ModeCompare {
+		return
+	}
+	m.compareIDs = nil
+	m.mode = ModePanel
+	m.SetMode(m.prevMode)
+	if m.beforeCompare != nil {
+		m.applyLayout(*m.beforeCompare, false)
+		m.beforeCompare = nil
+	}
+}
+
+// Comparing reports whether compare mode is active.
+func (m *Manager) Comparing() bool {
+	return m.mode == ModeCompare
+}
+
+// FocusedID returns the ID of the focused window.
+func (m *Manager) FocusedID() string {
+	return m.focusedID
+}
+
+func (m *Manager) removeFromCompare(id string) {
+	for idx, existing := range m.compareIDs {
+		if existing == id {
+			m.compareIDs = append(m.compareIDs[:idx], m.compareIDs[idx+1:]...)
+			return
+		}
+	}
+}
+
+// visibleOrder lists the windows to draw, back to front.
+func (m *Manager) visibleOrder() []string {
+	if m.mode != ModeCompare {
+		if len(m.hidden) == 0 {
+			return m.order
+		}
+		ids := make([]string, 0, len(m.order))
+		for _, id := range m.order {
+			if !m.hidden[id] {
+				ids = append(ids, id)
+			}
+		}
+		return ids
+	}
+	ids := make([]string, 0, len(m.compareIDs))
+	for _, id := range m.order {
+		for _, compared := range m.compareIDs {
+			if id == compared {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	return ids
+}
+
+// layoutCompare splits the area into equal columns below the instructions.
+// Columns may be narrower than free windows so that several models fit.
+func (m *Manager) layoutCompare() {
+	count := len(m.compareIDs)
+	if count == 0 || m.width <= 0 || m.height <= 0 {
+		return
+	}
+	gutter := 1
+	top := 1
+	width := maxInt(1, (m.width-(count+1)*gutter)/count)
+	height := maxInt(minWindowHeight, m.height-top)
+	for idx, id := range m.compareIDs {
+		win := m.windows[id]
+		if win == nil {
+			continue
+		}
+		win.SetBounds(gutter+idx*(width+gutter), top, width, height)
+	}
+}
+
+// handleCompareMessage handles input in compare mode. Windows cannot be
+// dragged, and scrolling any of them scrolls all of them.
+func (m *Manager) handleCompareMessage(msg tea.Msg) (tea.Cmd, bool) {
+	switch ev := msg.(type) {
+	case tea.MouseClickMsg:
+		mouse := ev.Mouse()
+		if mouse.Button != tea.MouseLeft {
+			return nil, false
+		}
+		id := m.windowAt(mouse.X, mouse.Y)
+		if id == "" {
+			return nil, true
+		}
+		m.focusWindow(id)
+		return func() tea.Msg {
+			return ActivateConversationMsg{ConversationID: id}
+		}, true
+
+	case tea.MouseWheelMsg:
+		mouse := ev.Mouse()
+		if m.windowAt(mouse.X, mouse.Y) == "" {
+			return nil, false
+		}
+		var cmds []tea.Cmd
+		for _, id := range m.compareIDs {
+			if win := m.windows[id]; win != nil {
+				cmds = append(cmds, win.HandleViewportMsg(ev))
+			}
+		}
+		return tea.Batch(cmds...), true
+
+	case tea.KeyPressMsg:
+		switch ev.String() {
+		case "tab":
+			m.cycleFocus(1)
+			return nil, true
+		case "shift+tab":
+			m.cycleFocus(-1)
+			return nil, true
+		case "shift+up", "shift+down", "shift+pgup", "shift+pgdown", "shift+home", "shift+end":
+			m.scrollCompared(ev.String())
+			return nil, true
+		}
+
+	case AnimationTickMsg:
+		return nil, true
+	}
+	return nil, false
+}
+
+func (m *Manager) scrollCompared(key string) {
+	for _, id := range m.compareIDs {
+		win := m.windows[id]
+		if win == nil || win.Viewport() == nil {
+			continue
+		}
+		vp := win.Viewport()
+		switch key {
+		case "shift+up":
+			vp.ScrollUp(1)
+		case "shift+down":
+			vp.ScrollDown(1)
+		case "shift+pgup":
+			vp.ScrollHalfPageUp()
+		case "shift+pgdown":
+			vp.ScrollHalfPageDown()
+		case "shift+home":
+			vp.GotoTop()
+		case "shift+end":
+			vp.GotoBottom()
+		}
+	}
+}
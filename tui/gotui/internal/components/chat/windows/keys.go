@@ -0,0 +1,347 @@
+package windows
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/keybindings"
+	"gotui/internal/styles"
+)
+
+// How far one key press moves or resizes a window. Cells are about twice as
+// tall as they are wide, so rows step half as far.
+const (
+	keyStepX = 4
+	keyStepY = 2
+)
+
+// SetKeyMode turns window keys on or off and reports whether they are on.
+// They only work in window mode.
+func (m *Manager) SetKeyMode(on bool) bool {
+	m.keyMode = on && m.mode == ModeWindow
+	return m.keyMode
+}
+
+// KeyMode reports whether keys move and resize windows instead of typing.
+func (m *Manager) KeyMode() bool {
+	return m.keyMode
+}
+
+// SetKeyMap sets the bindings window keys and their hints use.
+func (m *Manager) SetKeyMap(km keybindings.KeyMap) {
+	m.keyMap = km
+}
+
+// HandleKey applies a window key to the focused window.
+func (m *Manager) HandleKey(msg tea.KeyPressMsg) tea.Cmd {
+	if !m.keyMode {
+		return nil
+	}
+	km := &m.keyMap
+	switch {
+	case key.Matches(msg, km.WindowDone):
+		m.keyMode = false
+	case key.Matches(msg, km.WindowFocusNext):
+		m.cycleFocus(1)
+	case key.Matches(msg, km.WindowFocusPrev):
+		m.cycleFocus(-1)
+	case key.Matches(msg, km.WindowMoveLeft):
+		m.moveFocused(-keyStepX, 0)
+	case key.Matches(msg, km.WindowMoveRight):
+		m.moveFocused(keyStepX, 0)
+	case key.Matches(msg, km.WindowMoveUp):
+		m.moveFocused(0, -keyStepY)
+	case key.Matches(msg, km.WindowMoveDown):
+		m.moveFocused(0, keyStepY)
+	case key.Matches(msg, km.WindowResizeLeft):
+		m.resizeFocused(-keyStepX, 0)
+	case key.Matches(msg, km.WindowResizeRight):
+		m.resizeFocused(keyStepX, 0)
+	case key.Matches(msg, km.WindowResizeUp):
+		m.resizeFocused(0, -keyStepY)
+	case key.Matches(msg, km.WindowResizeDown):
+		m.resizeFocused(0, keyStepY)
+	case key.Matches(msg, km.WindowSnapLeft):
+		m.placeFocused(m.snapBounds(snapLeft))
+	case key.Matches(msg, km.WindowSnapRight):
+		m.placeFocused(m.snapBounds(snapRight))
+	case key.Matches(msg, km.WindowSnapTop):
+		m.placeFocused(m.snapBounds(snapTop))
+	case key.Matches(msg, km.WindowSnapBottom):
+		m.placeFocused(m.snapBounds(snapBottom))
+	case key.Matches(msg, km.WindowSnapTopLeft):
+		m.placeFocused(m.snapBounds(snapTopLeft))
+	case key.Matches(msg, km.WindowSnapTopRight):
+		m.placeFocused(m.snapBounds(snapTopRight))
+	case key.Matches(msg, km.WindowSnapBottomLeft):
+		m.placeFocused(m.snapBounds(snapBottomLeft))
+	case key.Matches(msg, km.WindowSnapBottomRight):
+		m.placeFocused(m.snapBounds(snapBottomRight))
+	case key.Matches(msg, km.WindowMaximise):
+		m.toggleMaximise()
+	case key.Matches(msg, km.WindowSwapNext):
+		return m.swapFocused(1)
+	case key.Matches(msg, km.WindowSwapPrev):
+		return m.swapFocused(-1)
+	case key.Matches(msg, km.WindowClose):
+		return m.closeFocused()
+	case key.Matches(msg, km.WindowReopen):
+		return m.reopenWindows()
+	}
+	return nil
+}
+
+func (m *Manager) focusedWindow() *ConversationWindow {
+	if m.hidden[m.focusedID] {
+		return nil
+	}
+	return m.windows[m.focusedID]
+}
+
+func (m *Manager) moveFocused(dx, dy int) {
+	if win := m.focusedWindow(); win != nil {
+		m.placeFocused(rect{x: win.X + dx, y: win.Y + dy, width: win.Width, height: win.Height})
+	}
+}
+
+func (m *Manager) resizeFocused(dw, dh int) {
+	if win := m.focusedWindow(); win != nil {
+		m.placeFocused(rect{x: win.X, y: win.Y, width: win.Width + dw, height: win.Height + dh})
+	}
+}
+
+// placeFocused moves the focused window to bounds. Placing a window by hand
+// ends auto-tiling, as dragging does.
+func (m *Manager) placeFocused(bounds rect) {
+	win := m.focusedWindow()
+	if win == nil {
+		return
+	}
+	if m.autoTile {
+		m.autoTile = false
+		m.animations = make(map[string]*animation)
+	}
+	delete(m.maximised, win.ID)
+	win.SetBounds(bounds.x, bounds.y, bounds.width, bounds.height)
+	win.ConstrainTo(m.width, m.height)
+	m.layoutChanged = true
+}
+
+// snapArea is a part of the window area a window can be snapped to.
+type snapArea int
+
+const (
+	snapLeft snapArea = iota
+	snapRight
+	snapTop
+	snapBottom
+	snapTopLeft
+	snapTopRight
+	snapBottomLeft
+	snapBottomRight
+)
+
+// snapBounds returns the bounds of a part of the window area.
+func (m *Manager) snapBounds(snap snapArea) rect {
+	area := m.windowArea()
+	halfW, halfH := area.width/2, area.height/2
+	left := rect{x: area.x, y: area.y, width: halfW, height: area.height}
+	right := rect{x: area.x + halfW, y: area.y, width: area.width - halfW, height: area.height}
+	switch snap {
+	case snapLeft:
+		return left
+	case snapRight:
+		return right
+	case snapTop:
+		return rect{x: area.x, y: area.y, width: area.width, height: halfH}
+	case snapBottom:
+		return rect{x: area.x, y: area.y + halfH, width: area.width, height: area.height - halfH}
+	case snapTopLeft:
+		return rect{x: left.x, y: left.y, width: left.width, height: halfH}
+	case snapTopRight:
+		return rect{x: right.x, y: right.y, width: right.width, height: halfH}
+	case snapBottomLeft:
+		return rect{x: left.x, y: left.y + halfH, width: left.width, height: area.height - halfH}
+	case snapBottomRight:
+		return rect{x: right.x, y: right.y + halfH, width: right.width, height: area.height - halfH}
+	}
+	return area
+}
+
+// windowArea is the space below the instructions line.
+func (m *Manager) windowArea() rect {
+	top := 1
+	return rect{x: 0, y: top, width: m.width, height: maxInt(minWindowHeight, m.height-top)}
+}
+
+func (m *Manager) toggleMaximise() {
+	win := m.focusedWindow()
+	if win == nil {
+		return
+	}
+	if previous, ok := m.maximised[win.ID]; ok {
+		m.placeFocused(previous)
+		return
+	}
+	previous := rect{x: win.X, y: win.Y, width: win.Width, height: win.Height}
+	m.placeFocused(m.windowArea())
+	m.maximised[win.ID] = previous
+}
+
+// swapFocused swaps the focused window with the one Tab (delta 1) or
+// Shift+Tab (delta -1) would focus. The focused window keeps the focus.
+func (m *Manager) swapFocused(delta int) tea.Cmd {
+	order := m.visibleOrder()
+	current := -1
+	for idx, id := range order {
+		if id == m.focusedID {
+			current = idx
+			break
+		}
+	}
+	if len(order) < 2 || current < 0 {
+		return nil
+	}
+	n := len(order)
+	otherID := order[((current+delta)%n+n)%n]
+	m.layoutChanged = true
+
+	if m.autoTile {
+		a, b := m.orderIndex(m.focusedID), m.orderIndex(otherID)
+		m.order[a], m.order[b] = m.order[b], m.order[a]
+		return m.layoutAutoTile(true)
+	}
+	focused, other := m.windows[m.focusedID], m.windows[otherID]
+	focusedBounds := rect{x: focused.X, y: focused.Y, width: focused.Width, height: focused.Height}
+	focused.SetBounds(other.X, other.Y, other.Width, other.Height)
+	other.SetBounds(focusedBounds.x, focusedBounds.y, focusedBounds.width, focusedBounds.height)
+	focused.ConstrainTo(m.width, m.height)
+	other.ConstrainTo(m.width, m.height)
+	delete(m.maximised, focused.ID)
+	delete(m.maximised, other.ID)
+	return nil
+}
+
+func (m *Manager) orderIndex(id string) int {
+	for idx, existing := range m.order {
+		if existing == id {
+			return idx
+		}
+	}
+	return -1
+}
+
+// closeFocused hides the focused window until its conversation is opened
+// again and activates the window below it. The last window stays open.
+func (m *Manager) closeFocused() tea.Cmd {
+	if m.focusedWindow() == nil || len(m.visibleOrder()) < 2 {
+		return nil
+	}
+	m.hidden[m.focusedID] = true
+	m.layoutChanged = true
+	order := m.visibleOrder()
+	next := order[len(order)-1]
+	m.focusWindow(next)
+
+	activate := func() tea.Msg {
+		return ActivateConversationMsg{ConversationID: next}
+	}
+	if m.autoTile {
+		return tea.Batch(activate, m.layoutAutoTile(true))
+	}
+	return activate
+}
+
+func (m *Manager) reopenWindows() tea.Cmd {
+	if len(m.hidden) == 0 {
+		return nil
+	}
+	m.hidden = make(map[string]bool)
+	m.layoutChanged = true
+	if m.autoTile {
+		return m.layoutAutoTile(true)
+	}
+	return nil
+}
+
+// keyHints lists the window keys as bound in the key map, grouped the way
+// they are shown while window keys are on. Groups with no enabled binding
+// are left out.
+func (m *Manager) keyHints() [][2]string {
+	km := m.keyMap
+	groups := []struct {
+		bindings []key.Binding
+		desc     string
+	}{
+		{[]key.Binding{km.WindowMoveLeft, km.WindowMoveDown, km.WindowMoveUp, km.WindowMoveRight}, "move"},
+		{[]key.Binding{km.WindowResizeLeft, km.WindowResizeDown, km.WindowResizeUp, km.WindowResizeRight}, "resize"},
+		{[]key.Binding{km.WindowSnapLeft, km.WindowSnapRight, km.WindowSnapTop, km.WindowSnapBottom}, "snap to left/right/top/bottom half"},
+		{[]key.Binding{km.WindowSnapTopLeft, km.WindowSnapTopRight, km.WindowSnapBottomLeft, km.WindowSnapBottomRight}, "snap to a quarter"},
+		{[]key.Binding{km.WindowMaximise}, "maximise / restore"},
+		{[]key.Binding{km.WindowSwapNext, km.WindowSwapPrev}, "swap with next / previous window"},
+		{[]key.Binding{km.WindowFocusNext, km.WindowFocusPrev}, "focus next / previous window"},
+		{[]key.Binding{km.WindowClose}, "close window"},
+		{[]key.Binding{km.WindowReopen}, "reopen closed windows"},
+		{[]key.Binding{km.WindowDone}, "done"},
+	}
+	var hints [][2]string
+	for _, group := range groups {
+		var keys []string
+		for _, binding := range group.bindings {
+			if binding.Enabled() && binding.Help().Key != "" {
+				keys = append(keys, binding.Help().Key)
+			}
+		}
+		if len(keys) > 0 {
+			hints = append(hints, [2]string{strings.Join(keys, " "), group.desc})
+		}
+	}
+	return hints
+}
+
+func (m *Manager) keyHintsView() string {
+	theme := styles.CurrentTheme()
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Primary.Hex())).
+		Bold(true)
+	descStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color(theme.Foreground.Hex()))
+
+	hints := m.keyHints()
+	keyWidth := 0
+	for _, hint := range hints {
+		keyWidth = maxInt(keyWidth, lipgloss.Width(hint[0]))
+	}
+	lines := []string{keyStyle.Render("Window keys")}
+	for _, hint := range hints {
+		lines = append(lines, keyStyle.Width(keyWidth+2).Render(hint[0])+descStyle.Render(hint[1]))
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(theme.Primary.Hex())).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// firstKey returns the first key of an enabled binding, or "".
+func firstKey(b key.Binding) string {
+	if !b.Enabled() || len(b.Keys()) == 0 {
+		return ""
+	}
+	return b.Keys()[0]
+}
+
+// displayKey writes a key the way the instructions do, e.g. "alt+w" as
+// "Alt+W".
+func displayKey(key string) string {
+	parts := strings.Split(key, "+")
+	for idx, part := range parts {
+		if part != "" {
+			parts[idx] = strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return strings.Join(parts, "+")
+}
@@ -0,0 +1,106 @@
+package windows
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+
+	"gotui/internal/windowlayout"
+)
+
+// Layout snapshots the window positions for saving. In compare mode it is
+// the layout compare mode returns to.
+func (m *Manager) Layout() windowlayout.Layout {
+	if m.mode == ModeCompare && m.beforeCompare != nil {
+		return *m.beforeCompare
+	}
+	layout := windowlayout.Layout{Focused: slices.Index(m.slots, m.focusedID), AutoTile: m.autoTile}
+	for slot, id := range m.slots {
+		if m.hidden[id] {
+			layout.Hidden = append(layout.Hidden, slot)
+		}
+	}
+	for _, id := range m.order {
+		win := m.windows[id]
+		slot := slices.Index(m.slots, id)
+		if win == nil || slot < 0 {
+			continue
+		}
+		bounds := rect{x: win.X, y: win.Y, width: win.Width, height: win.Height}
+		if anim, ok := m.animations[id]; ok {
+			bounds = anim.target
+		}
+		layout.Windows = append(layout.Windows, savedWindow(slot, bounds))
+	}
+	return layout
+}
+
+// ApplyLayout moves the windows to the bounds saved in layout and restores
+// its stacking order, focus, closed windows and auto-tiling. Windows are
+// matched by the order they were opened in; slots no window has yet get
+// their bounds when a window opens in them this session. Windows the layout
+// does not mention keep theirs and stay on top.
+func (m *Manager) ApplyLayout(layout windowlayout.Layout) tea.Cmd {
+	if m.mode == ModeCompare {
+		m.beforeCompare = &layout
+		return nil
+	}
+	return m.applyLayout(layout, m.mode == ModeWindow)
+}
+
+func (m *Manager) applyLayout(layout windowlayout.Layout, animated bool) tea.Cmd {
+	m.pending = make(map[int]rect)
+	placed := make(map[int]bool, len(layout.Windows))
+	order := make([]string, 0, len(m.order))
+	for _, saved := range layout.Windows {
+		bounds := rect{x: saved.X, y: saved.Y, width: saved.Width, height: saved.Height}
+		if bounds.width <= 0 || bounds.height <= 0 || saved.Slot < 0 || placed[saved.Slot] {
+			continue
+		}
+		placed[saved.Slot] = true
+		if saved.Slot >= len(m.slots) {
+			m.pending[saved.Slot] = bounds
+			continue
+		}
+		id := m.slots[saved.Slot]
+		m.windows[id].SetBounds(bounds.x, bounds.y, bounds.width, bounds.height)
+		order = append(order, id)
+	}
+	for _, id := range m.order {
+		if !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
+	m.order = order
+	m.dragging = nil
+	m.animations = make(map[string]*animation)
+	m.maximised = make(map[string]rect)
+	m.hidden = make(map[string]bool)
+	for _, slot := range layout.Hidden {
+		if slot >= 0 && slot < len(m.slots) && m.slots[slot] != m.activeID {
+			m.hidden[m.slots[slot]] = true
+		}
+	}
+	if layout.Focused >= 0 && layout.Focused < len(m.slots) {
+		m.focusWindow(m.slots[layout.Focused])
+	}
+
+	m.autoTile = layout.AutoTile
+	if m.autoTile && m.mode != ModePanel {
+		return m.layoutAutoTile(animated)
+	}
+	m.ensureWindowBounds()
+	return nil
+}
+
+// LayoutChanged reports whether the user moved windows or toggled
+// auto-tiling since the last call.
+func (m *Manager) LayoutChanged() bool {
+	changed := m.layoutChanged
+	m.layoutChanged = false
+	return changed
+}
+
+func savedWindow(slot int, bounds rect) windowlayout.Window {
+	return windowlayout.Window{Slot: slot, X: bounds.x, Y: bounds.y, Width: bounds.width, Height: bounds.height}
+}
@@ -3,14 +3,17 @@ package windows
 import (
 	"fmt"
 	"math"
+	"slices"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 
 	"gotui/internal/components/chattemplates"
+	"gotui/internal/keybindings"
 	"gotui/internal/stores"
 	"gotui/internal/styles"
+	"gotui/internal/windowlayout"
 )
 
 type Mode int
@@ -18,6 +21,8 @@ type Mode int
 const (
 	ModePanel Mode = iota
 	ModeWindow
+	// ModeCompare shows only the compared windows, side by side.
+	ModeCompare
 )
 
 type ActivateConversationMsg struct {
@@ -39,12 +44,31 @@ type Manager struct {
 	autoTile        bool
 	animations      map[string]*animation
 	renderContent   ContentRenderer
+	// compareIDs are the windows shown in compare mode, left to right;
+	// prevMode is the mode to return to afterwards.
+	compareIDs    []string
+	prevMode      Mode
+	beforeCompare *windowlayout.Layout
+	// slots are the window IDs in the order they were opened; saved layouts
+	// refer to windows by index in it. pending holds restored bounds of slots
+	// no window has taken yet this session.
+	slots         []string
+	pending       map[int]rect
+	layoutChanged bool
+	// keyMode sends keys to HandleKey, which reads them from keyMap; hidden
+	// windows were closed with it and maximised ones remember their bounds
+	// from before.
+	keyMode   bool
+	keyMap    keybindings.KeyMap
+	hidden    map[string]bool
+	maximised map[string]rect
 }
 
 type dragState struct {
 	windowID string
 	offsetX  int
 	offsetY  int
+	moved    bool
 }
 
 func NewManager(templateManager *chattemplates.TemplateManager) *Manager {
@@ -53,6 +77,9 @@ func NewManager(templateManager *chattemplates.TemplateManager) *Manager {
 		templateManager: templateManager,
 		windows:         make(map[string]*ConversationWindow),
 		animations:      make(map[string]*animation),
+		keyMap:          keybindings.DefaultKeyMap(),
+		hidden:          make(map[string]bool),
+		maximised:       make(map[string]rect),
 	}
 }
 
@@ -67,6 +94,7 @@ func (m *Manager) SetMode(mode Mode) {
 	m.mode = mode
 	if mode != ModeWindow {
 		m.dragging = nil
+		m.keyMode = false
 		return
 	}
 	if m.focusedID == "" && m.activeID != "" {
@@ -76,6 +104,10 @@ func (m *Manager) SetMode(mode Mode) {
 }
 
 func (m *Manager) ToggleMode() Mode {
+	if m.mode == ModeCompare {
+		m.StopCompare()
+		return m.mode
+	}
 	if m.mode == ModeWindow {
 		m.SetMode(ModePanel)
 	} else {
@@ -88,8 +120,20 @@ func (m *Manager) SetContentRenderer(renderer ContentRenderer) {
 	m.renderContent = renderer
 }
 
+// RefreshTheme re-renders the messages of every window, closed ones
+// included, after the theme changed.
+func (m *Manager) RefreshTheme() {
+	for _, win := range m.windows {
+		if vp := win.Viewport(); vp != nil {
+			vp.RefreshTheme()
+		}
+	}
+}
+
+// IsWindowMode reports whether conversations are drawn as windows, which
+// includes compare mode.
 func (m *Manager) IsWindowMode() bool {
-	return m.mode == ModeWindow
+	return m.mode != ModePanel
 }
 
 func (m *Manager) SetSize(width, height int) {
@@ -122,7 +166,13 @@ func (m *Manager) SyncConversations(conversations []*stores.Conversation, active
 			win = NewConversationWindow(conv, m.templateManager)
 			m.windows[conv.ID] = win
 			m.order = append(m.order, conv.ID)
-			win.SetBounds(m.defaultBounds(len(m.order) - 1))
+			m.slots = append(m.slots, conv.ID)
+			if bounds, ok := m.pending[len(m.slots)-1]; ok {
+				win.SetBounds(bounds.x, bounds.y, bounds.width, bounds.height)
+				delete(m.pending, len(m.slots)-1)
+			} else {
+				win.SetBounds(m.defaultBounds(len(m.order) - 1))
+			}
 		} else {
 			win.SyncConversation(conv)
 		}
@@ -133,6 +183,10 @@ func (m *Manager) SyncConversations(conversations []*stores.Conversation, active
 		if _, ok := seen[id]; !ok {
 			delete(m.windows, id)
 			m.removeFromOrder(id)
+			m.slots = slices.DeleteFunc(m.slots, func(slot string) bool { return slot == id })
+			m.removeFromCompare(id)
+			delete(m.hidden, id)
+			delete(m.maximised, id)
 			if m.focusedID == id {
 				m.focusedID = ""
 			}
@@ -140,10 +194,16 @@ func (m *Manager) SyncConversations(conversations []*stores.Conversation, active
 	}
 
 	m.activeID = activeID
+	// Opening a closed window's conversation brings the window back.
+	delete(m.hidden, activeID)
 	if m.focusedID == "" && activeID != "" {
 		m.focusWindow(activeID)
 	}
 
+	if m.mode == ModeCompare {
+		m.layoutCompare()
+		return nil
+	}
 	if m.autoTile {
 		return m.layoutAutoTile(true)
 	}
@@ -153,9 +213,12 @@ func (m *Manager) SyncConversations(conversations []*stores.Conversation, active
 }
 
 func (m *Manager) HandleMessage(msg tea.Msg) (tea.Cmd, bool) {
-	if m.mode != ModeWindow {
+	if m.mode == ModePanel {
 		return nil, false
 	}
+	if m.mode == ModeCompare {
+		return m.handleCompareMessage(msg)
+	}
 
 	switch ev := msg.(type) {
 	case tea.MouseClickMsg:
@@ -224,6 +287,7 @@ func (m *Manager) HandleMessage(msg tea.Msg) (tea.Cmd, bool) {
 		newY := mouse.Y - m.dragging.offsetY
 		win.SetPosition(newX, newY)
 		win.ConstrainTo(m.width, m.height)
+		m.dragging.moved = true
 		return nil, true
 
 	case tea.MouseReleaseMsg:
@@ -232,6 +296,9 @@ func (m *Manager) HandleMessage(msg tea.Msg) (tea.Cmd, bool) {
 		}
 		mouse := ev.Mouse()
 		if mouse.Button == tea.MouseLeft {
+			if m.dragging != nil && m.dragging.moved {
+				m.layoutChanged = true
+			}
 			m.dragging = nil
 			return nil, true
 		}
@@ -292,7 +359,7 @@ func (m *Manager) View() string {
 
 	m.applyAnimations(time.Now())
 
-	for idx, id := range m.order {
+	for idx, id := range m.visibleOrder() {
 		win := m.windows[id]
 		if win == nil {
 			continue
@@ -325,7 +392,20 @@ func (m *Manager) View() string {
 	if m.autoTile {
 		autoStatus = "ON"
 	}
-	instructionText := fmt.Sprintf("Window mode – drag windows with mouse, TAB to cycle, Ctrl+T to exit, Ctrl+U auto-tiling %s", autoStatus)
+	move := "drag windows with mouse"
+	if label := firstKey(m.keyMap.WindowKeys); label != "" {
+		move += " or " + displayKey(label)
+	}
+	instructionText := fmt.Sprintf("Window mode – %s, TAB to cycle, Ctrl+T to exit, Ctrl+U auto-tiling %s", move, autoStatus)
+	switch {
+	case m.mode == ModeCompare:
+		instructionText = compareInstructions
+	case m.keyMode:
+		instructionText = "Window keys – move, resize and snap the focused window"
+		if done := firstKey(m.keyMap.WindowDone); done != "" {
+			instructionText += ", " + displayKey(done) + " when done"
+		}
+	}
 	instructions := lipgloss.NewStyle().
 		Background(lipgloss.Color(theme.SurfaceHigh.Hex())).
 		Foreground(lipgloss.Color(theme.Muted.Hex())).
@@ -338,12 +418,22 @@ func (m *Manager) View() string {
 		Z(1000).
 		ID("window-mode-hint"))
 
+	if m.keyMode {
+		hints := m.keyHintsView()
+		canvas.AddLayers(lipgloss.NewLayer(hints).
+			X(maxInt(0, m.width-lipgloss.Width(hints)-1)).
+			Y(maxInt(1, m.height-lipgloss.Height(hints)-1)).
+			Z(1001).
+			ID("window-keys-hint"))
+	}
+
 	return canvas.Render()
 }
 
 func (m *Manager) windowAt(x, y int) string {
-	for i := len(m.order) - 1; i >= 0; i-- {
-		id := m.order[i]
+	order := m.visibleOrder()
+	for i := len(order) - 1; i >= 0; i-- {
+		id := order[i]
 		win := m.windows[id]
 		if win == nil {
 			continue
@@ -372,13 +462,18 @@ func (m *Manager) Focus(id string) {
 }
 
 func (m *Manager) cycleFocus(delta int) {
-	if len(m.order) == 0 {
+	order := m.visibleOrder()
+	if m.mode == ModeCompare {
+		// Compared windows do not overlap, so cycle left to right.
+		order = m.compareIDs
+	}
+	if len(order) == 0 {
 		return
 	}
 
 	currentIndex := 0
 	if m.focusedID != "" {
-		for i, id := range m.order {
+		for i, id := range order {
 			if id == m.focusedID {
 				currentIndex = i
 				break
@@ -387,9 +482,9 @@ func (m *Manager) cycleFocus(delta int) {
 	}
 
 	next := currentIndex + delta
-	n := len(m.order)
+	n := len(order)
 	next = ((next % n) + n) % n
-	target := m.order[next]
+	target := order[next]
 	m.focusWindow(target)
 }
 
@@ -403,7 +498,13 @@ func (m *Manager) removeFromOrder(id string) {
 }
 
 func (m *Manager) ensureWindowBounds() {
-	if len(m.windows) == 0 {
+	// Panel mode does not show the windows, so its size must not squeeze
+	// them.
+	if len(m.windows) == 0 || m.mode == ModePanel {
+		return
+	}
+	if m.mode == ModeCompare {
+		m.layoutCompare()
 		return
 	}
 	if m.autoTile {
@@ -473,15 +574,16 @@ func (m *Manager) defaultBounds(index int) (int, int, int, int) {
 }
 
 func (m *Manager) layoutAutoTile(animated bool) tea.Cmd {
-	if len(m.order) == 0 || m.width <= 0 || m.height <= 0 {
+	order := m.visibleOrder()
+	if len(order) == 0 || m.width <= 0 || m.height <= 0 {
 		return nil
 	}
 
-	cols := int(math.Ceil(math.Sqrt(float64(len(m.order)))))
+	cols := int(math.Ceil(math.Sqrt(float64(len(order)))))
 	if cols < 1 {
 		cols = 1
 	}
-	rows := int(math.Ceil(float64(len(m.order)) / float64(cols)))
+	rows := int(math.Ceil(float64(len(order)) / float64(cols)))
 	if rows < 1 {
 		rows = 1
 	}
@@ -495,7 +597,7 @@ func (m *Manager) layoutAutoTile(animated bool) tea.Cmd {
 	now := time.Now()
 	started := false
 
-	for idx, id := range m.order {
+	for idx, id := range order {
 		win := m.windows[id]
 		if win == nil {
 			continue
@@ -585,6 +687,7 @@ func (m *Manager) animationTickCmd() tea.Cmd {
 
 func (m *Manager) ToggleAutoTile() (bool, tea.Cmd) {
 	m.autoTile = !m.autoTile
+	m.layoutChanged = true
 	if !m.autoTile {
 		m.animations = make(map[string]*animation)
 		return false, nil
@@ -1,6 +1,7 @@
 package chatcomponents
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/v2/textarea"
@@ -14,6 +15,21 @@ type ChatInput struct {
 	width    int
 	height   int
 	focused  bool
+
+	// history holds earlier prompts, oldest first. historyIndex is the
+	// recalled entry, or len(history) while editing a new prompt whose text
+	// is kept in pending meanwhile.
+	history      []string
+	historyIndex int
+	pending      string
+
+	// Reverse incremental search state. searchIndex is the matched entry or
+	// -1; searchOriginal is restored when the search is cancelled.
+	searching      bool
+	searchQuery    string
+	searchIndex    int
+	searchFailed   bool
+	searchOriginal string
 }
 
 // NewChatInput creates a new ChatInput component with default styling and behavior.
@@ -112,6 +128,142 @@ func (ci *ChatInput) SetValueAndCursor(value string, cursor int) {
 // Clear removes all content from the textarea.
 func (ci *ChatInput) Clear() {
 	ci.textarea.Reset()
+	ci.historyIndex = len(ci.history)
+	ci.pending = ""
+}
+
+// SetHistory replaces the prompts recalled with up/down and history search,
+// oldest first.
+func (ci *ChatInput) SetHistory(entries []string) {
+	ci.history = append([]string(nil), entries...)
+	ci.historyIndex = len(ci.history)
+	ci.pending = ""
+}
+
+// Remember adds a sent prompt to the history.
+func (ci *ChatInput) Remember(prompt string) {
+	if strings.TrimSpace(prompt) == "" {
+		return
+	}
+	if n := len(ci.history); n == 0 || ci.history[n-1] != prompt {
+		ci.history = append(ci.history, prompt)
+	}
+	ci.historyIndex = len(ci.history)
+	ci.pending = ""
+}
+
+// HistoryPrev recalls the previous prompt when the cursor is on the first
+// row, so up still moves through a multi-line prompt. It reports whether
+// the key was used.
+func (ci *ChatInput) HistoryPrev() bool {
+	if ci.textarea.Line() > 0 || ci.textarea.LineInfo().RowOffset > 0 || len(ci.history) == 0 {
+		return false
+	}
+	if ci.historyIndex >= len(ci.history) {
+		ci.historyIndex = len(ci.history)
+		ci.pending = ci.textarea.Value()
+	}
+	if ci.historyIndex == 0 {
+		return true
+	}
+	ci.historyIndex--
+	ci.textarea.SetValue(ci.history[ci.historyIndex])
+	ci.cursorToStart()
+	return true
+}
+
+// HistoryNext moves towards newer prompts, ending at the prompt that was
+// being written, when the cursor is on the last row.
+func (ci *ChatInput) HistoryNext() bool {
+	if ci.historyIndex >= len(ci.history) {
+		return false
+	}
+	info := ci.textarea.LineInfo()
+	if ci.textarea.Line() < ci.textarea.LineCount()-1 || info.RowOffset < info.Height-1 {
+		return false
+	}
+	ci.historyIndex++
+	if ci.historyIndex == len(ci.history) {
+		ci.textarea.SetValue(ci.pending)
+		ci.pending = ""
+		return true
+	}
+	ci.textarea.SetValue(ci.history[ci.historyIndex])
+	return true
+}
+
+func (ci *ChatInput) cursorToStart() {
+	for ci.textarea.Line() > 0 || ci.textarea.LineInfo().RowOffset > 0 {
+		ci.textarea.CursorUp()
+	}
+	ci.textarea.CursorStart()
+}
+
+// StartSearch begins a reverse incremental search through the history.
+func (ci *ChatInput) StartSearch() {
+	if len(ci.history) == 0 {
+		return
+	}
+	ci.searching = true
+	ci.searchQuery = ""
+	ci.searchIndex = -1
+	ci.searchFailed = false
+	ci.searchOriginal = ci.textarea.Value()
+}
+
+// Searching reports whether a reverse search is in progress.
+func (ci *ChatInput) Searching() bool {
+	return ci.searching
+}
+
+// HandleSearchKey handles a key during a reverse search: typing narrows
+// the match, the search key again finds an older match, enter keeps the
+// match for editing and esc restores the previous input.
+func (ci *ChatInput) HandleSearchKey(msg tea.KeyPressMsg, again bool) {
+	switch {
+	case again:
+		from := ci.searchIndex - 1
+		if ci.searchIndex < 0 {
+			from = len(ci.history) - 1
+		}
+		ci.search(from)
+	case msg.String() == "esc" || msg.String() == "ctrl+g":
+		ci.searching = false
+		ci.textarea.SetValue(ci.searchOriginal)
+	case msg.String() == "backspace":
+		if runes := []rune(ci.searchQuery); len(runes) > 0 {
+			ci.searchQuery = string(runes[:len(runes)-1])
+		}
+		ci.search(len(ci.history) - 1)
+	case msg.Text != "":
+		ci.searchQuery += msg.Text
+		from := ci.searchIndex
+		if from < 0 {
+			from = len(ci.history) - 1
+		}
+		ci.search(from)
+	default:
+		// Enter and any other key accept the match as the new input.
+		ci.searching = false
+		if ci.searchIndex >= 0 {
+			ci.historyIndex = ci.searchIndex
+			ci.pending = ci.searchOriginal
+		}
+	}
+}
+
+// search finds the newest entry at or before from containing the query.
+func (ci *ChatInput) search(from int) {
+	query := strings.ToLower(ci.searchQuery)
+	for i := min(from, len(ci.history)-1); i >= 0; i-- {
+		if strings.Contains(strings.ToLower(ci.history[i]), query) {
+			ci.searchIndex = i
+			ci.searchFailed = false
+			ci.textarea.SetValue(ci.history[i])
+			return
+		}
+	}
+	ci.searchFailed = true
 }
 
 // InsertRune inserts a rune at the current cursor position.
@@ -134,6 +286,17 @@ func (ci *ChatInput) View() string {
 	if ci.width <= 0 || ci.height <= 0 {
 		return ""
 	}
+	if ci.searching && ci.height > 1 {
+		label := "(reverse-i-search)"
+		if ci.searchFailed {
+			label = "(failed reverse-i-search)"
+		}
+		ci.textarea.SetHeight(ci.height - 1)
+		view := ci.textarea.View()
+		ci.textarea.SetHeight(ci.height)
+		prompt := lipgloss.NewStyle().Faint(true).MaxWidth(ci.width).Render(fmt.Sprintf("%s`%s': ", label, ci.searchQuery))
+		return lipgloss.JoinVertical(lipgloss.Left, view, prompt)
+	}
 	return ci.textarea.View()
 }
 
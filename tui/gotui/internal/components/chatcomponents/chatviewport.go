@@ -136,6 +136,12 @@ func (cv *ChatViewport) SetMessages(messages []chattemplates.MessageTemplateData
 	cv.updateContent()
 }
 
+// RefreshTheme re-applies the current theme and re-renders the messages.
+func (cv *ChatViewport) RefreshTheme() {
+	cv.configureViewport()
+	cv.updateContent()
+}
+
 // Messages returns a copy of the currently rendered messages.
 func (cv *ChatViewport) Messages() []chattemplates.MessageTemplateData {
 	if len(cv.messages) == 0 {
@@ -182,6 +188,21 @@ func (cv *ChatViewport) GotoBottom() {
 	cv.viewport.GotoBottom()
 }
 
+// AtBottom reports whether the last line is visible
+func (cv *ChatViewport) AtBottom() bool {
+	return cv.viewport.AtBottom()
+}
+
+// ScrollOffset returns the index of the first visible line
+func (cv *ChatViewport) ScrollOffset() int {
+	return cv.viewport.YOffset
+}
+
+// SetScrollOffset scrolls so that line n is the first visible one
+func (cv *ChatViewport) SetScrollOffset(n int) {
+	cv.viewport.SetYOffset(n)
+}
+
 // Update handles messages for the viewport component
 func (cv *ChatViewport) Update(msg tea.Msg) (*ChatViewport, tea.Cmd) {
 	var cmd tea.Cmd
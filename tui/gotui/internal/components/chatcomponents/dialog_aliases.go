@@ -10,16 +10,27 @@ type (
 	ModelOption               = stores.ModelOption
 	AgentOption               = stores.AgentOption
 	ModelPicker               = dialogs.ModelPicker
-	AgentPicker               = dialogs.AgentPicker
+	AgentBrowser              = dialogs.AgentBrowser
 	CommandPaletteItem        = dialogs.CommandPaletteItem
 	CommandPalette            = dialogs.CommandPalette
 	ApplicationSettingsDialog = dialogs.ApplicationSettingsDialog
 	ApplicationSettingOption  = dialogs.ApplicationSettingOption
+	ProfilePicker             = dialogs.ProfilePicker
+	ServerProfileOption       = dialogs.ServerProfileOption
+	ChangesDialog             = dialogs.ChangesDialog
+	ChangeOption              = dialogs.ChangeOption
+	ContextDialog             = dialogs.ContextDialog
+	ArgumentDialog            = dialogs.ArgumentDialog
+	ArgumentField             = dialogs.ArgumentField
 )
 
 var (
 	NewModelPicker               = dialogs.NewModelPicker
-	NewAgentPicker               = dialogs.NewAgentPicker
+	NewAgentBrowser              = dialogs.NewAgentBrowser
 	NewCommandPalette            = dialogs.NewCommandPalette
 	NewApplicationSettingsDialog = dialogs.NewApplicationSettingsDialog
+	NewProfilePicker             = dialogs.NewProfilePicker
+	NewChangesDialog             = dialogs.NewChangesDialog
+	NewContextDialog             = dialogs.NewContextDialog
+	NewArgumentDialog            = dialogs.NewArgumentDialog
 )
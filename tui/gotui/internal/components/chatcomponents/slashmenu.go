@@ -203,6 +203,13 @@ func (m *SlashMenu) renderItem(cmd SlashCommand, selected bool, width int) strin
 		lipgloss.NewStyle().Render("  "),
 		descStyle.Render(cmd.Description),
 	)
+	if cmd.Shortcut != "" {
+		headline = lipgloss.JoinHorizontal(lipgloss.Left,
+			headline,
+			lipgloss.NewStyle().Render("  "),
+			lipgloss.NewStyle().Foreground(theme.Primary).Render(cmd.Shortcut),
+		)
+	}
 	usageLine := ""
 	if cmd.Usage != "" {
 		usageLine = lipgloss.NewStyle().
@@ -257,6 +264,14 @@ func innerWidth(width int) int {
 	return inner
 }
 
+// SetCommands replaces the registered slash commands.
+func (m *SlashMenu) SetCommands(commands []SlashCommand) {
+	m.commands = commands
+	if m.selected >= len(m.filtered()) {
+		m.selected = 0
+	}
+}
+
 // Commands returns a copy of the registered slash commands
 func (m *SlashMenu) Commands() []SlashCommand {
 	cmds := make([]SlashCommand, len(m.commands))
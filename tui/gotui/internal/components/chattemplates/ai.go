@@ -36,7 +36,7 @@ func (at *AITemplate) Render(data MessageTemplateData, theme styles.Theme) Rende
 	// Process content - try to render as markdown for AI responses
 	content := data.Content
 	if !data.Raw && markdown.IsMarkdown(content) {
-		if renderer, err := markdown.New(data.Width - 4); err == nil {
+		if renderer, err := markdown.ForWidth(data.Width-4, theme); err == nil {
 			if rendered, err := renderer.Render(content); err == nil {
 				content = rendered
 			}
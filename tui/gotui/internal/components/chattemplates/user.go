@@ -1,11 +1,20 @@
 package chattemplates
 
 import (
+	"path/filepath"
+
+	"gotui/internal/imagepreview"
 	"gotui/internal/styles"
 
 	"github.com/charmbracelet/lipgloss/v2"
 )
 
+// Image previews are at most this many cells.
+const (
+	imagePreviewCols = 60
+	imagePreviewRows = 12
+)
+
 // UserTemplate handles rendering of user messages
 type UserTemplate struct {
 	BaseTemplate
@@ -39,9 +48,49 @@ func (ut *UserTemplate) Render(data MessageTemplateData, theme styles.Theme) Ren
 	contentLines := ut.RenderContent(data.Content, style, data.Width, theme)
 	lines = append(lines, contentLines...)
 
+	// Preview attached images
+	for _, path := range stringsFromAny(data.Metadata["images"]) {
+		lines = append(lines, ut.renderImage(path, data.Width, theme)...)
+	}
+
 	// Add spacer
 	spacer := ut.AddSpacer(data.Width, theme)
 	lines = append(lines, spacer)
 
 	return RenderedMessage{Lines: lines}
 }
+
+// renderImage previews an attached image under its file name.
+func (ut *UserTemplate) renderImage(path string, width int, theme styles.Theme) []string {
+	const indent = "  "
+	captionStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	caption := captionStyle.Render("🖼 " + filepath.Base(path))
+	cols := min(imagePreviewCols, width-len(indent)*2)
+	preview, err := imagepreview.Render(path, cols, imagePreviewRows, caption)
+	if err != nil {
+		preview = []string{caption + captionStyle.Render(" (no preview)")}
+	}
+	lines := make([]string, 0, len(preview))
+	for _, line := range preview {
+		lines = append(lines, indent+line)
+	}
+	return lines
+}
+
+// stringsFromAny reads a list of strings from metadata, which holds a
+// []string until it has been through JSON.
+func stringsFromAny(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
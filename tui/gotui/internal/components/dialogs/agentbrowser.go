@@ -0,0 +1,502 @@
+package dialogs
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/stores"
+	"gotui/internal/styles"
+)
+
+// AgentBrowserAction is the action chosen in the agent browser.
+type AgentBrowserAction int
+
+const (
+	AgentBrowserActionNone AgentBrowserAction = iota
+	// AgentBrowserActionSelect uses the agent in the active conversation.
+	AgentBrowserActionSelect
+	// AgentBrowserActionSelectDefault makes the agent the default for new
+	// conversations as well.
+	AgentBrowserActionSelectDefault
+	// AgentBrowserActionInstall asks the server to install the agent.
+	AgentBrowserActionInstall
+)
+
+// AgentBrowser lists the server's agents with search and tag filters next to
+// a preview of the selected agent's details.
+type AgentBrowser struct {
+	store    *stores.AgentStore
+	agents   []stores.AgentOption
+	visible  bool
+	selected int
+	filter   string
+	// tag filters the list; "" shows every agent.
+	tag string
+	// installing is the ID of the agent an install was requested for.
+	installing string
+}
+
+// NewAgentBrowser constructs a browser optionally bound to the supplied store.
+func NewAgentBrowser(store *stores.AgentStore) *AgentBrowser {
+	browser := &AgentBrowser{}
+	browser.BindStore(store)
+	return browser
+}
+
+// BindStore attaches the browser to the shared agent store and lists its
+// agents. Later changes arrive through SetAgents from the update loop, as the
+// store is refreshed in the background.
+func (b *AgentBrowser) BindStore(store *stores.AgentStore) {
+	if b == nil {
+		return
+	}
+	b.store = store
+	if store != nil {
+		b.agents = store.Agents()
+	} else {
+		b.agents = nil
+	}
+	b.clampSelection()
+}
+
+// SetAgents replaces the listed agents, keeping the selected agent selected
+// when it is still listed.
+func (b *AgentBrowser) SetAgents(options []stores.AgentOption) {
+	if b == nil {
+		return
+	}
+	var selectedID string
+	if agent, ok := b.current(); ok {
+		selectedID = agent.ID
+	}
+	b.agents = append([]stores.AgentOption(nil), options...)
+	for _, agent := range b.agents {
+		if agent.ID == b.installing && agent.Available() {
+			b.installing = ""
+		}
+	}
+	if b.tag != "" && !slices.Contains(b.tags(), b.tag) {
+		b.tag = ""
+	}
+	b.selected = 0
+	for i, agent := range b.filtered() {
+		if agent.ID == selectedID {
+			b.selected = i
+			break
+		}
+	}
+}
+
+// InstallFailed clears the pending install of id so it can be retried.
+func (b *AgentBrowser) InstallFailed(id string) {
+	if b != nil && b.installing == id {
+		b.installing = ""
+	}
+}
+
+// Open displays the browser with the search and tag filter cleared.
+func (b *AgentBrowser) Open() {
+	if b == nil {
+		return
+	}
+	if !b.visible {
+		b.selected = 0
+		b.filter = ""
+		b.tag = ""
+	}
+	b.visible = true
+}
+
+// Close hides the browser.
+func (b *AgentBrowser) Close() {
+	if b == nil {
+		return
+	}
+	b.visible = false
+}
+
+// IsVisible reports whether the browser is currently displayed.
+func (b *AgentBrowser) IsVisible() bool {
+	return b != nil && b.visible
+}
+
+// HandleKey processes key events while the browser is visible. Enter on an
+// agent that is not installed yet asks for it to be installed.
+func (b *AgentBrowser) HandleKey(msg tea.KeyPressMsg) (handled bool, action AgentBrowserAction, option stores.AgentOption) {
+	if b == nil || !b.visible {
+		return false, AgentBrowserActionNone, stores.AgentOption{}
+	}
+
+	switch msg.String() {
+	case "esc":
+		if b.filter != "" {
+			b.setFilter("")
+			return true, AgentBrowserActionNone, stores.AgentOption{}
+		}
+		b.Close()
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "enter", "cmd+enter", "ctrl+enter":
+		agent, ok := b.current()
+		if !ok {
+			return true, AgentBrowserActionNone, stores.AgentOption{}
+		}
+		if !agent.Available() {
+			if b.installing != "" {
+				return true, AgentBrowserActionNone, stores.AgentOption{}
+			}
+			b.installing = agent.ID
+			return true, AgentBrowserActionInstall, agent
+		}
+		b.Close()
+		if msg.String() != "enter" {
+			return true, AgentBrowserActionSelectDefault, agent
+		}
+		return true, AgentBrowserActionSelect, agent
+	case "left":
+		b.cycleTag(-1)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "right":
+		b.cycleTag(1)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "down", "ctrl+n":
+		b.move(1)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "up", "ctrl+p", "shift+tab":
+		b.move(-1)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "pgdown":
+		b.move(5)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "pgup":
+		b.move(-5)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "backspace":
+		if runes := []rune(b.filter); len(runes) > 0 {
+			b.setFilter(string(runes[:len(runes)-1]))
+		}
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	case "ctrl+u":
+		b.setFilter("")
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	}
+
+	if msg.Text != "" && msg.Mod&^tea.ModShift == 0 {
+		b.setFilter(b.filter + msg.Text)
+		return true, AgentBrowserActionNone, stores.AgentOption{}
+	}
+
+	return false, AgentBrowserActionNone, stores.AgentOption{}
+}
+
+func (b *AgentBrowser) setFilter(filter string) {
+	b.filter = filter
+	b.selected = 0
+}
+
+func (b *AgentBrowser) move(delta int) {
+	count := len(b.filtered())
+	if count == 0 {
+		b.selected = 0
+		return
+	}
+	b.selected = (b.selected + delta%count + count) % count
+}
+
+func (b *AgentBrowser) clampSelection() {
+	if b.selected >= len(b.filtered()) {
+		b.selected = 0
+	}
+}
+
+// cycleTag steps the tag filter through "all" and every tag.
+func (b *AgentBrowser) cycleTag(delta int) {
+	options := append([]string{""}, b.tags()...)
+	index := 0
+	for i, tag := range options {
+		if tag == b.tag {
+			index = i
+			break
+		}
+	}
+	b.tag = options[(index+delta+len(options))%len(options)]
+	b.selected = 0
+}
+
+func (b *AgentBrowser) current() (stores.AgentOption, bool) {
+	agents := b.filtered()
+	if b.selected < 0 || b.selected >= len(agents) {
+		return stores.AgentOption{}, false
+	}
+	return agents[b.selected], true
+}
+
+// tags lists the tags of all agents, most used first.
+func (b *AgentBrowser) tags() []string {
+	counts := make(map[string]int)
+	for _, agent := range b.agents {
+		for _, tag := range agent.Tags {
+			counts[strings.ToLower(tag)]++
+		}
+	}
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+// filtered applies the tag filter and the search. Words of the search that
+// start with # match tags; the rest is matched fuzzily against the name,
+// description, tags and tools, best match first.
+func (b *AgentBrowser) filtered() []stores.AgentOption {
+	var (
+		words []string
+		tags  []string
+	)
+	if b.tag != "" {
+		tags = append(tags, b.tag)
+	}
+	for _, word := range strings.Fields(b.filter) {
+		if tag, ok := strings.CutPrefix(word, "#"); ok {
+			if tag != "" {
+				tags = append(tags, strings.ToLower(tag))
+			}
+			continue
+		}
+		words = append(words, word)
+	}
+	query := strings.Join(words, " ")
+
+	type scored struct {
+		agent stores.AgentOption
+		score int
+	}
+	var matches []scored
+	for _, agent := range b.agents {
+		if !hasTags(agent, tags) {
+			continue
+		}
+		target := strings.Join([]string{agent.Name, agent.Description, strings.Join(agent.Tags, " "), strings.Join(agent.Tools, " ")}, " ")
+		score, ok := fuzzyScore(query, target)
+		if !ok {
+			continue
+		}
+		matches = append(matches, scored{agent, score})
+	}
+	if query != "" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	}
+	agents := make([]stores.AgentOption, len(matches))
+	for i, match := range matches {
+		agents[i] = match.agent
+	}
+	return agents
+}
+
+// hasTags reports whether agent has a tag starting with each of tags.
+func hasTags(agent stores.AgentOption, tags []string) bool {
+	for _, want := range tags {
+		found := false
+		for _, tag := range agent.Tags {
+			if strings.HasPrefix(strings.ToLower(tag), want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// View renders the browser dialog if visible.
+func (b *AgentBrowser) View(width, height int) string {
+	panel, ok := b.dialogPanel(width, height)
+	if !ok || height <= 0 {
+		return ""
+	}
+	return Wrap(panel, width, height)
+}
+
+// Layer renders as an overlay layer for the chat canvas.
+func (b *AgentBrowser) Layer(width, height int) *lipgloss.Layer {
+	panel, ok := b.dialogPanel(width, height)
+	if !ok || height <= 0 {
+		return nil
+	}
+	return WrapLayer(panel, width, height)
+}
+
+func (b *AgentBrowser) dialogPanel(width, height int) (string, bool) {
+	if b == nil || !b.visible || width <= 0 {
+		return "", false
+	}
+
+	theme := styles.CurrentTheme()
+	panelWidth := clamp(width-12, 60, min(120, width-4))
+	if panelWidth >= width {
+		panelWidth = width - 2
+	}
+	contentWidth := panelWidth - 6
+	listWidth := clamp(contentWidth*2/5, 24, 44)
+	detailWidth := contentWidth - listWidth - 3
+	// Title, hint, tags, search and the frame take about 16 lines.
+	bodyHeight := max(6, height-16)
+
+	headerTitle := lipgloss.NewStyle().
+		Foreground(theme.Primary).
+		Bold(true).
+		Render("Agents")
+	headerHint := lipgloss.NewStyle().
+		Foreground(theme.Muted).
+		Render("Type to search • #tag filters • ← → tags • Enter use/install • Ctrl+Enter default • Esc close")
+
+	search := lipgloss.NewStyle().
+		Foreground(theme.Foreground).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(theme.SurfaceHigh).
+		Padding(0, 1).
+		Width(contentWidth).
+		Render("🔍 " + b.filter + "▏")
+
+	list := lipgloss.NewStyle().Width(listWidth).Height(bodyHeight).MaxHeight(bodyHeight).
+		Render(b.renderList(listWidth, bodyHeight))
+	separator := lipgloss.NewStyle().Foreground(theme.Border).
+		Render(strings.TrimRight(strings.Repeat(" │\n", bodyHeight), "\n"))
+	details := lipgloss.NewStyle().Width(detailWidth).Height(bodyHeight).MaxHeight(bodyHeight).PaddingLeft(1).
+		Render(b.renderDetails(detailWidth - 1))
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, separator, details)
+
+	panel := lipgloss.NewStyle().
+		Width(panelWidth).
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Border).
+		Padding(1, 2).
+		Render(lipgloss.JoinVertical(lipgloss.Left,
+			headerTitle,
+			headerHint,
+			"",
+			b.renderTags(contentWidth),
+			search,
+			body,
+		))
+
+	return panel, true
+}
+
+// renderTags shows the tag filter, highlighting the active one.
+func (b *AgentBrowser) renderTags(width int) string {
+	theme := styles.CurrentTheme()
+	chip := func(label string, active bool) string {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if active {
+			return style.Foreground(theme.Background).Background(theme.Primary).Bold(true).Render(label)
+		}
+		return style.Foreground(theme.Muted).Render(label)
+	}
+	chips := []string{chip("All", b.tag == "")}
+	for _, tag := range b.tags() {
+		chips = append(chips, chip("#"+tag, tag == b.tag))
+	}
+	return lipgloss.NewStyle().Width(width).MaxWidth(width).MaxHeight(1).Render(strings.Join(chips, " "))
+}
+
+func (b *AgentBrowser) renderList(width, height int) string {
+	theme := styles.CurrentTheme()
+	agents := b.filtered()
+	if len(agents) == 0 {
+		message := "No agents available"
+		if b.filter != "" || b.tag != "" {
+			message = "No agents match"
+		}
+		return lipgloss.NewStyle().Foreground(theme.Muted).Padding(1, 1).Render(message)
+	}
+
+	start := 0
+	if len(agents) > height {
+		start = clamp(b.selected-height/2, 0, len(agents)-height)
+	}
+	end := min(len(agents), start+height)
+	rows := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		agent := agents[i]
+		indicator := "  "
+		nameStyle := lipgloss.NewStyle().Foreground(theme.Foreground)
+		if i == b.selected {
+			indicator = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+			nameStyle = nameStyle.Bold(true).Foreground(theme.Primary)
+		}
+		status := ""
+		switch {
+		case agent.ID == b.installing:
+			status = lipgloss.NewStyle().Foreground(theme.Warning).Render(" …")
+		case !agent.Available():
+			status = lipgloss.NewStyle().Foreground(theme.Muted).Render(" ⤓")
+		}
+		name := truncate(agent.Name, width-2-lipgloss.Width(status))
+		rows = append(rows, indicator+nameStyle.Render(name)+status)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// renderDetails previews the selected agent.
+func (b *AgentBrowser) renderDetails(width int) string {
+	theme := styles.CurrentTheme()
+	agent, ok := b.current()
+	if !ok {
+		return ""
+	}
+	label := lipgloss.NewStyle().Foreground(theme.Muted)
+	value := lipgloss.NewStyle().Foreground(theme.Foreground)
+	field := func(name, text string) string {
+		if strings.TrimSpace(text) == "" {
+			text = "—"
+		}
+		return label.Render(fmt.Sprintf("%-9s", name)) + value.Render(text)
+	}
+
+	title := lipgloss.NewStyle().Foreground(theme.Primary).Bold(true).Render(agent.Name)
+	status := lipgloss.NewStyle().Foreground(theme.Success).Render("● Installed")
+	switch {
+	case agent.ID == b.installing:
+		status = lipgloss.NewStyle().Foreground(theme.Warning).Render("… Installing")
+	case !agent.Available():
+		status = lipgloss.NewStyle().Foreground(theme.Muted).Render("⤓ Available — Enter to install")
+	}
+
+	tags := make([]string, len(agent.Tags))
+	for i, tag := range agent.Tags {
+		tags[i] = "#" + tag
+	}
+	lines := []string{
+		title,
+		status,
+		"",
+		field("ID", agent.ID),
+		field("Version", agent.Version),
+		field("Type", agent.Type),
+		field("Author", agent.Author),
+		field("Tags", strings.Join(tags, " ")),
+		field("Tools", strings.Join(agent.Tools, ", ")),
+	}
+	description := strings.TrimSpace(agent.LongDescription)
+	if description == "" {
+		description = strings.TrimSpace(agent.Description)
+	}
+	if description != "" {
+		lines = append(lines, "", value.Width(width).Render(description))
+	}
+	return lipgloss.NewStyle().Width(width).Render(strings.Join(lines, "\n"))
+}
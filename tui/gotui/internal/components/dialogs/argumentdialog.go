@@ -0,0 +1,190 @@
+package dialogs
+
+import (
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/styles"
+)
+
+// ArgumentField is a value the argument dialog asks for.
+type ArgumentField struct {
+	Name        string
+	Description string
+	Value       string
+}
+
+// ArgumentDialog prompts for the arguments a slash command was invoked
+// without, one field at a time.
+type ArgumentDialog struct {
+	title   string
+	fields  []ArgumentField
+	current int
+	visible bool
+}
+
+// NewArgumentDialog constructs a hidden argument dialog.
+func NewArgumentDialog() *ArgumentDialog {
+	return &ArgumentDialog{}
+}
+
+// Open shows the dialog for fields under title, e.g. the command's usage.
+func (d *ArgumentDialog) Open(title string, fields []ArgumentField) {
+	d.title = title
+	d.fields = append([]ArgumentField(nil), fields...)
+	d.current = 0
+	d.visible = len(d.fields) > 0
+}
+
+// Close hides the dialog.
+func (d *ArgumentDialog) Close() {
+	d.visible = false
+}
+
+// IsVisible reports whether the dialog is currently shown.
+func (d *ArgumentDialog) IsVisible() bool {
+	return d.visible
+}
+
+// Paste inserts pasted text into the current field. Line breaks become
+// spaces.
+func (d *ArgumentDialog) Paste(text string) {
+	if !d.visible {
+		return
+	}
+	text = strings.Join(strings.Fields(strings.ReplaceAll(text, "\r", "")), " ")
+	d.fields[d.current].Value += text
+}
+
+// HandleKey edits the current field. Enter moves to the next field and, on
+// the last one, returns the values by field name.
+func (d *ArgumentDialog) HandleKey(msg tea.KeyPressMsg) (handled bool, values map[string]string, ok bool) {
+	if !d.visible {
+		return false, nil, false
+	}
+
+	field := &d.fields[d.current]
+	switch msg.String() {
+	case "esc":
+		d.Close()
+		return true, nil, false
+	case "enter":
+		if strings.TrimSpace(field.Value) == "" {
+			return true, nil, false
+		}
+		if d.current < len(d.fields)-1 {
+			d.current++
+			return true, nil, false
+		}
+		d.Close()
+		values = make(map[string]string, len(d.fields))
+		for _, f := range d.fields {
+			values[f.Name] = strings.TrimSpace(f.Value)
+		}
+		return true, values, true
+	case "tab", "down":
+		d.current = (d.current + 1) % len(d.fields)
+		return true, nil, false
+	case "shift+tab", "up":
+		d.current = (d.current - 1 + len(d.fields)) % len(d.fields)
+		return true, nil, false
+	case "backspace":
+		if runes := []rune(field.Value); len(runes) > 0 {
+			field.Value = string(runes[:len(runes)-1])
+		}
+		return true, nil, false
+	case "ctrl+u":
+		field.Value = ""
+		return true, nil, false
+	}
+
+	if msg.Text != "" && msg.Mod&^tea.ModShift == 0 {
+		field.Value += msg.Text
+	}
+	// The dialog is modal; swallow everything else.
+	return true, nil, false
+}
+
+// View renders the dialog.
+func (d *ArgumentDialog) View(width, height int) string {
+	panel, ok := d.dialogPanel(width)
+	if !ok || height <= 0 {
+		return ""
+	}
+	return Wrap(panel, width, height)
+}
+
+// Layer renders the dialog as an overlay layer.
+func (d *ArgumentDialog) Layer(width, height int) *lipgloss.Layer {
+	panel, ok := d.dialogPanel(width)
+	if !ok || height <= 0 {
+		return nil
+	}
+	return WrapLayer(panel, width, height)
+}
+
+func (d *ArgumentDialog) dialogPanel(width int) (string, bool) {
+	if !d.visible || width <= 0 {
+		return "", false
+	}
+
+	theme := styles.CurrentTheme()
+	panelWidth := clamp(width-10, 44, int(math.Min(72, float64(width-4))))
+	if panelWidth <= 0 {
+		panelWidth = width
+	}
+	contentWidth := panelWidth - 8
+
+	headerTitle := lipgloss.NewStyle().
+		Foreground(theme.Primary).
+		Bold(true).
+		Render(d.title)
+	headerHint := lipgloss.NewStyle().
+		Foreground(theme.Muted).
+		Render("Enter to continue • Tab to switch • Esc to cancel")
+	rows := []string{headerTitle, headerHint, ""}
+
+	for i, field := range d.fields {
+		label := lipgloss.NewStyle().Foreground(theme.Foreground).Bold(true).Render(field.Name)
+		if field.Description != "" {
+			label = lipgloss.JoinHorizontal(lipgloss.Left, label, "  ",
+				lipgloss.NewStyle().Foreground(theme.Muted).Render(field.Description))
+		}
+		border := theme.SurfaceHigh
+		value := field.Value
+		if i == d.current {
+			border = theme.Accent
+			value += "▏"
+		}
+		input := lipgloss.NewStyle().
+			Foreground(theme.Foreground).
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(border).
+			Padding(0, 1).
+			Width(contentWidth).
+			MaxHeight(3).
+			Render(tailFit(value, contentWidth-4))
+		rows = append(rows, label, input)
+	}
+
+	panel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return panel, true
+}
+
+// tailFit keeps the end of s within width cells so the cursor stays visible.
+func tailFit(s string, width int) string {
+	runes := []rune(s)
+	for width > 0 && lipgloss.Width(string(runes)) > width {
+		runes = runes[1:]
+	}
+	return string(runes)
+}
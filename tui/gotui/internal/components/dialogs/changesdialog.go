@@ -0,0 +1,225 @@
+package dialogs
+
+import (
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/styles"
+)
+
+// ChangeOption describes a file the agent changed in the conversation.
+type ChangeOption struct {
+	Path      string
+	Display   string
+	Operation string
+	Edits     int
+	Added     int
+	Removed   int
+	// Source names what a revert would restore from, e.g. "snapshot" or "git".
+	Source string
+}
+
+// ChangeAction is the action chosen in the changes dialog.
+type ChangeAction int
+
+const (
+	ChangeActionNone ChangeAction = iota
+	ChangeActionRevert
+	// ChangeActionForceRevert reverts even if the file was edited since.
+	ChangeActionForceRevert
+	ChangeActionRevertAll
+)
+
+// ChangesDialog lists the conversation's file changes with revert actions.
+type ChangesDialog struct {
+	options    []ChangeOption
+	visible    bool
+	selected   int
+	confirmAll bool
+}
+
+// NewChangesDialog constructs an empty changes dialog.
+func NewChangesDialog() *ChangesDialog {
+	return &ChangesDialog{}
+}
+
+// SetOptions replaces the listed changes, keeping the selection in range.
+func (d *ChangesDialog) SetOptions(options []ChangeOption) {
+	d.options = options
+	if d.selected >= len(d.options) {
+		d.selected = len(d.options) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+}
+
+// Open shows the dialog with the most recent change selected.
+func (d *ChangesDialog) Open() {
+	d.selected = 0
+	d.confirmAll = false
+	d.visible = true
+}
+
+// Close hides the dialog.
+func (d *ChangesDialog) Close() {
+	d.visible = false
+	d.confirmAll = false
+}
+
+// IsVisible reports whether the dialog is shown.
+func (d *ChangesDialog) IsVisible() bool {
+	return d.visible
+}
+
+// HandleKey processes navigation and revert keys. For single-file actions
+// option is the selected change.
+func (d *ChangesDialog) HandleKey(msg tea.KeyPressMsg) (handled bool, action ChangeAction, option ChangeOption) {
+	if !d.visible {
+		return false, ChangeActionNone, ChangeOption{}
+	}
+
+	if d.confirmAll {
+		d.confirmAll = false
+		if msg.String() == "y" || msg.String() == "Y" {
+			d.Close()
+			return true, ChangeActionRevertAll, ChangeOption{}
+		}
+		return true, ChangeActionNone, ChangeOption{}
+	}
+
+	switch msg.String() {
+	case "esc":
+		d.Close()
+		return true, ChangeActionNone, ChangeOption{}
+	case "down", "j", "ctrl+n":
+		d.move(1)
+		return true, ChangeActionNone, ChangeOption{}
+	case "up", "k", "ctrl+p":
+		d.move(-1)
+		return true, ChangeActionNone, ChangeOption{}
+	case "enter", "r", "f":
+		if len(d.options) == 0 {
+			return true, ChangeActionNone, ChangeOption{}
+		}
+		action := ChangeActionRevert
+		if msg.String() == "f" {
+			action = ChangeActionForceRevert
+		}
+		return true, action, d.options[d.selected]
+	case "R":
+		if len(d.options) > 0 {
+			d.confirmAll = true
+		}
+		return true, ChangeActionNone, ChangeOption{}
+	}
+
+	return false, ChangeActionNone, ChangeOption{}
+}
+
+func (d *ChangesDialog) move(delta int) {
+	if len(d.options) == 0 {
+		return
+	}
+	limit := len(d.options)
+	d.selected = (d.selected + delta + limit) % limit
+}
+
+// View renders the dialog.
+func (d *ChangesDialog) View(width, height int) string {
+	panel, ok := d.dialogPanel(width)
+	if !ok || height <= 0 {
+		return ""
+	}
+	return Wrap(panel, width, height)
+}
+
+// Layer renders the dialog as an overlay layer.
+func (d *ChangesDialog) Layer(width, height int) *lipgloss.Layer {
+	panel, ok := d.dialogPanel(width)
+	if !ok || height <= 0 {
+		return nil
+	}
+	return WrapLayer(panel, width, height)
+}
+
+func (d *ChangesDialog) dialogPanel(width int) (string, bool) {
+	if !d.visible || width <= 0 {
+		return "", false
+	}
+
+	theme := styles.CurrentTheme()
+	panelWidth := clamp(width-10, 50, int(math.Min(90, float64(width-4))))
+	if panelWidth <= 0 {
+		panelWidth = width
+	}
+	contentWidth := panelWidth - 6
+
+	headerTitle := lipgloss.NewStyle().
+		Foreground(theme.Primary).
+		Bold(true).
+		Render(fmt.Sprintf("Changes (%d)", len(d.options)))
+	hint := "↑ ↓ navigate • r revert file • f force revert • R revert all • Esc close"
+	hintStyle := lipgloss.NewStyle().Foreground(theme.Muted)
+	if d.confirmAll {
+		hint = fmt.Sprintf("Revert all %d files to their state before this conversation? (y/N)", len(d.options))
+		hintStyle = lipgloss.NewStyle().Foreground(theme.Warning).Bold(true)
+	}
+	header := lipgloss.JoinVertical(lipgloss.Left, headerTitle, hintStyle.Width(contentWidth).Render(hint), "")
+
+	var rows []string
+	if len(d.options) == 0 {
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Padding(1, 2).
+			Render("The agent has not changed any files in this conversation"))
+	}
+	for i, opt := range d.options {
+		rows = append(rows, d.renderOption(opt, i == d.selected, contentWidth))
+	}
+
+	panel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, lipgloss.JoinVertical(lipgloss.Left, rows...)))
+
+	return panel, true
+}
+
+func (d *ChangesDialog) renderOption(opt ChangeOption, selected bool, width int) string {
+	theme := styles.CurrentTheme()
+
+	name := lipgloss.NewStyle().Foreground(theme.Foreground).Bold(true).Render(opt.Display)
+	stat := lipgloss.JoinHorizontal(lipgloss.Left,
+		lipgloss.NewStyle().Foreground(theme.Success).Render(fmt.Sprintf("+%d", opt.Added)),
+		" ",
+		lipgloss.NewStyle().Foreground(theme.Error).Render(fmt.Sprintf("-%d", opt.Removed)),
+	)
+	edits := "1 edit"
+	if opt.Edits != 1 {
+		edits = fmt.Sprintf("%d edits", opt.Edits)
+	}
+	source := "revert from " + opt.Source
+	if opt.Source == "" {
+		source = "no snapshot to revert from"
+	}
+	detail := fmt.Sprintf("%s • %s • %s", opt.Operation, edits, source)
+	if opt.Operation == "" {
+		detail = fmt.Sprintf("%s • %s", edits, source)
+	}
+	details := lipgloss.NewStyle().Foreground(theme.Muted).Render(detail)
+
+	indicator := "  "
+	rowStyle := lipgloss.NewStyle().Width(width).MaxWidth(width)
+	if selected {
+		indicator = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+		rowStyle = rowStyle.Foreground(theme.Foreground)
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Left, name, "  ", stat), details)
+	return rowStyle.Render(lipgloss.JoinHorizontal(lipgloss.Left, indicator, body))
+}
@@ -190,6 +190,9 @@ func (p *CommandPalette) dialogPanel(width int) (string, bool) {
 func (p *CommandPalette) renderItem(cmd SlashCommand, selected bool, width int) string {
 	theme := styles.CurrentTheme()
 	name := lipgloss.NewStyle().Foreground(theme.Foreground).Bold(true).Render("/" + cmd.Name)
+	if cmd.Shortcut != "" {
+		name = lipgloss.JoinHorizontal(lipgloss.Left, name, "  ", lipgloss.NewStyle().Foreground(theme.Primary).Render(cmd.Shortcut))
+	}
 	desc := lipgloss.NewStyle().Foreground(theme.Muted).Render(cmd.Description)
 	usage := lipgloss.NewStyle().Foreground(theme.Secondary).Italic(true).Render(cmd.Usage)
 
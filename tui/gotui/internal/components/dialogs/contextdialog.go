@@ -0,0 +1,200 @@
+package dialogs
+
+import (
+	"fmt"
+	"math"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/styles"
+	"gotui/internal/usage"
+)
+
+// ContextAction is the action chosen in the context dialog.
+type ContextAction int
+
+const (
+	ContextActionNone ContextAction = iota
+	// ContextActionToggle drops the selected item or puts it back.
+	ContextActionToggle
+	ContextActionRestoreAll
+)
+
+// ContextDialog lists what the next request sends to the agent and lets the
+// user drop items from it.
+type ContextDialog struct {
+	window   agentcontext.Window
+	visible  bool
+	selected int
+}
+
+// NewContextDialog constructs an empty context dialog.
+func NewContextDialog() *ContextDialog {
+	return &ContextDialog{}
+}
+
+// SetWindow replaces the listed context, keeping the selection in range.
+func (d *ContextDialog) SetWindow(window agentcontext.Window) {
+	d.window = window
+	if d.selected >= len(d.window.Items) {
+		d.selected = len(d.window.Items) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+}
+
+// Open shows the dialog with the first item selected.
+func (d *ContextDialog) Open() {
+	d.selected = 0
+	d.visible = true
+}
+
+// Close hides the dialog.
+func (d *ContextDialog) Close() {
+	d.visible = false
+}
+
+// IsVisible reports whether the dialog is shown.
+func (d *ContextDialog) IsVisible() bool {
+	return d.visible
+}
+
+// HandleKey processes navigation and drop keys. For toggles item is the
+// selected entry.
+func (d *ContextDialog) HandleKey(msg tea.KeyPressMsg) (handled bool, action ContextAction, item agentcontext.Item) {
+	if !d.visible {
+		return false, ContextActionNone, agentcontext.Item{}
+	}
+
+	switch msg.String() {
+	case "esc":
+		d.Close()
+		return true, ContextActionNone, agentcontext.Item{}
+	case "down", "j", "ctrl+n":
+		d.move(1)
+		return true, ContextActionNone, agentcontext.Item{}
+	case "up", "k", "ctrl+p":
+		d.move(-1)
+		return true, ContextActionNone, agentcontext.Item{}
+	case "space", " ", "enter", "d", "x":
+		if len(d.window.Items) == 0 || !d.window.Items[d.selected].Droppable() {
+			return true, ContextActionNone, agentcontext.Item{}
+		}
+		return true, ContextActionToggle, d.window.Items[d.selected]
+	case "a":
+		return true, ContextActionRestoreAll, agentcontext.Item{}
+	}
+
+	return false, ContextActionNone, agentcontext.Item{}
+}
+
+func (d *ContextDialog) move(delta int) {
+	if len(d.window.Items) == 0 {
+		return
+	}
+	limit := len(d.window.Items)
+	d.selected = (d.selected + delta + limit) % limit
+}
+
+// View renders the dialog.
+func (d *ContextDialog) View(width, height int) string {
+	panel, ok := d.dialogPanel(width, height)
+	if !ok || height <= 0 {
+		return ""
+	}
+	return Wrap(panel, width, height)
+}
+
+// Layer renders the dialog as an overlay layer.
+func (d *ContextDialog) Layer(width, height int) *lipgloss.Layer {
+	panel, ok := d.dialogPanel(width, height)
+	if !ok || height <= 0 {
+		return nil
+	}
+	return WrapLayer(panel, width, height)
+}
+
+func (d *ContextDialog) dialogPanel(width, height int) (string, bool) {
+	if !d.visible || width <= 0 {
+		return "", false
+	}
+
+	theme := styles.CurrentTheme()
+	panelWidth := clamp(width-10, 50, int(math.Min(90, float64(width-4))))
+	if panelWidth <= 0 {
+		panelWidth = width
+	}
+	contentWidth := panelWidth - 6
+
+	title := fmt.Sprintf("Context · ~%s tokens", usage.FormatTokens(d.window.Used()))
+	if d.window.Limit > 0 {
+		title = fmt.Sprintf("Context · ~%s of %s tokens (%.0f%%)", usage.FormatTokens(d.window.Used()), usage.FormatTokens(d.window.Limit), d.window.Fraction()*100)
+	}
+	headerTitle := lipgloss.NewStyle().
+		Foreground(theme.Primary).
+		Bold(true).
+		Render(title)
+	hint := lipgloss.NewStyle().
+		Foreground(theme.Muted).
+		Width(contentWidth).
+		Render("↑ ↓ navigate • space drop/restore • a restore all • Esc close")
+	header := lipgloss.JoinVertical(lipgloss.Left, headerTitle, hint, "")
+
+	var rows []string
+	if len(d.window.Items) == 0 {
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Padding(1, 2).
+			Render("Nothing but your message yet. Mention files with @path."))
+	}
+	// Show a window of rows around the selection when the list is long.
+	visibleRows := max(height-12, 5)
+	start := 0
+	if len(d.window.Items) > visibleRows {
+		start = min(max(d.selected-visibleRows/2, 0), len(d.window.Items)-visibleRows)
+	}
+	end := min(start+visibleRows, len(d.window.Items))
+	for i := start; i < end; i++ {
+		rows = append(rows, d.renderItem(d.window.Items[i], i == d.selected, contentWidth))
+	}
+	if end < len(d.window.Items) {
+		rows = append(rows, lipgloss.NewStyle().Foreground(theme.Muted).Render(fmt.Sprintf("  … %d more", len(d.window.Items)-end)))
+	}
+
+	panel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, lipgloss.JoinVertical(lipgloss.Left, rows...)))
+
+	return panel, true
+}
+
+func (d *ContextDialog) renderItem(item agentcontext.Item, selected bool, width int) string {
+	theme := styles.CurrentTheme()
+
+	icon := "💬"
+	switch item.Kind {
+	case agentcontext.KindFile:
+		icon = "📄"
+	case agentcontext.KindImage:
+		icon = "🖼"
+	}
+	labelStyle := lipgloss.NewStyle().Foreground(theme.Foreground)
+	tokens := lipgloss.NewStyle().Foreground(theme.Muted).Render(fmt.Sprintf("~%s", usage.FormatTokens(item.Tokens)))
+	if item.Dropped {
+		labelStyle = lipgloss.NewStyle().Foreground(theme.Muted).Strikethrough(true)
+		tokens = lipgloss.NewStyle().Foreground(theme.Warning).Render("dropped")
+	}
+
+	indicator := "  "
+	rowStyle := lipgloss.NewStyle().Width(width).MaxWidth(width)
+	if selected {
+		indicator = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+	}
+	return rowStyle.Render(lipgloss.JoinHorizontal(lipgloss.Left, indicator, icon, " ", labelStyle.Render(item.Label), "  ", tokens))
+}
@@ -0,0 +1,73 @@
+package dialogs
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyScore matches query against target case-insensitively. Every
+// space-separated word of query must appear in target as a subsequence, in
+// any order. Matches that are contiguous or start at word boundaries score
+// higher. It reports false when a word does not match.
+func fuzzyScore(query, target string) (int, bool) {
+	words := strings.Fields(strings.ToLower(query))
+	if len(words) == 0 {
+		return 0, true
+	}
+	lower := []rune(strings.ToLower(target))
+	original := []rune(target)
+	total := 0
+	for _, word := range words {
+		score, ok := fuzzyWord([]rune(word), lower, original)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+func fuzzyWord(word, lower, original []rune) (int, bool) {
+	score := 0
+	if strings.Contains(string(lower), string(word)) {
+		score += 10 * len(word)
+	}
+	last := -1
+	for _, r := range word {
+		found := -1
+		for i := last + 1; i < len(lower); i++ {
+			if lower[i] == r {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+		score++
+		switch {
+		case found == last+1 && last >= 0:
+			score += 3
+		case last >= 0:
+			score -= min(found-last-1, 3)
+		}
+		if wordStart(original, found) {
+			score += 5
+		}
+		last = found
+	}
+	return score, true
+}
+
+// wordStart reports whether s[i] begins a word: after a separator or at a
+// lower-to-upper case change.
+func wordStart(s []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := s[i-1]
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(s[i])
+}
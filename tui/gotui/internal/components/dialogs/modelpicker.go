@@ -1,6 +1,9 @@
 package dialogs
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
@@ -10,63 +13,111 @@ import (
 	"gotui/internal/styles"
 )
 
+// ModelPickerScope selects what a picked model applies to.
+type ModelPickerScope int
+
+const (
+	// ModelScopeChat sets the model of the active conversation.
+	ModelScopeChat ModelPickerScope = iota
+	// ModelScopeDefault sets the default model for new conversations.
+	ModelScopeDefault
+)
+
 const (
-	modelPickerActionApply        = "Apply to this chat ↵"
-	modelPickerActionApplyDefault = "Apply as default ⌘↵ / Ctrl+↵"
+	modelSectionFavorites = "★ Favourites"
+	modelSectionRecent    = "Recent"
+	modelSectionMatches   = "Matches"
 )
 
+// Column widths of the model table.
+const (
+	modelBadgesWidth  = 22
+	modelContextWidth = 8
+)
+
+// ModelPicker is a searchable table of the available models, grouped by
+// provider with favourite and recent models pinned to the top.
 type ModelPicker struct {
-	store       *stores.AIModelStore
-	unsubscribe func()
-	options     []stores.ModelOption
-	visible     bool
-	selected    int
+	store    *stores.AIModelStore
+	settings *stores.ApplicationSettingsStore
+	options  []stores.ModelOption
+	visible  bool
+	selected int
+	filter   string
+	scope    ModelPickerScope
+	current  *stores.ModelOption
+}
+
+// modelEntry is a selectable row of the table under its section heading.
+type modelEntry struct {
+	option  stores.ModelOption
+	section string
 }
 
 func NewModelPicker(store *stores.AIModelStore) *ModelPicker {
-	picker := &ModelPicker{}
+	picker := &ModelPicker{settings: stores.SharedApplicationSettingsStore()}
 	picker.BindStore(store)
 	return picker
 }
 
-// BindStore attaches the picker to the shared model store so it stays in sync with updates.
+// BindStore attaches the picker to the shared model store and lists its
+// models. Later changes arrive through SetOptions from the update loop, as
+// the store is refreshed in the background.
 func (p *ModelPicker) BindStore(store *stores.AIModelStore) {
 	if p == nil {
 		return
 	}
-	if p.unsubscribe != nil {
-		p.unsubscribe()
-		p.unsubscribe = nil
-	}
 	p.store = store
 	if store != nil {
 		p.options = store.Models()
-		p.unsubscribe = store.Subscribe(func(models []stores.ModelOption) {
-			p.SetOptions(models)
-		})
 	} else {
 		p.options = nil
 	}
-	if p.selected >= len(p.options) {
-		p.selected = 0
-	}
+	p.clampSelection()
 }
 
+// SetOptions replaces the listed models, keeping the highlighted model
+// highlighted when it is still listed.
 func (p *ModelPicker) SetOptions(options []stores.ModelOption) {
 	if p == nil {
 		return
 	}
+	var highlighted *stores.ModelOption
+	if entries := p.entries(); p.selected >= 0 && p.selected < len(entries) {
+		highlighted = &entries[p.selected].option
+	}
 	copySlice := make([]stores.ModelOption, len(options))
 	copy(copySlice, options)
 	p.options = copySlice
-	if p.selected >= len(p.options) {
-		p.selected = 0
+	if highlighted != nil {
+		p.selectOption(*highlighted)
+		return
 	}
+	p.clampSelection()
 }
 
-func (p *ModelPicker) Open() {
-	if !p.visible {
-		p.selected = 0
+// Open shows the picker for scope with the search cleared. current is the
+// active conversation's model, which starts selected.
+func (p *ModelPicker) Open(current *stores.ModelOption, scope ModelPickerScope) {
+	p.current = nil
+	if current != nil {
+		model := *current
+		p.current = &model
+	}
+	p.scope = scope
+	p.filter = ""
+	p.selected = 0
+	target := p.current
+	if scope == ModelScopeDefault {
+		target = p.settings.Settings().DefaultModel
+	}
+	if target != nil {
+		for i, entry := range p.entries() {
+			if stores.SameModel(entry.option, *target) {
+				p.selected = i
+				break
+			}
+		}
 	}
 	p.visible = true
 }
@@ -79,6 +130,9 @@ func (p *ModelPicker) IsVisible() bool {
 	return p.visible
 }
 
+// HandleKey navigates, searches and picks. applyDefault reports that the
+// model was picked as the default for new conversations rather than for the
+// active one.
 func (p *ModelPicker) HandleKey(msg tea.KeyPressMsg) (handled bool, option stores.ModelOption, applyDefault bool, ok bool) {
 	if !p.visible {
 		return false, stores.ModelOption{}, false, false
@@ -86,54 +140,166 @@ func (p *ModelPicker) HandleKey(msg tea.KeyPressMsg) (handled bool, option store
 
 	switch msg.String() {
 	case "esc":
-		p.Close()
-		return true, stores.ModelOption{}, false, false
-	case "cmd+enter", "ctrl+enter":
-		if len(p.options) == 0 {
-			p.Close()
+		if p.filter != "" {
+			p.setFilter("")
 			return true, stores.ModelOption{}, false, false
 		}
-		opt := p.options[p.selected]
 		p.Close()
-		return true, opt, true, true
-	case "enter":
-		if len(p.options) == 0 {
-			p.Close()
+		return true, stores.ModelOption{}, false, false
+	case "cmd+enter", "ctrl+enter", "enter":
+		entries := p.entries()
+		if len(entries) == 0 {
 			return true, stores.ModelOption{}, false, false
 		}
-		opt := p.options[p.selected]
+		asDefault := p.scope == ModelScopeDefault || msg.String() != "enter"
 		p.Close()
-		return true, opt, false, true
+		return true, entries[p.selected].option, asDefault, true
+	case "left", "right":
+		if p.scope == ModelScopeChat {
+			p.scope = ModelScopeDefault
+		} else {
+			p.scope = ModelScopeChat
+		}
+		return true, stores.ModelOption{}, false, false
+	case "ctrl+f":
+		if entries := p.entries(); len(entries) > 0 {
+			chosen := entries[p.selected].option
+			p.settings.ToggleFavoriteModel(chosen)
+			p.selectOption(chosen)
+		}
+		return true, stores.ModelOption{}, false, false
 	case "down", "ctrl+n":
 		p.move(1)
 		return true, stores.ModelOption{}, false, false
-	case "up", "ctrl+p":
+	case "up", "ctrl+p", "shift+tab":
 		p.move(-1)
 		return true, stores.ModelOption{}, false, false
 	case "pgdown":
-		p.move(3)
+		p.move(5)
 		return true, stores.ModelOption{}, false, false
 	case "pgup":
-		p.move(-3)
+		p.move(-5)
 		return true, stores.ModelOption{}, false, false
-	case "shift+tab":
-		p.move(-1)
+	case "backspace":
+		if runes := []rune(p.filter); len(runes) > 0 {
+			p.setFilter(string(runes[:len(runes)-1]))
+		}
+		return true, stores.ModelOption{}, false, false
+	case "ctrl+u":
+		p.setFilter("")
+		return true, stores.ModelOption{}, false, false
+	}
+
+	if msg.Text != "" && msg.Mod&^tea.ModShift == 0 {
+		p.setFilter(p.filter + msg.Text)
 		return true, stores.ModelOption{}, false, false
 	}
 
 	return false, stores.ModelOption{}, false, false
 }
 
+func (p *ModelPicker) setFilter(filter string) {
+	p.filter = filter
+	p.selected = 0
+}
+
 func (p *ModelPicker) move(delta int) {
-	if len(p.options) == 0 {
+	count := len(p.entries())
+	if count == 0 {
+		p.selected = 0
 		return
 	}
-	maxIndex := len(p.options)
-	p.selected = (p.selected + delta + maxIndex) % maxIndex
+	p.selected = (p.selected + delta%count + count) % count
+}
+
+func (p *ModelPicker) clampSelection() {
+	if p.selected >= len(p.entries()) {
+		p.selected = 0
+	}
+}
+
+// selectOption keeps model selected after the rows move, e.g. when it is
+// pinned or unpinned.
+func (p *ModelPicker) selectOption(model stores.ModelOption) {
+	for i, entry := range p.entries() {
+		if stores.SameModel(entry.option, model) {
+			p.selected = i
+			return
+		}
+	}
+	p.clampSelection()
+}
+
+// entries lists the selectable rows. Without a search, favourites and
+// recent models come first, then the remaining models by provider; with a
+// search, the matches ranked best first.
+func (p *ModelPicker) entries() []modelEntry {
+	settings := p.settings.Settings()
+	if strings.TrimSpace(p.filter) != "" {
+		type scored struct {
+			entry modelEntry
+			score int
+		}
+		var matches []scored
+		for _, option := range p.options {
+			target := option.Name + " " + option.Provider + " " + strings.Join(option.Capabilities, " ")
+			if score, ok := fuzzyScore(p.filter, target); ok {
+				if settings.IsFavoriteModel(option) {
+					score += 2
+				}
+				matches = append(matches, scored{modelEntry{option: option, section: modelSectionMatches}, score})
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+		entries := make([]modelEntry, len(matches))
+		for i, match := range matches {
+			entries[i] = match.entry
+		}
+		return entries
+	}
+
+	var entries []modelEntry
+	pinned := make(map[int]bool)
+	pin := func(models []stores.ModelOption, section string) {
+		for _, model := range models {
+			for i, option := range p.options {
+				if !pinned[i] && stores.SameModel(option, model) {
+					pinned[i] = true
+					entries = append(entries, modelEntry{option: option, section: section})
+					break
+				}
+			}
+		}
+	}
+	pin(settings.FavoriteModels, modelSectionFavorites)
+	pin(settings.RecentModels, modelSectionRecent)
+
+	var providers []string
+	byProvider := make(map[string][]stores.ModelOption)
+	for i, option := range p.options {
+		if pinned[i] {
+			continue
+		}
+		provider := strings.TrimSpace(option.Provider)
+		if provider == "" {
+			provider = "Other"
+		}
+		if _, ok := byProvider[provider]; !ok {
+			providers = append(providers, provider)
+		}
+		byProvider[provider] = append(byProvider[provider], option)
+	}
+	sort.Strings(providers)
+	for _, provider := range providers {
+		for _, option := range byProvider[provider] {
+			entries = append(entries, modelEntry{option: option, section: provider})
+		}
+	}
+	return entries
 }
 
 func (p *ModelPicker) View(width, height int) string {
-	panel, ok := p.dialogPanel(width)
+	panel, ok := p.dialogPanel(width, height)
 	if !ok || height <= 0 {
 		return ""
 	}
@@ -148,200 +314,226 @@ func (p *ModelPicker) View(width, height int) string {
 
 // Layer returns the picker rendered as a dialog layer so the background remains visible.
 func (p *ModelPicker) Layer(width, height int) *lipgloss.Layer {
-	panel, ok := p.dialogPanel(width)
+	panel, ok := p.dialogPanel(width, height)
 	if !ok || height <= 0 {
 		return nil
 	}
 	return WrapLayer(panel, width, height)
 }
 
-func (p *ModelPicker) dialogPanel(width int) (string, bool) {
+func (p *ModelPicker) dialogPanel(width, height int) (string, bool) {
 	if !p.visible || width <= 0 {
 		return "", false
 	}
 
 	theme := styles.CurrentTheme()
-
-	minPanel := 44
-	maxPanel := width - 6
-	if maxPanel < minPanel {
-		maxPanel = minPanel
-	}
-	desiredPanel := width - 12
-	if desiredPanel < minPanel {
-		desiredPanel = minPanel
-	}
-	panelWidth := clamp(desiredPanel, minPanel, maxPanel)
+	panelWidth := clamp(width-12, 60, min(110, width-4))
 	if panelWidth >= width {
-		panelWidth = width - 4
-		if panelWidth < minPanel {
-			panelWidth = minPanel
-		}
-	}
-	if panelWidth <= 0 {
-		panelWidth = width
-	}
-
-	paddingX := 4
-	contentWidth := panelWidth - (paddingX * 2)
-	if contentWidth < 32 {
-		contentWidth = panelWidth - 2
+		panelWidth = width - 2
 	}
+	paddingX := 2
+	contentWidth := panelWidth - paddingX*2 - 2
 
 	headerTitle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.Primary.Hex())).
+		Foreground(theme.Primary).
 		Bold(true).
-		Padding(0, 3).
-		Render("Model Palette")
+		Render("Models")
 	headerHint := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.Muted.Hex())).
-		Padding(0, 1).
-		Render("Use ↑ ↓ to navigate • Enter to apply • Cmd/Ctrl+Enter default • Esc to cancel")
-	header := lipgloss.JoinVertical(lipgloss.Left, headerTitle, headerHint, "")
+		Foreground(theme.Muted).
+		Render("Type to search • ↑ ↓ navigate • ← → chat/default • Ctrl+F favourite • Enter apply • Esc close")
 
-	rows := p.renderOptions(contentWidth)
-	body := lipgloss.JoinVertical(lipgloss.Left, rows...)
-
-	actions := p.renderActions(contentWidth)
-	body = lipgloss.JoinVertical(lipgloss.Left, body, actions)
+	search := lipgloss.NewStyle().
+		Foreground(theme.Foreground).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(theme.SurfaceHigh).
+		Padding(0, 1).
+		Width(contentWidth).
+		Render("🔍 " + p.filter + "▏")
+
+	// Title, hint, scopes, selections, search, table heading, details and
+	// the frame take about 20 lines.
+	maxRows := max(4, height-20)
+	sections := []string{
+		headerTitle,
+		headerHint,
+		"",
+		p.renderScopes(),
+		p.renderSelections(contentWidth),
+		search,
+		p.renderTable(contentWidth, maxRows),
+		p.renderDetails(contentWidth),
+	}
 
 	panel := lipgloss.NewStyle().
 		Width(panelWidth).
 		BorderStyle(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color(theme.Border.Hex())).
-		Padding(2, paddingX).
-		Render(lipgloss.JoinVertical(lipgloss.Left, header, body))
+		BorderForeground(theme.Border).
+		Padding(1, paddingX).
+		Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
 
 	return panel, true
 }
 
-func (p *ModelPicker) renderOptions(width int) []string {
+// renderScopes shows what Enter applies the model to.
+func (p *ModelPicker) renderScopes() string {
 	theme := styles.CurrentTheme()
-	if len(p.options) == 0 {
-		empty := lipgloss.NewStyle().
-			Width(width).
-			Foreground(lipgloss.Color(theme.Muted.Hex())).
-			Padding(1, 2).
-			Align(lipgloss.Center, lipgloss.Center).
-			Render("No models available")
-		return []string{empty}
-	}
-
-	rows := make([]string, 0, len(p.options)*2)
-	for i, opt := range p.options {
-		rows = append(rows, p.renderOption(opt, i == p.selected, width))
-		if i < len(p.options)-1 {
-			divider := lipgloss.NewStyle().
-				Foreground(lipgloss.Color(theme.Border.Hex())).
-				Width(width).
-				Render(strings.Repeat("─", max(12, width-2)))
-			rows = append(rows, divider)
+	tab := func(label string, active bool) string {
+		style := lipgloss.NewStyle().Padding(0, 1)
+		if active {
+			return style.Foreground(theme.Background).Background(theme.Primary).Bold(true).Render(label)
 		}
+		return style.Foreground(theme.Muted).Render(label)
 	}
-	return rows
+	return lipgloss.JoinHorizontal(lipgloss.Left,
+		tab("● This chat", p.scope == ModelScopeChat),
+		" ",
+		tab("◆ Default for new chats", p.scope == ModelScopeDefault),
+	)
 }
 
-func (p *ModelPicker) renderActions(width int) string {
-	if width <= 0 {
-		return ""
-	}
+// renderSelections names the active conversation's model and the default.
+func (p *ModelPicker) renderSelections(width int) string {
 	theme := styles.CurrentTheme()
-	buttonStyle := lipgloss.NewStyle().
-		Padding(0, 2).
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color(theme.SurfaceHigh.Hex())).
-		Foreground(lipgloss.Color(theme.Foreground.Hex()))
-
-	primary := buttonStyle.Copy().
-		BorderForeground(lipgloss.Color(theme.Primary.Hex())).
-		Foreground(lipgloss.Color(theme.Primary.Hex())).
-		Render(modelPickerActionApply)
-	secondary := buttonStyle.Render(modelPickerActionApplyDefault)
-
-	row := lipgloss.JoinHorizontal(lipgloss.Left, primary, "  ", secondary)
-	return lipgloss.NewStyle().
-		Width(width).
-		PaddingTop(1).
-		Render(row)
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+	value := lipgloss.NewStyle().Foreground(theme.Foreground)
+	name := func(model *stores.ModelOption) string {
+		if model == nil || model.Name == "" {
+			return "none"
+		}
+		return model.Name
+	}
+	line := muted.Render("This chat: ") + value.Render(name(p.current)) +
+		muted.Render("   Default: ") + value.Render(name(p.settings.Settings().DefaultModel))
+	return lipgloss.NewStyle().Width(width).MaxWidth(width).PaddingTop(1).Render(line)
 }
 
-func (p *ModelPicker) renderOption(opt stores.ModelOption, selected bool, width int) string {
+// renderTable draws the rows around the selection, at most maxRows lines
+// including section headings.
+func (p *ModelPicker) renderTable(width, maxRows int) string {
 	theme := styles.CurrentTheme()
-	nameStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#d7ffae")).
-		Bold(true)
-	// providerStyle := lipgloss.NewStyle().
-	// 	Foreground(lipgloss.Color("#d7ffae")).
-	// 	Bold(true)
+	entries := p.entries()
+	if len(entries) == 0 {
+		message := "No models available"
+		if p.filter != "" {
+			message = fmt.Sprintf("No models match %q", p.filter)
+		}
+		return lipgloss.NewStyle().
+			Width(width).
+			Foreground(theme.Muted).
+			Padding(1, 2).
+			Render(message)
+	}
+
+	settings := p.settings.Settings()
+	nameWidth := max(12, width-4-modelBadgesWidth-modelContextWidth)
+	heading := lipgloss.NewStyle().Foreground(theme.Muted).Bold(true).Render(
+		"    " + padRight("Model", nameWidth) + padRight("Capabilities", modelBadgesWidth) + padLeft("Context", modelContextWidth))
+
+	var (
+		lines        []string
+		selectedLine int
+		section      string
+	)
+	for i, entry := range entries {
+		if entry.section != section {
+			section = entry.section
+			lines = append(lines, lipgloss.NewStyle().Foreground(theme.Secondary).Bold(true).Render(section))
+		}
+		if i == p.selected {
+			selectedLine = len(lines)
+		}
+		lines = append(lines, p.renderRow(entry.option, i == p.selected, settings, nameWidth))
+	}
 
-	metaStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color(theme.Muted.Hex()))
+	start := 0
+	if len(lines) > maxRows {
+		start = clamp(selectedLine-maxRows/2, 0, len(lines)-maxRows)
+		lines = lines[start : start+maxRows]
+	}
+	return lipgloss.NewStyle().Width(width).PaddingTop(1).Render(
+		lipgloss.JoinVertical(lipgloss.Left, append([]string{heading}, lines...)...))
+}
 
-	head := nameStyle.Render(opt.Name) + nameStyle.Render(opt.Provider)
+func (p *ModelPicker) renderRow(opt stores.ModelOption, selected bool, settings stores.ApplicationSettings, nameWidth int) string {
+	theme := styles.CurrentTheme()
 
-	contextLine := ""
-	if opt.Context != "" {
-		contextLine = metaStyle.Render("Context: " + opt.Context)
+	indicator := "  "
+	if selected {
+		indicator = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+	}
+	star := "  "
+	if settings.IsFavoriteModel(opt) {
+		star = lipgloss.NewStyle().Foreground(theme.Warning).Render("★ ")
 	}
 
-	descParts := []string{}
-	if strings.TrimSpace(opt.Description) != "" {
-		descParts = append(descParts, opt.Description)
+	var marks []string
+	if p.current != nil && stores.SameModel(*p.current, opt) {
+		marks = append(marks, "●")
 	}
-	if len(opt.Capabilities) > 0 {
-		descParts = append(descParts, "Capabilities: "+strings.Join(opt.Capabilities, ", "))
+	if settings.DefaultModel != nil && stores.SameModel(*settings.DefaultModel, opt) {
+		marks = append(marks, "◆")
 	}
-
-	cardWidth := width - 4
-	if cardWidth < 28 {
-		cardWidth = width - 2
+	name := opt.Name
+	if p.filter != "" && opt.Provider != "" {
+		name += " · " + opt.Provider
+	}
+	if len(marks) > 0 {
+		name += " " + strings.Join(marks, "")
 	}
-	wrapWidth := cardWidth - 6
-	if wrapWidth < 20 {
-		wrapWidth = max(20, width-10)
+	nameStyle := lipgloss.NewStyle().Foreground(theme.Foreground)
+	if selected {
+		nameStyle = nameStyle.Bold(true).Foreground(theme.Primary)
 	}
-	descText := metaStyle.Copy().Width(wrapWidth).Render(strings.Join(descParts, "\n"))
+	nameCell := nameStyle.Render(padRight(truncate(name, nameWidth-1), nameWidth))
 
 	badges := p.renderCapabilityBadges(opt.Capabilities)
+	badgesCell := lipgloss.NewStyle().Width(modelBadgesWidth).MaxWidth(modelBadgesWidth).Render(badges)
+
+	contextCell := lipgloss.NewStyle().Foreground(theme.Muted).Render(padLeft(formatContextWindow(opt.Context), modelContextWidth))
+
+	return indicator + star + nameCell + badgesCell + contextCell
+}
 
-	sections := []string{head}
-	if contextLine != "" {
-		sections = append(sections, contextLine)
+// renderDetails describes the selected model below the table.
+func (p *ModelPicker) renderDetails(width int) string {
+	theme := styles.CurrentTheme()
+	entries := p.entries()
+	if len(entries) == 0 {
+		return ""
 	}
-	if strings.TrimSpace(descText) != "" {
-		sections = append(sections, descText)
+	opt := entries[p.selected].option
+	var parts []string
+	if desc := strings.TrimSpace(opt.Description); desc != "" {
+		parts = append(parts, desc)
 	}
-	if strings.TrimSpace(badges) != "" {
-		sections = append(sections, badges)
+	if len(opt.Capabilities) > 0 {
+		parts = append(parts, "Capabilities: "+strings.Join(opt.Capabilities, ", "))
 	}
-
-	cardStyle := lipgloss.NewStyle().
-		Width(cardWidth).
-		Padding(1, 2)
-
-	indicator := lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Muted.Hex())).Render("  ")
-	container := lipgloss.NewStyle().Width(width).Padding(1, 1)
-
-	if selected {
-		indicator = lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Accent.Hex())).Render("➤ ")
-		cardStyle = cardStyle.
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color(theme.Primary.Hex())).
-			Foreground(lipgloss.Color(theme.Foreground.Hex()))
-		// Removed background from cardStyle for selected state
-		// Removed background from container for selected state
-	} else {
-		cardStyle = cardStyle.
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color(theme.SurfaceHigh.Hex()))
-		// Removed background from cardStyle for unselected state
-		// Removed background from container for unselected state
+	if len(parts) == 0 {
+		parts = append(parts, opt.Provider)
 	}
+	return lipgloss.NewStyle().
+		Width(width).
+		MaxHeight(3).
+		PaddingTop(1).
+		Foreground(theme.Muted).
+		Render(strings.Join(parts, " • "))
+}
 
-	cardContent := lipgloss.JoinVertical(lipgloss.Left, sections...)
-	card := cardStyle.Render(cardContent)
-	row := lipgloss.JoinHorizontal(lipgloss.Top, indicator, card)
-	return container.Render(row)
+// capabilityBadges maps the capability names servers report to the badges
+// shown in the table.
+var capabilityBadges = map[string]string{
+	"vision":           "vision",
+	"image":            "vision",
+	"images":           "vision",
+	"multimodal":       "vision",
+	"tools":            "tools",
+	"tool_use":         "tools",
+	"tool-use":         "tools",
+	"function_calling": "tools",
+	"functions":        "tools",
+	"reasoning":        "reasoning",
+	"thinking":         "reasoning",
 }
 
 func (p *ModelPicker) renderCapabilityBadges(capabilities []string) string {
@@ -349,16 +541,65 @@ func (p *ModelPicker) renderCapabilityBadges(capabilities []string) string {
 		return ""
 	}
 	theme := styles.CurrentTheme()
-	badgeStyle := lipgloss.NewStyle().
-		// Background(lipgloss.Color(theme.SurfaceHighest.Hex())).
-		Foreground(lipgloss.Color(theme.Muted.Hex())).
-		Padding(0, 1).
-		MarginRight(1)
-	badges := make([]string, 0, len(capabilities))
-	for _, cap := range capabilities {
-		badges = append(badges, badgeStyle.Render(strings.ToUpper(cap)))
+	colors := map[string]lipgloss.Style{
+		"vision":    lipgloss.NewStyle().Foreground(theme.Info),
+		"tools":     lipgloss.NewStyle().Foreground(theme.Success),
+		"reasoning": lipgloss.NewStyle().Foreground(theme.Accent),
+	}
+	seen := make(map[string]bool)
+	var badges []string
+	for _, capability := range capabilities {
+		badge, ok := capabilityBadges[strings.ToLower(strings.TrimSpace(capability))]
+		if !ok || seen[badge] {
+			continue
+		}
+		seen[badge] = true
+		badges = append(badges, colors[badge].Render(badge))
+	}
+	return strings.Join(badges, " ")
+}
+
+// formatContextWindow shortens token counts, e.g. "128000" to "128K", and
+// passes other descriptions through.
+func formatContextWindow(context string) string {
+	context = strings.TrimSpace(context)
+	n, err := strconv.Atoi(strings.ReplaceAll(context, ",", ""))
+	switch {
+	case err != nil:
+		return context
+	case n >= 1_000_000 && n%1_000_000 == 0:
+		return fmt.Sprintf("%dM", n/1_000_000)
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1000:
+		return fmt.Sprintf("%dK", n/1000)
+	}
+	return context
+}
+
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+func padRight(s string, width int) string {
+	if gap := width - lipgloss.Width(s); gap > 0 {
+		return s + strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+func padLeft(s string, width int) string {
+	if gap := width - lipgloss.Width(s); gap > 0 {
+		return strings.Repeat(" ", gap) + s
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Left, badges...)
+	return s
 }
 
 func clamp(value, minValue, maxValue int) int {
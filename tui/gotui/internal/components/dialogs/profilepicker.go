@@ -0,0 +1,197 @@
+package dialogs
+
+import (
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/styles"
+)
+
+// ServerProfileOption describes a selectable server connection profile.
+type ServerProfileOption struct {
+	Name     string
+	Endpoint string
+	Secure   bool
+	Auth     bool
+}
+
+// ProfilePicker renders an overlay for switching between server profiles.
+type ProfilePicker struct {
+	options  []ServerProfileOption
+	active   string
+	visible  bool
+	selected int
+}
+
+// NewProfilePicker constructs a new profile picker with the provided options.
+func NewProfilePicker(options []ServerProfileOption) *ProfilePicker {
+	return &ProfilePicker{options: options}
+}
+
+// SetOptions replaces the selectable profiles and records the active one.
+func (p *ProfilePicker) SetOptions(options []ServerProfileOption, active string) {
+	p.options = options
+	p.active = active
+	if p.selected >= len(p.options) {
+		p.selected = 0
+	}
+}
+
+// Open makes the picker visible with the active profile selected.
+func (p *ProfilePicker) Open() {
+	if idx := p.indexOf(p.active); idx >= 0 {
+		p.selected = idx
+	} else if p.selected >= len(p.options) {
+		p.selected = 0
+	}
+	p.visible = true
+}
+
+// Close hides the picker.
+func (p *ProfilePicker) Close() {
+	p.visible = false
+}
+
+// IsVisible reports whether the picker overlay is currently shown.
+func (p *ProfilePicker) IsVisible() bool {
+	return p.visible
+}
+
+// HandleKey processes keyboard navigation and selection.
+func (p *ProfilePicker) HandleKey(msg tea.KeyPressMsg) (handled bool, option ServerProfileOption, ok bool) {
+	if !p.visible {
+		return false, ServerProfileOption{}, false
+	}
+
+	switch msg.String() {
+	case "esc":
+		p.Close()
+		return true, ServerProfileOption{}, false
+	case "enter", "tab":
+		if len(p.options) == 0 {
+			p.Close()
+			return true, ServerProfileOption{}, false
+		}
+		choice := p.options[p.selected]
+		p.Close()
+		return true, choice, true
+	case "down", "ctrl+n":
+		p.move(1)
+		return true, ServerProfileOption{}, false
+	case "up", "ctrl+p", "shift+tab":
+		p.move(-1)
+		return true, ServerProfileOption{}, false
+	}
+
+	return false, ServerProfileOption{}, false
+}
+
+func (p *ProfilePicker) move(delta int) {
+	if len(p.options) == 0 {
+		return
+	}
+	limit := len(p.options)
+	p.selected = (p.selected + delta + limit) % limit
+}
+
+func (p *ProfilePicker) indexOf(name string) int {
+	for i, opt := range p.options {
+		if strings.EqualFold(opt.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// View renders the picker overlay as a dialog.
+func (p *ProfilePicker) View(width, height int) string {
+	panel, ok := p.dialogPanel(width)
+	if !ok || height <= 0 {
+		return ""
+	}
+	return Wrap(panel, width, height)
+}
+
+// Layer renders the picker as an overlay layer.
+func (p *ProfilePicker) Layer(width, height int) *lipgloss.Layer {
+	panel, ok := p.dialogPanel(width)
+	if !ok || height <= 0 {
+		return nil
+	}
+	return WrapLayer(panel, width, height)
+}
+
+func (p *ProfilePicker) dialogPanel(width int) (string, bool) {
+	if !p.visible || width <= 0 {
+		return "", false
+	}
+
+	theme := styles.CurrentTheme()
+	panelWidth := clamp(width-10, 44, int(math.Min(72, float64(width-4))))
+	if panelWidth <= 0 {
+		panelWidth = width
+	}
+	contentWidth := panelWidth - 6
+
+	headerTitle := lipgloss.NewStyle().
+		Foreground(theme.Primary).
+		Bold(true).
+		Render("Server Profiles")
+	headerHint := lipgloss.NewStyle().
+		Foreground(theme.Muted).
+		Render("↑ ↓ to navigate • Enter to connect • Esc to cancel")
+	header := lipgloss.JoinVertical(lipgloss.Left, headerTitle, headerHint, "")
+
+	var rows []string
+	if len(p.options) == 0 {
+		rows = append(rows, lipgloss.NewStyle().
+			Foreground(theme.Muted).
+			Padding(1, 2).
+			Render("No profiles configured"))
+	}
+	for i, opt := range p.options {
+		rows = append(rows, p.renderOption(opt, i == p.selected, contentWidth))
+	}
+
+	panel := lipgloss.NewStyle().
+		Width(panelWidth).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, header, lipgloss.JoinVertical(lipgloss.Left, rows...)))
+
+	return panel, true
+}
+
+func (p *ProfilePicker) renderOption(opt ServerProfileOption, selected bool, width int) string {
+	theme := styles.CurrentTheme()
+
+	name := lipgloss.NewStyle().Foreground(theme.Foreground).Bold(true).Render(opt.Name)
+	var badges []string
+	if strings.EqualFold(opt.Name, p.active) {
+		badges = append(badges, lipgloss.NewStyle().Foreground(theme.Success).Bold(true).Render("ACTIVE"))
+	}
+	if opt.Secure {
+		badges = append(badges, lipgloss.NewStyle().Foreground(theme.Info).Render("🔒 TLS"))
+	}
+	if opt.Auth {
+		badges = append(badges, lipgloss.NewStyle().Foreground(theme.Warning).Render("🔑 auth"))
+	}
+	firstLine := name
+	if len(badges) > 0 {
+		firstLine = lipgloss.JoinHorizontal(lipgloss.Left, name, "  ", strings.Join(badges, "  "))
+	}
+	endpoint := lipgloss.NewStyle().Foreground(theme.Muted).Render(opt.Endpoint)
+
+	indicator := "  "
+	rowStyle := lipgloss.NewStyle().Width(width)
+	if selected {
+		indicator = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+		rowStyle = rowStyle.Foreground(theme.Foreground)
+	}
+	body := lipgloss.JoinVertical(lipgloss.Left, firstLine, endpoint)
+	return rowStyle.Render(lipgloss.JoinHorizontal(lipgloss.Left, indicator, body))
+}
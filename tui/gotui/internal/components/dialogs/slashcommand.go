@@ -5,4 +5,6 @@ type SlashCommand struct {
 	Name        string
 	Description string
 	Usage       string
+	// Shortcut is the effective key binding that triggers the same action, if any.
+	Shortcut string
 }
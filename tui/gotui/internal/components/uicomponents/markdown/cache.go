@@ -0,0 +1,51 @@
+package markdown
+
+import (
+	"sync"
+
+	"gotui/internal/styles"
+)
+
+// maxCachedRenderers bounds the cache so repeated resizes do not grow it forever.
+const maxCachedRenderers = 16
+
+type cacheKey struct {
+	theme string
+	width int
+}
+
+var (
+	cacheMu   sync.Mutex
+	renderers = make(map[cacheKey]*Renderer)
+)
+
+// ForWidth returns a shared renderer for the given width and theme, creating it
+// on first use. Building a glamour renderer is expensive, so templates should
+// prefer this over New when rendering many messages.
+func ForWidth(width int, theme styles.Theme) (*Renderer, error) {
+	key := cacheKey{theme: themeKey(theme), width: width}
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	if renderer, ok := renderers[key]; ok {
+		return renderer, nil
+	}
+
+	renderer, err := NewWithTheme(width, theme)
+	if err != nil {
+		return nil, err
+	}
+	if len(renderers) >= maxCachedRenderers {
+		renderers = make(map[cacheKey]*Renderer)
+	}
+	renderers[key] = renderer
+	return renderer, nil
+}
+
+// ResetCache drops all cached renderers, e.g. after the active theme changes.
+func ResetCache() {
+	cacheMu.Lock()
+	renderers = make(map[cacheKey]*Renderer)
+	cacheMu.Unlock()
+}
@@ -3,6 +3,9 @@ package markdown
 import (
 	"fmt"
 	"strings"
+	"sync"
+
+	"gotui/internal/styles"
 
 	"github.com/charmbracelet/glamour"
 )
@@ -11,14 +14,18 @@ import (
 type Renderer struct {
 	renderer *glamour.TermRenderer
 	width    int
+	theme    styles.Theme
+	mu       sync.Mutex
 }
 
-// New creates a new markdown renderer
+// New creates a new markdown renderer styled with the current theme
 func New(width int) (*Renderer, error) {
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(width),
-	)
+	return NewWithTheme(width, styles.CurrentTheme())
+}
+
+// NewWithTheme creates a new markdown renderer styled with the given theme
+func NewWithTheme(width int, theme styles.Theme) (*Renderer, error) {
+	renderer, err := newTermRenderer(width, theme)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create glamour renderer: %w", err)
 	}
@@ -26,11 +33,25 @@ func New(width int) (*Renderer, error) {
 	return &Renderer{
 		renderer: renderer,
 		width:    width,
+		theme:    theme,
 	}, nil
 }
 
+func newTermRenderer(width int, theme styles.Theme) (*glamour.TermRenderer, error) {
+	if width < 1 {
+		width = 1
+	}
+	return glamour.NewTermRenderer(
+		glamour.WithStyles(StyleConfigFromTheme(theme)),
+		glamour.WithWordWrap(width),
+	)
+}
+
 // SetWidth updates the renderer width
 func (r *Renderer) SetWidth(width int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if width == r.width {
 		return nil
 	}
@@ -38,10 +59,7 @@ func (r *Renderer) SetWidth(width int) error {
 	r.width = width
 
 	// Recreate renderer with new width
-	renderer, err := glamour.NewTermRenderer(
-		glamour.WithAutoStyle(),
-		glamour.WithWordWrap(width),
-	)
+	renderer, err := newTermRenderer(width, r.theme)
 	if err != nil {
 		return fmt.Errorf("failed to recreate glamour renderer: %w", err)
 	}
@@ -56,7 +74,9 @@ func (r *Renderer) Render(markdown string) (string, error) {
 		return "", nil
 	}
 
+	r.mu.Lock()
 	rendered, err := r.renderer.Render(markdown)
+	r.mu.Unlock()
 	if err != nil {
 		return "", fmt.Errorf("failed to render markdown: %w", err)
 	}
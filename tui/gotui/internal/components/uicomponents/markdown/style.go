@@ -0,0 +1,191 @@
+package markdown
+
+import (
+	"gotui/internal/styles"
+
+	"github.com/charmbracelet/glamour/ansi"
+)
+
+// StyleConfigFromTheme builds a glamour style that follows the application theme
+// so markdown output matches the surrounding chrome instead of glamour's defaults.
+func StyleConfigFromTheme(theme styles.Theme) ansi.StyleConfig {
+	foreground := theme.Foreground.Hex()
+	primary := theme.Primary.Hex()
+	secondary := theme.Secondary.Hex()
+	accent := theme.Accent.Hex()
+	muted := theme.Muted.Hex()
+	border := theme.Border.Hex()
+	info := theme.Info.Hex()
+	success := theme.Success.Hex()
+	warning := theme.Warning.Hex()
+	errorColor := theme.Error.Hex()
+	surface := theme.SurfaceHigh.Hex()
+
+	return ansi.StyleConfig{
+		Document: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color: stringPtr(foreground),
+			},
+		},
+		BlockQuote: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Color:  stringPtr(muted),
+				Italic: boolPtr(true),
+			},
+			Indent:      uintPtr(1),
+			IndentToken: stringPtr("│ "),
+		},
+		Paragraph: ansi.StyleBlock{},
+		List: ansi.StyleList{
+			LevelIndent: 2,
+		},
+		Heading: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				BlockSuffix: "\n",
+				Color:       stringPtr(primary),
+				Bold:        boolPtr(true),
+			},
+		},
+		H1: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "# ",
+				Color:  stringPtr(accent),
+			},
+		},
+		H2: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "## ",
+			},
+		},
+		H3: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "### ",
+				Color:  stringPtr(secondary),
+			},
+		},
+		H4: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "#### ",
+				Color:  stringPtr(secondary),
+			},
+		},
+		H5: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "##### ",
+				Color:  stringPtr(muted),
+			},
+		},
+		H6: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix: "###### ",
+				Color:  stringPtr(muted),
+				Bold:   boolPtr(false),
+			},
+		},
+		Strikethrough: ansi.StylePrimitive{
+			CrossedOut: boolPtr(true),
+		},
+		Emph: ansi.StylePrimitive{
+			Italic: boolPtr(true),
+		},
+		Strong: ansi.StylePrimitive{
+			Bold: boolPtr(true),
+		},
+		HorizontalRule: ansi.StylePrimitive{
+			Color:  stringPtr(border),
+			Format: "\n────────\n",
+		},
+		Item: ansi.StylePrimitive{
+			BlockPrefix: "• ",
+		},
+		Enumeration: ansi.StylePrimitive{
+			BlockPrefix: ". ",
+			Color:       stringPtr(secondary),
+		},
+		Task: ansi.StyleTask{
+			Ticked:   "[✓] ",
+			Unticked: "[ ] ",
+		},
+		Link: ansi.StylePrimitive{
+			Color:     stringPtr(info),
+			Underline: boolPtr(true),
+		},
+		LinkText: ansi.StylePrimitive{
+			Color: stringPtr(info),
+			Bold:  boolPtr(true),
+		},
+		Image: ansi.StylePrimitive{
+			Color:     stringPtr(accent),
+			Underline: boolPtr(true),
+		},
+		ImageText: ansi.StylePrimitive{
+			Color:  stringPtr(muted),
+			Format: "Image: {{.text}} →",
+		},
+		Code: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{
+				Prefix:          " ",
+				Suffix:          " ",
+				Color:           stringPtr(warning),
+				BackgroundColor: stringPtr(surface),
+			},
+		},
+		CodeBlock: ansi.StyleCodeBlock{
+			StyleBlock: ansi.StyleBlock{
+				StylePrimitive: ansi.StylePrimitive{
+					Color: stringPtr(foreground),
+				},
+				Margin: uintPtr(1),
+			},
+			Chroma: &ansi.Chroma{
+				Text:             ansi.StylePrimitive{Color: stringPtr(foreground)},
+				Error:            ansi.StylePrimitive{Color: stringPtr(errorColor)},
+				Comment:          ansi.StylePrimitive{Color: stringPtr(muted), Italic: boolPtr(true)},
+				CommentPreproc:   ansi.StylePrimitive{Color: stringPtr(muted)},
+				Keyword:          ansi.StylePrimitive{Color: stringPtr(primary), Bold: boolPtr(true)},
+				KeywordReserved:  ansi.StylePrimitive{Color: stringPtr(primary)},
+				KeywordNamespace: ansi.StylePrimitive{Color: stringPtr(primary)},
+				KeywordType:      ansi.StylePrimitive{Color: stringPtr(secondary)},
+				Operator:         ansi.StylePrimitive{Color: stringPtr(accent)},
+				Punctuation:      ansi.StylePrimitive{Color: stringPtr(muted)},
+				NameBuiltin:      ansi.StylePrimitive{Color: stringPtr(secondary)},
+				NameTag:          ansi.StylePrimitive{Color: stringPtr(primary)},
+				NameAttribute:    ansi.StylePrimitive{Color: stringPtr(info)},
+				NameClass:        ansi.StylePrimitive{Color: stringPtr(secondary), Bold: boolPtr(true)},
+				NameConstant:     ansi.StylePrimitive{Color: stringPtr(accent)},
+				NameDecorator:    ansi.StylePrimitive{Color: stringPtr(warning)},
+				NameFunction:     ansi.StylePrimitive{Color: stringPtr(info)},
+				LiteralNumber:    ansi.StylePrimitive{Color: stringPtr(accent)},
+				LiteralString:    ansi.StylePrimitive{Color: stringPtr(success)},
+				GenericDeleted:   ansi.StylePrimitive{Color: stringPtr(errorColor)},
+				GenericEmph:      ansi.StylePrimitive{Italic: boolPtr(true)},
+				GenericInserted:  ansi.StylePrimitive{Color: stringPtr(success)},
+				GenericStrong:    ansi.StylePrimitive{Bold: boolPtr(true)},
+				Background:       ansi.StylePrimitive{BackgroundColor: stringPtr(surface)},
+			},
+		},
+		Table: ansi.StyleTable{
+			CenterSeparator: stringPtr("┼"),
+			ColumnSeparator: stringPtr("│"),
+			RowSeparator:    stringPtr("─"),
+		},
+		DefinitionDescription: ansi.StylePrimitive{
+			BlockPrefix: "\n🠶 ",
+		},
+	}
+}
+
+// themeKey identifies a theme by its colors so cached renderers are invalidated
+// whenever any color changes, including themes set without a preset name.
+func themeKey(theme styles.Theme) string {
+	return theme.Primary.Hex() + theme.Secondary.Hex() + theme.Accent.Hex() +
+		theme.Foreground.Hex() + theme.Muted.Hex() + theme.Border.Hex() +
+		theme.Info.Hex() + theme.Success.Hex() + theme.Warning.Hex() +
+		theme.Error.Hex() + theme.SurfaceHigh.Hex()
+}
+
+func stringPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func uintPtr(u uint) *uint { return &u }
@@ -34,6 +34,9 @@ func (h *HelpBar) SetSize(width, height int) {
 	h.height = height
 }
 
+// SetKeyMap replaces the bindings shown in the bar with the effective key map.
+func (h *HelpBar) SetKeyMap(km keybindings.KeyMap) { h.keyMap = km }
+
 // SetVisible adjusts visibility.
 func (h *HelpBar) SetVisible(visible bool) { h.visible = visible }
 
@@ -107,7 +110,7 @@ func (h *HelpBar) View() string {
 	helpContent := lipgloss.JoinHorizontal(lipgloss.Center, interleaveSeparator(helpItems, lipgloss.NewStyle().Foreground(theme.Border).Render(" • "))...)
 
 	if strings.TrimSpace(stripANSI(helpContent)) == "" {
-		helpContent = defaultHelpContent(theme)
+		helpContent = h.defaultHelpContent(theme)
 	}
 
 	width := h.width
@@ -120,7 +123,7 @@ func (h *HelpBar) View() string {
 	}
 
 	if lipgloss.Width(helpContent) > width-4 {
-		helpContent = defaultHelpContent(theme)
+		helpContent = h.defaultHelpContent(theme)
 	}
 
 	return lipgloss.NewStyle().
@@ -169,15 +172,24 @@ func stripANSI(s string) string {
 	return ansiEscapeCodes.ReplaceAllString(s, "")
 }
 
-func defaultHelpContent(theme styles.Theme) string {
-	entries := []struct {
+func (h *HelpBar) defaultHelpContent(theme styles.Theme) string {
+	type entry struct {
 		key  string
 		desc string
+	}
+	var entries []entry
+	for _, candidate := range []struct {
+		binding key.Binding
+		desc    string
 	}{
-		{"enter", "send"},
-		{"tab", "focus"},
-		{"ctrl+c", "quit"},
-		{"?", "help"},
+		{h.keyMap.Submit, "send"},
+		{h.keyMap.FocusChat, "focus"},
+		{h.keyMap.Quit, "quit"},
+		{h.keyMap.Help, "help"},
+	} {
+		if keys := candidate.binding.Keys(); candidate.binding.Enabled() && len(keys) > 0 {
+			entries = append(entries, entry{keys[0], candidate.desc})
+		}
 	}
 
 	var parts []string
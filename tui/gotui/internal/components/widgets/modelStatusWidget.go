@@ -7,6 +7,7 @@ import (
 	"gotui/internal/components/chatcomponents"
 	"gotui/internal/stores"
 	"gotui/internal/styles"
+	"gotui/internal/usage"
 
 	"github.com/charmbracelet/lipgloss/v2"
 )
@@ -156,6 +157,10 @@ func (w *ModelStatusWidget) View() string {
 		segments = append(segments, label)
 	}
 
+	if label := usageLabel(); label != "" {
+		segments = append(segments, label)
+	}
+
 	if host := strings.TrimSpace(details.Host); host != "" {
 		label := fmt.Sprintf("Server: %s:%d", host, details.Port)
 		if protocol := strings.TrimSpace(details.Protocol); protocol != "" {
@@ -185,3 +190,26 @@ func (w *ModelStatusWidget) View() string {
 		Padding(0, 1).
 		Render(content)
 }
+
+// usageLabel summarizes the active conversation's token usage and the session
+// cost, flagged once either nears its budget.
+func usageLabel() string {
+	usageStore := stores.SharedUsageStore()
+	conversation := usageStore.Conversation(stores.SharedConversationStore().ActiveID())
+	session := usageStore.Session()
+	if conversation.IsZero() && session.IsZero() {
+		return ""
+	}
+	label := fmt.Sprintf("Usage: %s tokens", usage.FormatTokens(conversation.Total()))
+	if cost := usage.DescribeCost(conversation); cost != "" {
+		label += " · " + cost
+	}
+	if cost := usage.DescribeCost(session); cost != "" {
+		label += fmt.Sprintf(" (session %s)", cost)
+	}
+	budgets := usageStore.Budgets()
+	if budgets.Conversation.Fraction(conversation) >= usage.WarnFraction || budgets.Session.Fraction(session) >= usage.WarnFraction {
+		return "⚠ " + label
+	}
+	return label
+}
@@ -0,0 +1,665 @@
+// Package config resolves gotui settings from layered sources. In increasing
+// order of precedence: built-in defaults, the user config file, the project
+// config file, environment variables and command-line flags. Server settings
+// of the selected profile rank above the files and below the environment.
+//
+// The project config file comes with the checkout, so it may not set where
+// gotui connects, how it authenticates or where it logs; see
+// projectIgnoredKeys.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gotui/internal/configfile"
+	"gotui/internal/imagepreview"
+	"gotui/internal/keybindings"
+	"gotui/internal/notify"
+	"gotui/internal/transport"
+	"gotui/internal/usage"
+)
+
+// Layout modes accepted by the layout setting.
+const (
+	LayoutPanel  = "panel"
+	LayoutWindow = "window"
+)
+
+// ThemeAuto selects a theme from the detected terminal background.
+const ThemeAuto = "auto"
+
+// Server describes how to reach the agent server.
+type Server struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	TLS      TLS    `json:"tls,omitzero"`
+	// Auth selects how the token is sent: "bearer" (default) or "api-key".
+	Auth  string `json:"auth,omitempty"`
+	Token string `json:"token,omitempty"`
+	// TokenFile holds the token instead of the config file; it must be private
+	// to the user.
+	TokenFile string `json:"token_file,omitempty"`
+}
+
+// TLS configures certificate verification for wss/https connections.
+type TLS struct {
+	CAFile             string `json:"ca_file,omitempty"`
+	CertFile           string `json:"cert_file,omitempty"`
+	KeyFile            string `json:"key_file,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// ModelRef identifies a model by display name and provider.
+type ModelRef struct {
+	Name     string `json:"name,omitempty"`
+	Provider string `json:"provider,omitempty"`
+}
+
+// AgentRef identifies an agent.
+type AgentRef struct {
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Defaults holds the model and agent selected for new conversations, and the
+// models pinned in the model picker.
+type Defaults struct {
+	Model          *ModelRef  `json:"model,omitempty"`
+	Agent          *AgentRef  `json:"agent,omitempty"`
+	FavoriteModels []ModelRef `json:"favorite_models,omitempty"`
+	RecentModels   []ModelRef `json:"recent_models,omitempty"`
+}
+
+// Log configures the debug log file.
+type Log struct {
+	Path     string `json:"path,omitempty"`
+	Truncate *bool  `json:"truncate,omitempty"`
+	// Level is the minimum level written: debug, info, warn or error.
+	Level string `json:"level,omitempty"`
+	// MaxSizeMB rotates the file once it grows past this size; 0 disables
+	// rotation.
+	MaxSizeMB *int `json:"max_size_mb,omitempty"`
+	// MaxBackups is the number of rotated files kept next to Path.
+	MaxBackups *int `json:"max_backups,omitempty"`
+	// RedactBodies hides message contents, logging only their size.
+	RedactBodies *bool `json:"redact_bodies,omitempty"`
+}
+
+// Log levels accepted by Log.Level.
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// Notifications configures alerts raised when the agent needs attention.
+type Notifications struct {
+	// Alert is how the terminal is alerted: auto, bell, osc9, osc777 or off.
+	Alert string `json:"alert,omitempty"`
+	// OnlyUnfocused suppresses alerts while the terminal has focus.
+	OnlyUnfocused *bool `json:"only_unfocused,omitempty"`
+	// Events opts event types (run_complete, approval, error, server, budget)
+	// in or out of alerts; unlisted types keep their default.
+	Events map[string]bool `json:"events,omitempty"`
+}
+
+// Alerts reports whether event raises an alert.
+func (n Notifications) Alerts(event string) bool {
+	if enabled, ok := n.Events[event]; ok {
+		return enabled
+	}
+	return notify.DefaultEnabled(event)
+}
+
+// Usage configures token and cost tracking.
+type Usage struct {
+	// Prices extends or overrides the built-in price table. Keys are model
+	// name prefixes; prices are US dollars per million tokens.
+	Prices usage.Prices `json:"prices,omitempty"`
+	// BuiltinPrices set to false drops the built-in estimated prices, so
+	// only server-reported costs and Prices are used.
+	BuiltinPrices *bool `json:"builtin_prices,omitempty"`
+	// Budgets are soft limits warned about at 80% and 100%.
+	Budgets UsageBudgets `json:"budgets,omitzero"`
+}
+
+// PriceTable returns the prices costs are estimated with: the built-in
+// estimates unless turned off, with Prices on top.
+func (u Usage) PriceTable() usage.Prices {
+	if u.BuiltinPrices != nil && !*u.BuiltinPrices {
+		return usage.Prices{}.Merge(u.Prices)
+	}
+	return usage.DefaultPrices.Merge(u.Prices)
+}
+
+// UsageBudgets holds the soft budget of each scope.
+type UsageBudgets struct {
+	Conversation usage.Budget `json:"conversation,omitzero"`
+	Session      usage.Budget `json:"session,omitzero"`
+}
+
+// Config is the merged configuration. The same structure is used for every
+// file layer, so a file only needs to mention the settings it changes.
+type Config struct {
+	Server Server `json:"server,omitzero"`
+	// Profile names the entry in Profiles to connect to at startup.
+	Profile  string            `json:"profile,omitempty"`
+	Profiles map[string]Server `json:"profiles,omitempty"`
+	Defaults Defaults          `json:"defaults,omitzero"`
+	Theme    string            `json:"theme,omitempty"`
+	Layout   string            `json:"layout,omitempty"`
+	Log      Log               `json:"log,omitzero"`
+	// Notifications configures agent alerts.
+	Notifications Notifications `json:"notifications,omitzero"`
+	// Usage configures cost estimates and budgets.
+	Usage Usage `json:"usage,omitzero"`
+	// ImagePreviews is how images are drawn in the chat: auto, kitty,
+	// blocks or off.
+	ImagePreviews string           `json:"image_previews,omitempty"`
+	Keybindings   keybindings.File `json:"keybindings,omitzero"`
+
+	// serverOverrides are the server settings given by environment or flag,
+	// which take precedence over the selected profile as well.
+	serverOverrides Server
+}
+
+// Default returns the built-in configuration.
+func Default() Config {
+	truncate, redact, onlyUnfocused := true, true, true
+	maxSizeMB, maxBackups := 10, 3
+	return Config{
+		Server: Server{
+			Host: "localhost",
+			Port: 3001,
+		},
+		Defaults: Defaults{
+			Model: &ModelRef{Name: "gpt-4.1-mini", Provider: "OpenAI"},
+		},
+		Theme:         ThemeAuto,
+		Layout:        LayoutPanel,
+		ImagePreviews: imagepreview.ProtocolAuto,
+		Log: Log{
+			Path:         filepath.Join(os.TempDir(), "gotui-debug.log"),
+			Truncate:     &truncate,
+			Level:        LogLevelInfo,
+			MaxSizeMB:    &maxSizeMB,
+			MaxBackups:   &maxBackups,
+			RedactBodies: &redact,
+		},
+		Notifications: Notifications{
+			Alert:         notify.MethodAuto,
+			OnlyUnfocused: &onlyUnfocused,
+		},
+	}
+}
+
+// Options controls where Load looks for configuration.
+type Options struct {
+	// ProjectPath locates the project config file; empty skips the project layer.
+	ProjectPath string
+	// UserFile overrides the user config file location.
+	UserFile string
+	// Flags holds parsed command-line flags; nil skips the flag layer.
+	Flags *Flags
+	// Getenv reads environment variables; defaults to os.Getenv.
+	Getenv func(string) string
+}
+
+// Report describes which sources contributed to the configuration.
+type Report struct {
+	UserFile     string
+	ProjectFile  string
+	KeymapFiles  []string
+	Errors       []error
+	FileDefaults Defaults
+	// UserDefaults and ProjectDefaults hold the defaults set by each file alone.
+	UserDefaults    Defaults
+	ProjectDefaults Defaults
+}
+
+// Lines renders the files the configuration was read from as log lines.
+func (r Report) Lines() []string {
+	var lines []string
+	if r.UserFile != "" {
+		lines = append(lines, "⚙️  User config: "+r.UserFile)
+	}
+	if r.ProjectFile != "" {
+		lines = append(lines, "⚙️  Project config: "+r.ProjectFile)
+	}
+	return lines
+}
+
+// ErrorLines renders the configuration errors as log lines.
+func (r Report) ErrorLines() []string {
+	var lines []string
+	for _, err := range r.Errors {
+		lines = append(lines, fmt.Sprintf("❌ Config error: %v", err))
+	}
+	return lines
+}
+
+// UserFilePath returns the existing user config file, or the path a new one
+// should be written to.
+func UserFilePath() string {
+	dir := configfile.UserDir()
+	if dir == "" {
+		return ""
+	}
+	if path := configfile.FindFile(dir, "config"); path != "" {
+		return path
+	}
+	return filepath.Join(dir, "config.toml")
+}
+
+// ProjectFilePath returns the project config file, if one exists.
+func ProjectFilePath(projectPath string) string {
+	return configfile.FindFile(configfile.ProjectDir(projectPath), "config")
+}
+
+// projectIgnoredKeys are the settings a project config file may not change.
+// A cloned repository could otherwise point the connection, and with it the
+// user's credentials or token file, at a host of its choosing, or have the
+// debug log truncate any file the user can write.
+var projectIgnoredKeys = [][]string{
+	{"server"},
+	{"profiles"},
+	{"log", "path"},
+}
+
+// Load resolves the configuration from all layers.
+func Load(opts Options) (Config, Report) {
+	getenv := opts.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	cfg := Default()
+	var report Report
+
+	// The standalone keymap file predates config.toml; its bindings form the base
+	// that [keybindings] sections in config files refine.
+	if path := keybindings.UserKeymapPath(); path != "" {
+		if err := configfile.Decode(path, &cfg.Keybindings); err != nil {
+			report.Errors = append(report.Errors, err)
+		} else {
+			report.KeymapFiles = append(report.KeymapFiles, path)
+		}
+	}
+
+	userFile := opts.UserFile
+	if userFile == "" {
+		userFile = UserFilePath()
+	}
+	for _, layer := range []struct {
+		path     string
+		record   *string
+		defaults *Defaults
+		ignored  [][]string
+	}{
+		{userFile, &report.UserFile, &report.UserDefaults, nil},
+		{ProjectFilePath(opts.ProjectPath), &report.ProjectFile, &report.ProjectDefaults, projectIgnoredKeys},
+	} {
+		if layer.path == "" {
+			continue
+		}
+		if _, err := os.Stat(layer.path); err != nil {
+			if !os.IsNotExist(err) || layer.path == opts.UserFile {
+				report.Errors = append(report.Errors, err)
+			}
+			continue
+		}
+		own, encoded, dropped, err := decodeLayer(layer.path, layer.ignored)
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+			continue
+		}
+		for _, key := range dropped {
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %s is ignored; only the user config file may set it", layer.path, key))
+		}
+		// The layer's JSON only holds the keys the file sets, so decoding it
+		// over cfg leaves the lower layers' settings elsewhere in place.
+		if err := json.Unmarshal(encoded, &cfg); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %w", layer.path, err))
+			continue
+		}
+		if own.Defaults.Model != nil {
+			report.FileDefaults.Model = own.Defaults.Model
+		}
+		if own.Defaults.Agent != nil {
+			report.FileDefaults.Agent = own.Defaults.Agent
+		}
+		*layer.defaults = own.Defaults
+		*layer.record = layer.path
+		if own.Keybindings.Preset != "" || len(own.Keybindings.Bindings) > 0 {
+			report.KeymapFiles = append(report.KeymapFiles, layer.path)
+		}
+	}
+	// Pinned models add up across files rather than the project list hiding
+	// the user's.
+	report.FileDefaults.FavoriteModels = mergeModelRefs(report.UserDefaults.FavoriteModels, report.ProjectDefaults.FavoriteModels)
+	report.FileDefaults.RecentModels = mergeModelRefs(report.UserDefaults.RecentModels, report.ProjectDefaults.RecentModels)
+
+	applyEnv(&cfg, getenv, &report)
+	if opts.Flags != nil {
+		opts.Flags.apply(&cfg, &report)
+	}
+
+	report.Errors = append(report.Errors, cfg.normalize()...)
+	return cfg, report
+}
+
+func mergeModelRefs(lists ...[]ModelRef) []ModelRef {
+	var merged []ModelRef
+	for _, list := range lists {
+		for _, ref := range list {
+			if !slices.Contains(merged, ref) {
+				merged = append(merged, ref)
+			}
+		}
+	}
+	return merged
+}
+
+// decodeLayer reads the config file at path once, returning its settings and
+// the JSON form of the keys it sets. The ignored keys are removed first and
+// those the file set are returned as dropped, e.g. "server".
+func decodeLayer(path string, ignored [][]string) (own Config, encoded []byte, dropped []string, err error) {
+	var tree map[string]interface{}
+	if err := configfile.Decode(path, &tree); err != nil {
+		return Config{}, nil, nil, err
+	}
+	for _, key := range ignored {
+		if removeKey(tree, key) {
+			dropped = append(dropped, strings.Join(key, "."))
+		}
+	}
+	encoded, err = json.Marshal(tree)
+	if err != nil {
+		return Config{}, nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := json.Unmarshal(encoded, &own); err != nil {
+		return Config{}, nil, nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return own, encoded, dropped, nil
+}
+
+// removeKey deletes the value at the dotted key path from tree and reports
+// whether there was one.
+func removeKey(tree map[string]interface{}, key []string) bool {
+	for _, name := range key[:len(key)-1] {
+		child, ok := tree[name].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		tree = child
+	}
+	last := key[len(key)-1]
+	if _, ok := tree[last]; !ok {
+		return false
+	}
+	delete(tree, last)
+	return true
+}
+
+func applyEnv(cfg *Config, getenv func(string) string, report *Report) {
+	if v := getenv("AGENT_SERVER_HOST"); v != "" {
+		cfg.overrideServer(Server{Host: v})
+	}
+	if v := getenv("AGENT_SERVER_PORT"); v != "" {
+		if port, err := strconv.Atoi(v); err == nil {
+			cfg.overrideServer(Server{Port: port})
+		} else {
+			report.Errors = append(report.Errors, fmt.Errorf("AGENT_SERVER_PORT: invalid port %q", v))
+		}
+	}
+	if v := getenv("AGENT_SERVER_PROTOCOL"); v != "" {
+		cfg.overrideServer(Server{Protocol: v})
+	}
+	if v := getenv("AGENT_SERVER_TOKEN"); v != "" {
+		cfg.overrideServer(Server{Token: v})
+	}
+
+	if name, provider := getenv("SELECTED_MODEL_NAME"), getenv("SELECTED_MODEL_PROVIDER"); name != "" || provider != "" {
+		model := ModelRef{}
+		if cfg.Defaults.Model != nil {
+			model = *cfg.Defaults.Model
+		}
+		if name != "" {
+			model.Name = name
+		}
+		if provider != "" {
+			model.Provider = provider
+		}
+		cfg.Defaults.Model = &model
+	}
+
+	agent := AgentRef{
+		ID:     getenv("SELECTED_AGENT_ID"),
+		Name:   getenv("SELECTED_AGENT_NAME"),
+		Type:   getenv("SELECTED_AGENT_TYPE"),
+		Detail: getenv("SELECTED_AGENT_DETAIL"),
+	}
+	if agent != (AgentRef{}) {
+		cfg.Defaults.Agent = &agent
+	}
+
+	if v := getenv("GOTUI_THEME"); v != "" {
+		cfg.Theme = v
+	}
+	if v := getenv("GOTUI_LAYOUT"); v != "" {
+		cfg.Layout = v
+	}
+	if v := getenv("GOTUI_LOG_PATH"); v != "" {
+		cfg.Log.Path = v
+	}
+	if v := getenv("GOTUI_LOG_LEVEL"); v != "" {
+		cfg.Log.Level = v
+	}
+	if v := getenv("GOTUI_NOTIFY_ALERT"); v != "" {
+		cfg.Notifications.Alert = v
+	}
+	if v := getenv("GOTUI_IMAGE_PREVIEWS"); v != "" {
+		cfg.ImagePreviews = v
+	}
+	if v := getenv("GOTUI_PROFILE"); v != "" {
+		cfg.Profile = v
+	}
+	if v := getenv("GOTUI_KEYMAP_PRESET"); v != "" {
+		cfg.Keybindings.Preset = v
+	}
+}
+
+// overrideServer applies the non-empty fields of server from the environment
+// or a flag to the [server] section and, through serverOverrides, to the
+// selected profile.
+func (c *Config) overrideServer(server Server) {
+	c.Server = c.Server.overriddenBy(server)
+	c.serverOverrides = c.serverOverrides.overriddenBy(server)
+}
+
+// normalize validates values, resetting invalid ones to their defaults.
+func (c *Config) normalize() []error {
+	var errs []error
+	defaults := Default()
+
+	errs = append(errs, c.Server.normalize("server", defaults.Server)...)
+
+	c.Layout = strings.ToLower(strings.TrimSpace(c.Layout))
+	switch c.Layout {
+	case LayoutPanel, LayoutWindow:
+	case "":
+		c.Layout = LayoutPanel
+	default:
+		errs = append(errs, fmt.Errorf("layout: expected %q or %q, got %q", LayoutPanel, LayoutWindow, c.Layout))
+		c.Layout = LayoutPanel
+	}
+
+	for name, profile := range c.Profiles {
+		errs = append(errs, profile.normalize("profiles."+name, defaults.Server)...)
+		c.Profiles[name] = profile
+	}
+	c.Profile = strings.TrimSpace(c.Profile)
+	if c.Profile != "" {
+		if name, ok := c.profileName(c.Profile); ok {
+			c.Profile = name
+		} else {
+			errs = append(errs, fmt.Errorf("profile: unknown profile %q", c.Profile))
+			c.Profile = ""
+		}
+	}
+	c.serverOverrides.Host = strings.TrimSpace(c.serverOverrides.Host)
+	if c.serverOverrides.Port < 0 || c.serverOverrides.Port > 65535 {
+		c.serverOverrides.Port = 0
+	}
+
+	c.Theme = strings.TrimSpace(c.Theme)
+	if c.Theme == "" {
+		c.Theme = ThemeAuto
+	}
+
+	if strings.TrimSpace(c.Log.Path) == "" {
+		c.Log.Path = defaults.Log.Path
+	}
+	if c.Log.Truncate == nil {
+		c.Log.Truncate = defaults.Log.Truncate
+	}
+	c.Log.Level = strings.ToLower(strings.TrimSpace(c.Log.Level))
+	switch c.Log.Level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	case "":
+		c.Log.Level = defaults.Log.Level
+	default:
+		errs = append(errs, fmt.Errorf("log.level: expected debug, info, warn or error, got %q", c.Log.Level))
+		c.Log.Level = defaults.Log.Level
+	}
+	if c.Log.MaxSizeMB == nil {
+		c.Log.MaxSizeMB = defaults.Log.MaxSizeMB
+	} else if *c.Log.MaxSizeMB < 0 {
+		errs = append(errs, fmt.Errorf("log.max_size_mb: must not be negative, got %d", *c.Log.MaxSizeMB))
+		c.Log.MaxSizeMB = defaults.Log.MaxSizeMB
+	}
+	if c.Log.MaxBackups == nil {
+		c.Log.MaxBackups = defaults.Log.MaxBackups
+	} else if *c.Log.MaxBackups < 0 {
+		errs = append(errs, fmt.Errorf("log.max_backups: must not be negative, got %d", *c.Log.MaxBackups))
+		c.Log.MaxBackups = defaults.Log.MaxBackups
+	}
+	if c.Log.RedactBodies == nil {
+		c.Log.RedactBodies = defaults.Log.RedactBodies
+	}
+
+	c.Notifications.Alert = strings.ToLower(strings.TrimSpace(c.Notifications.Alert))
+	if c.Notifications.Alert == "" {
+		c.Notifications.Alert = defaults.Notifications.Alert
+	} else if !slices.Contains(notify.Methods, c.Notifications.Alert) {
+		errs = append(errs, fmt.Errorf("notifications.alert: expected one of %s, got %q", strings.Join(notify.Methods, ", "), c.Notifications.Alert))
+		c.Notifications.Alert = defaults.Notifications.Alert
+	}
+	if c.Notifications.OnlyUnfocused == nil {
+		c.Notifications.OnlyUnfocused = defaults.Notifications.OnlyUnfocused
+	}
+	for event := range c.Notifications.Events {
+		if !slices.Contains(notify.Events, event) {
+			errs = append(errs, fmt.Errorf("notifications.events: unknown event %q (want %s)", event, strings.Join(notify.Events, ", ")))
+			delete(c.Notifications.Events, event)
+		}
+	}
+
+	c.ImagePreviews = strings.ToLower(strings.TrimSpace(c.ImagePreviews))
+	if c.ImagePreviews == "" {
+		c.ImagePreviews = defaults.ImagePreviews
+	} else if !slices.Contains(imagepreview.Protocols, c.ImagePreviews) {
+		errs = append(errs, fmt.Errorf("image_previews: expected one of %s, got %q", strings.Join(imagepreview.Protocols, ", "), c.ImagePreviews))
+		c.ImagePreviews = defaults.ImagePreviews
+	}
+
+	for model, price := range c.Usage.Prices {
+		if price.Input < 0 || price.Output < 0 || price.Cached < 0 {
+			errs = append(errs, fmt.Errorf("usage.prices.%s: prices must not be negative", model))
+			delete(c.Usage.Prices, model)
+		}
+	}
+	if b := c.Usage.Budgets.Conversation; b.Tokens < 0 || b.Cost < 0 {
+		errs = append(errs, fmt.Errorf("usage.budgets.conversation: limits must not be negative"))
+		c.Usage.Budgets.Conversation = usage.Budget{}
+	}
+	if b := c.Usage.Budgets.Session; b.Tokens < 0 || b.Cost < 0 {
+		errs = append(errs, fmt.Errorf("usage.budgets.session: limits must not be negative"))
+		c.Usage.Budgets.Session = usage.Budget{}
+	}
+	return errs
+}
+
+func (s *Server) normalize(prefix string, defaults Server) []error {
+	var errs []error
+	s.Host = strings.TrimSpace(s.Host)
+	if s.Host == "" {
+		s.Host = defaults.Host
+	}
+	if s.Port == 0 {
+		s.Port = defaults.Port
+	}
+	if s.Port < 0 || s.Port > 65535 {
+		errs = append(errs, fmt.Errorf("%s.port: %d is out of range", prefix, s.Port))
+		s.Port = defaults.Port
+	}
+	s.Protocol = strings.ToLower(strings.TrimSpace(s.Protocol))
+	switch s.Protocol {
+	case "", "ws", "wss", "http", "https":
+	default:
+		errs = append(errs, fmt.Errorf("%s.protocol: unknown protocol %q", prefix, s.Protocol))
+		s.Protocol = ""
+	}
+	s.Auth = strings.ToLower(strings.TrimSpace(s.Auth))
+	switch s.Auth {
+	case "", transport.SchemeBearer, transport.SchemeAPIKey:
+	default:
+		errs = append(errs, fmt.Errorf("%s.auth: expected %q or %q, got %q", prefix, transport.SchemeBearer, transport.SchemeAPIKey, s.Auth))
+		s.Auth = ""
+	}
+	s.TokenFile = expandHome(strings.TrimSpace(s.TokenFile))
+	s.TLS.CAFile = expandHome(strings.TrimSpace(s.TLS.CAFile))
+	s.TLS.CertFile = expandHome(strings.TrimSpace(s.TLS.CertFile))
+	s.TLS.KeyFile = expandHome(strings.TrimSpace(s.TLS.KeyFile))
+	return errs
+}
+
+// Keys of the defaults table, as written by UpdateUserDefaults.
+const (
+	DefaultsModel          = "model"
+	DefaultsAgent          = "agent"
+	DefaultsFavoriteModels = "favorite_models"
+	DefaultsRecentModels   = "recent_models"
+)
+
+// UpdateUserDefaults writes the named keys of defaults to the [defaults] table
+// of the user config file. Other keys and the rest of the file are left as
+// they are; keys holding empty values are removed.
+func UpdateUserDefaults(path string, defaults Defaults, keys ...string) error {
+	if path == "" {
+		return fmt.Errorf("no user config directory available")
+	}
+	var fields map[string]interface{}
+	encoded, err := json.Marshal(defaults)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return err
+	}
+	values := make(map[string]interface{}, len(keys))
+	for _, key := range keys {
+		values[key] = fields[key]
+	}
+	return configfile.UpdateTable(path, "defaults", values)
+}
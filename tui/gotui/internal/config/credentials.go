@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gotui/internal/configfile"
+	"gotui/internal/transport"
+)
+
+// credentialsEntry is one profile's section of the credentials file.
+type credentialsEntry struct {
+	Auth  string `json:"auth,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// CredentialsFilePath returns the user credentials file, if one exists. It maps
+// profile names to tokens, e.g.
+//
+//	[staging]
+//	token = "..."
+func CredentialsFilePath() string {
+	dir := configfile.UserDir()
+	if dir == "" {
+		return ""
+	}
+	return configfile.FindFile(dir, "credentials")
+}
+
+// Credentials resolves the token for the profile. The inline token wins, then
+// token_file, then the profile's entry in the credentials file.
+func (p Profile) Credentials() (transport.Credentials, error) {
+	creds := transport.Credentials{Scheme: p.Auth, Token: strings.TrimSpace(p.Token)}
+	if !creds.IsZero() {
+		return creds, nil
+	}
+	if p.TokenFile != "" {
+		token, err := transport.ReadTokenFile(p.TokenFile)
+		if err != nil {
+			return transport.Credentials{}, fmt.Errorf("profile %s: %w", p.Name, err)
+		}
+		creds.Token = token
+		return creds, nil
+	}
+
+	path := CredentialsFilePath()
+	if path == "" {
+		return creds, nil
+	}
+	if err := transport.CheckPrivate(path); err != nil {
+		return transport.Credentials{}, err
+	}
+	var entries map[string]credentialsEntry
+	if err := configfile.Decode(path, &entries); err != nil {
+		return transport.Credentials{}, err
+	}
+	var entry credentialsEntry
+	found := false
+	for name, candidate := range entries {
+		if strings.EqualFold(name, p.Name) {
+			entry, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return creds, nil
+	}
+	creds.Token = strings.TrimSpace(entry.Token)
+	if creds.Scheme == "" {
+		creds.Scheme = entry.Auth
+	}
+	return creds, nil
+}
+
+// TLSOptions returns the certificate settings for the profile.
+func (p Profile) TLSOptions() transport.TLSOptions {
+	return transport.TLSOptions(p.TLS)
+}
+
+// Transport resolves the credentials and TLS configuration for the profile.
+func (p Profile) Transport() (transport.Settings, error) {
+	creds, err := p.Credentials()
+	if err != nil {
+		return transport.Settings{}, err
+	}
+	tlsConfig, err := p.TLSOptions().Config()
+	if err != nil {
+		return transport.Settings{}, fmt.Errorf("profile %s: %w", p.Name, err)
+	}
+	return transport.Settings{Credentials: creds, TLS: tlsConfig}, nil
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Flags binds the command-line flags that override configuration. Only flags
+// explicitly passed on the command line take effect.
+type Flags struct {
+	fs *flag.FlagSet
+
+	host         string
+	port         int
+	protocol     string
+	theme        string
+	layout       string
+	logPath      string
+	logLevel     string
+	keymapPreset string
+	configFile   string
+	profile      string
+}
+
+// RegisterFlags defines the configuration flags on fs.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	defaults := Default()
+	f := &Flags{fs: fs}
+	fs.StringVar(&f.host, "host", defaults.Server.Host, "Server host")
+	fs.IntVar(&f.port, "port", defaults.Server.Port, "Server port")
+	fs.StringVar(&f.protocol, "protocol", "", "Server protocol (ws, wss, http, https)")
+	fs.StringVar(&f.theme, "theme", "", "Theme name, or \"auto\" to follow the terminal background")
+	fs.StringVar(&f.layout, "layout", "", "Chat layout mode (panel or window)")
+	fs.StringVar(&f.logPath, "log", "", "Debug log file path")
+	fs.StringVar(&f.logLevel, "log-level", "", "Minimum log level (debug, info, warn, error)")
+	fs.StringVar(&f.keymapPreset, "keymap", "", "Keymap preset (default, vim, emacs)")
+	fs.StringVar(&f.configFile, "config", "", "User config file path")
+	fs.StringVar(&f.profile, "profile", "", "Server profile to connect to")
+	return f
+}
+
+// ConfigFile returns the user config file passed with -config, if any.
+func (f *Flags) ConfigFile() string {
+	if f == nil {
+		return ""
+	}
+	return f.configFile
+}
+
+func (f *Flags) apply(cfg *Config, report *Report) {
+	if f == nil || f.fs == nil {
+		return
+	}
+	if !f.fs.Parsed() {
+		report.Errors = append(report.Errors, fmt.Errorf("flags applied before parsing"))
+		return
+	}
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "host":
+			cfg.overrideServer(Server{Host: f.host})
+		case "port":
+			cfg.overrideServer(Server{Port: f.port})
+		case "protocol":
+			cfg.overrideServer(Server{Protocol: f.protocol})
+		case "theme":
+			cfg.Theme = f.theme
+		case "layout":
+			cfg.Layout = f.layout
+		case "log":
+			cfg.Log.Path = f.logPath
+		case "log-level":
+			cfg.Log.Level = f.logLevel
+		case "profile":
+			cfg.Profile = f.profile
+		case "keymap":
+			cfg.Keybindings.Preset = f.keymapPreset
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile names the implicit profile built from the [server] section.
+const DefaultProfile = "default"
+
+// Profile is a named server connection target.
+type Profile struct {
+	Name string
+	Server
+}
+
+// Endpoint renders the profile address for display, e.g. "wss://host:443".
+func (p Profile) Endpoint() string {
+	protocol := p.Protocol
+	if protocol == "" {
+		protocol = "ws"
+	}
+	return fmt.Sprintf("%s://%s:%d", protocol, p.Host, p.Port)
+}
+
+// Secure reports whether the profile connects over TLS.
+func (p Profile) Secure() bool {
+	return p.Protocol == "wss" || p.Protocol == "https"
+}
+
+// ServerProfiles lists the connection profiles: the implicit default profile
+// first (unless a profile of that name is configured) followed by the named
+// profiles in alphabetical order. Server settings from the environment or
+// flags override the active profile's, so -host works with any profile.
+func (c Config) ServerProfiles() []Profile {
+	defaultName, names := DefaultProfile, make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		if strings.EqualFold(name, DefaultProfile) {
+			defaultName = name
+		} else {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	defaultServer := c.Server
+	if server, ok := c.Profiles[defaultName]; ok {
+		defaultServer = server
+	}
+	profiles := []Profile{{Name: defaultName, Server: defaultServer}}
+	for _, name := range names {
+		profiles = append(profiles, Profile{Name: name, Server: c.Profiles[name]})
+	}
+	active := &profiles[0]
+	for i := range profiles {
+		if c.Profile != "" && profiles[i].Name == c.Profile {
+			active = &profiles[i]
+		}
+	}
+	active.Server = active.Server.overriddenBy(c.serverOverrides)
+	return profiles
+}
+
+// ActiveProfile returns the profile selected with the profile setting, falling
+// back to the default profile.
+func (c Config) ActiveProfile() Profile {
+	profiles := c.ServerProfiles()
+	for _, profile := range profiles {
+		if profile.Name == c.Profile {
+			return profile
+		}
+	}
+	return profiles[0]
+}
+
+// profileName returns the configured name of the profile called name,
+// ignoring case as profile names are everywhere.
+func (c Config) profileName(name string) (string, bool) {
+	if strings.EqualFold(name, DefaultProfile) {
+		for configured := range c.Profiles {
+			if strings.EqualFold(configured, DefaultProfile) {
+				return configured, true
+			}
+		}
+		return DefaultProfile, true
+	}
+	for configured := range c.Profiles {
+		if strings.EqualFold(configured, name) {
+			return configured, true
+		}
+	}
+	return "", false
+}
+
+// overriddenBy returns s with the non-empty fields of overrides applied.
+func (s Server) overriddenBy(overrides Server) Server {
+	if overrides.Host != "" {
+		s.Host = overrides.Host
+	}
+	if overrides.Port != 0 {
+		s.Port = overrides.Port
+	}
+	if overrides.Protocol != "" {
+		s.Protocol = overrides.Protocol
+	}
+	if overrides.Token != "" {
+		s.Token = overrides.Token
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+// Package configfile locates and decodes gotui configuration files. Files may be
+// written as JSON or TOML; both are decoded through the same json struct tags.
+package configfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+const (
+	appDirName     = "gotui"
+	projectDirName = ".codebolt/gotui"
+)
+
+// UserDir returns the per-user configuration directory, e.g. ~/.config/gotui.
+func UserDir() string {
+	if dir := strings.TrimSpace(os.Getenv("GOTUI_CONFIG_DIR")); dir != "" {
+		return dir
+	}
+	base, err := os.UserConfigDir()
+	if err != nil || base == "" {
+		home, herr := os.UserHomeDir()
+		if herr != nil || home == "" {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, appDirName)
+}
+
+// ProjectDir returns the configuration directory inside a project checkout.
+func ProjectDir(projectPath string) string {
+	projectPath = strings.TrimSpace(projectPath)
+	if projectPath == "" {
+		return ""
+	}
+	return filepath.Join(projectPath, projectDirName)
+}
+
+// UserProjectFile returns the file that keeps state of projectPath in the kind
+// directory of UserDir, e.g. ~/.config/gotui/history/app-1a2b3c4d5e6f.jsonl,
+// or "" without a user directory. Keeping it out of the checkout avoids
+// committing it.
+func UserProjectFile(kind, projectPath, ext string) string {
+	dir := UserDir()
+	if dir == "" {
+		return ""
+	}
+	projectPath = strings.TrimSpace(projectPath)
+	if abs, err := filepath.Abs(projectPath); err == nil {
+		projectPath = abs
+	}
+	sum := sha256.Sum256([]byte(projectPath))
+	name := filepath.Base(projectPath)
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		name = "default"
+	}
+	return filepath.Join(dir, kind, name+"-"+hex.EncodeToString(sum[:6])+ext)
+}
+
+// IsSupported reports whether the file extension is a known config format.
+func IsSupported(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".toml":
+		return true
+	}
+	return false
+}
+
+// Decode reads path and decodes it into v based on the file extension.
+func Decode(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := DecodeBytes(data, filepath.Ext(path), v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// DecodeBytes decodes data in the given format (".json" or ".toml") into v.
+func DecodeBytes(data []byte, format string, v interface{}) error {
+	switch strings.TrimPrefix(strings.ToLower(format), ".") {
+	case "json":
+		return json.Unmarshal(data, v)
+	case "toml":
+		tree, err := parseTOML(data)
+		if err != nil {
+			return err
+		}
+		// Round-trip through JSON so callers only need json struct tags.
+		encoded, err := json.Marshal(tree)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(encoded, v)
+	default:
+		return fmt.Errorf("unsupported config format %q", format)
+	}
+}
+
+// parseTOML decodes TOML data into generic maps.
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	if _, err := toml.Decode(string(data), &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// FindFile returns the first existing file named base with a supported
+// extension inside dir, preferring TOML over JSON.
+func FindFile(dir, base string) string {
+	if dir == "" {
+		return ""
+	}
+	for _, ext := range []string{".toml", ".json"} {
+		path := filepath.Join(dir, base+ext)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// ListFiles returns the supported config files directly inside dir, sorted by name.
+func ListFiles(dir string) []string {
+	if dir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !IsSupported(entry.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Signature summarises the names, sizes and modification times of the given
+// files and directories. Callers poll it to detect changes without a watcher.
+func Signature(paths ...string) string {
+	var b strings.Builder
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			b.WriteString(path)
+			b.WriteString(":missing;")
+			continue
+		}
+		if !info.IsDir() {
+			fmt.Fprintf(&b, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+			continue
+		}
+		for _, file := range ListFiles(path) {
+			if fi, err := os.Stat(file); err == nil {
+				fmt.Fprintf(&b, "%s:%d:%d;", file, fi.Size(), fi.ModTime().UnixNano())
+			}
+		}
+	}
+	return b.String()
+}
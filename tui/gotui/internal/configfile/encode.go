@@ -0,0 +1,82 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Encode writes v to path in the format implied by its extension. The file is
+// replaced atomically and created with user-only permissions. Comments in an
+// existing TOML file are not preserved.
+func Encode(path string, v interface{}) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err = json.MarshalIndent(v, "", "  ")
+		if err == nil {
+			data = append(data, '\n')
+		}
+	case ".toml":
+		data, err = encodeTOML(v)
+	default:
+		return fmt.Errorf("unsupported config format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, data)
+}
+
+// WriteFile replaces path atomically with data, readable by the user only.
+// Missing parent directories are created.
+func WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// encodeTOML converts v to generic maps via its json tags and writes them as
+// TOML. Numbers are kept as written so integers are not encoded as floats.
+func encodeTOML(v interface{}) ([]byte, error) {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("TOML root must be an object")
+	}
+	var b bytes.Buffer
+	enc := toml.NewEncoder(&b)
+	enc.Indent = ""
+	if err := enc.Encode(tree); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,199 @@
+package configfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// UpdateTable sets keys of the top-level table in the file at path, leaving
+// every other key as it is. A nil value removes the key. Values are converted
+// through their json tags. In TOML files only the lines of the changed keys
+// are rewritten, so comments and layout survive; when the table is written in
+// a form that cannot be edited line by line the file is re-encoded instead.
+func UpdateTable(path, table string, values map[string]interface{}) error {
+	if path == "" {
+		return fmt.Errorf("no config file to update")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	generic, err := toGeneric(values)
+	if err != nil {
+		return err
+	}
+	updates := generic.(map[string]interface{})
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		tree := map[string]interface{}{}
+		if len(strings.TrimSpace(string(data))) > 0 {
+			if err := json.Unmarshal(data, &tree); err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		}
+		applyTable(tree, table, updates)
+		return Encode(path, tree)
+	case ".toml":
+		tree, err := parseTOML(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		applyTable(tree, table, updates)
+		if spliced, ok := spliceTOMLTable(string(data), table, updates); ok && sameTree(spliced, tree) {
+			return WriteFile(path, []byte(spliced))
+		}
+		return Encode(path, tree)
+	default:
+		return fmt.Errorf("unsupported config format %q", filepath.Ext(path))
+	}
+}
+
+// toGeneric converts v to generic maps via its json tags. Numbers are kept as
+// json.Number so integers stay integers when written back as TOML.
+func toGeneric(v interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+func applyTable(tree map[string]interface{}, table string, updates map[string]interface{}) {
+	section, ok := tree[table].(map[string]interface{})
+	if !ok {
+		section = map[string]interface{}{}
+	}
+	for key, value := range updates {
+		if value == nil {
+			delete(section, key)
+			continue
+		}
+		section[key] = value
+	}
+	if len(section) == 0 {
+		delete(tree, table)
+		return
+	}
+	tree[table] = section
+}
+
+// sameTree reports whether the TOML text decodes to want.
+func sameTree(text string, want map[string]interface{}) bool {
+	got, err := parseTOML([]byte(text))
+	if err != nil {
+		return false
+	}
+	a, errA := toGeneric(got)
+	b, errB := toGeneric(want)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+var tomlHeader = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+
+// spliceTOMLTable rewrites, removes or adds the lines of the updated keys in
+// the [table] section of data. Keys are written in sorted order when added.
+// Values that do not fit on one line, such as nested tables, report false.
+func spliceTOMLTable(data, table string, updates map[string]interface{}) (string, bool) {
+	lines := strings.SplitAfter(data, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	header := toml.Key{table}.String()
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start < 0 {
+			if m := tomlHeader.FindStringSubmatch(line); m != nil && m[1] == header {
+				start = i
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			end = i
+			break
+		}
+	}
+
+	var added []string
+	for _, key := range sortedKeys(updates) {
+		value := updates[key]
+		line := ""
+		if value != nil {
+			encoded, err := toml.Marshal(map[string]interface{}{key: value})
+			if err != nil || strings.Count(string(encoded), "\n") != 1 {
+				return "", false
+			}
+			line = string(encoded)
+		}
+
+		found := -1
+		if start >= 0 {
+			pattern := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(toml.Key{key}.String()) + `\s*=`)
+			for i := start + 1; i < end; i++ {
+				if pattern.MatchString(lines[i]) {
+					found = i
+					break
+				}
+			}
+		}
+		switch {
+		case found >= 0:
+			lines[found] = line
+		case line != "":
+			added = append(added, line)
+		}
+	}
+
+	if len(added) > 0 {
+		if start < 0 {
+			if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+				lines[len(lines)-1] += "\n"
+			}
+			section := []string{"[" + header + "]\n"}
+			if len(lines) > 0 {
+				section = append([]string{"\n"}, section...)
+			}
+			lines = append(append(lines, section...), added...)
+		} else {
+			// Insert after the last key of the section, before trailing blanks.
+			at := end
+			for at > start+1 && strings.TrimSpace(lines[at-1]) == "" {
+				at--
+			}
+			if at > 0 && !strings.HasSuffix(lines[at-1], "\n") {
+				lines[at-1] += "\n"
+			}
+			lines = append(lines[:at], append(added, lines[at:]...)...)
+		}
+	}
+	return strings.Join(lines, ""), true
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,106 @@
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNothingStaged is returned when the index matches HEAD.
+	ErrNothingStaged = errors.New("nothing staged")
+	// ErrNoBase is returned when no branch to compare against can be found.
+	ErrNoBase = errors.New("no base branch found")
+)
+
+// Changes summarises a set of changes as text, e.g. for drafting a commit
+// message or pull request description.
+type Changes struct {
+	// Branch is the current branch name.
+	Branch string
+	// Base is the revision compared against; empty for staged changes.
+	Base string
+	// Commits are one-line summaries, oldest first.
+	Commits []string
+	Stat    string
+	Patch   string
+}
+
+// StagedChanges returns the changes recorded in the index.
+func (r *Repo) StagedChanges(ctx context.Context) (Changes, error) {
+	stat, err := r.Run(ctx, "diff", "--cached", "--no-color", "--stat")
+	if err != nil {
+		return Changes{}, err
+	}
+	if strings.TrimSpace(stat) == "" {
+		return Changes{}, ErrNothingStaged
+	}
+	patch, err := r.Run(ctx, "diff", "--cached", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return Changes{}, err
+	}
+	branch, _ := r.Run(ctx, "branch", "--show-current")
+	return Changes{Branch: strings.TrimSpace(branch), Stat: stat, Patch: patch}, nil
+}
+
+// BranchChanges returns the commits and diff of HEAD since it forked from the
+// repository's default branch.
+func (r *Repo) BranchChanges(ctx context.Context) (Changes, error) {
+	base, err := r.baseRef(ctx)
+	if err != nil {
+		return Changes{}, err
+	}
+	out, err := r.Run(ctx, "merge-base", base, "HEAD")
+	if err != nil {
+		return Changes{}, err
+	}
+	fork := strings.TrimSpace(out)
+
+	log, err := r.Run(ctx, "log", "--reverse", "--no-color", "--format=%h %s", fork+"..HEAD")
+	if err != nil {
+		return Changes{}, err
+	}
+	commits := splitLines(log)
+	if len(commits) == 0 {
+		return Changes{}, fmt.Errorf("no commits ahead of %s", base)
+	}
+	stat, err := r.Run(ctx, "diff", "--no-color", "--stat", fork, "HEAD")
+	if err != nil {
+		return Changes{}, err
+	}
+	patch, err := r.Run(ctx, "diff", "--no-color", "--no-ext-diff", fork, "HEAD")
+	if err != nil {
+		return Changes{}, err
+	}
+	branch, _ := r.Run(ctx, "branch", "--show-current")
+	return Changes{
+		Branch:  strings.TrimSpace(branch),
+		Base:    base,
+		Commits: commits,
+		Stat:    stat,
+		Patch:   patch,
+	}, nil
+}
+
+// baseRef picks the branch a pull request would target: the remote's default
+// branch, then main or master, then the current branch's upstream.
+func (r *Repo) baseRef(ctx context.Context) (string, error) {
+	if out, err := r.Run(ctx, "symbolic-ref", "-q", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimSpace(out), nil
+	}
+	current, _ := r.Run(ctx, "branch", "--show-current")
+	current = strings.TrimSpace(current)
+	for _, ref := range []string{"origin/main", "origin/master", "main", "master"} {
+		if ref == current {
+			continue
+		}
+		if _, err := r.Run(ctx, "rev-parse", "--verify", "-q", ref); err == nil {
+			return ref, nil
+		}
+	}
+	if out, err := r.Run(ctx, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		return strings.TrimSpace(out), nil
+	}
+	return "", ErrNoBase
+}
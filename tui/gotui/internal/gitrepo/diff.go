@@ -0,0 +1,144 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FileDiff is a parsed single-file unified diff.
+type FileDiff struct {
+	Path string
+	// Header holds the "diff --git", index and ---/+++ lines.
+	Header []string
+	Hunks  []Hunk
+	// Binary is set when git reports a binary change without hunks.
+	Binary bool
+}
+
+// Hunk is one "@@" section of a diff.
+type Hunk struct {
+	Header   string
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	// Lines are the hunk body lines including their ' ', '+' or '-' prefix.
+	Lines []string
+}
+
+// Empty reports whether the diff has no content changes.
+func (d FileDiff) Empty() bool { return len(d.Hunks) == 0 && !d.Binary }
+
+// Diff returns the diff of entry: index against HEAD when staged is set,
+// otherwise the working tree against the index. Untracked files are diffed
+// against an empty file.
+func (r *Repo) Diff(ctx context.Context, entry FileEntry, staged bool) (FileDiff, error) {
+	var (
+		out string
+		err error
+	)
+	switch {
+	case staged:
+		out, err = r.Run(ctx, "diff", "--cached", "--no-color", "--no-ext-diff", "--", entry.Path)
+	case entry.Untracked():
+		out, err = r.Run(ctx, "diff", "--no-index", "--no-color", "--no-ext-diff", "--", os.DevNull, entry.Path)
+		// --no-index exits with 1 when the files differ.
+		if err != nil && exitCode(err) == 1 {
+			err = nil
+		}
+	default:
+		out, err = r.Run(ctx, "diff", "--no-color", "--no-ext-diff", "--", entry.Path)
+	}
+	if err != nil {
+		return FileDiff{Path: entry.Path}, err
+	}
+	diff := ParseDiff(out)
+	diff.Path = entry.Path
+	return diff, nil
+}
+
+// ParseDiff parses the first file of a unified diff.
+func ParseDiff(text string) FileDiff {
+	var diff FileDiff
+	var current *Hunk
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git ") && (len(diff.Header) > 0 || current != nil):
+			// A second file; only the first is parsed.
+			if current != nil {
+				diff.Hunks = append(diff.Hunks, *current)
+			}
+			return diff
+		case strings.HasPrefix(line, "@@"):
+			if current != nil {
+				diff.Hunks = append(diff.Hunks, *current)
+			}
+			hunk := parseHunkHeader(line)
+			current = &hunk
+		case current != nil:
+			if line == "" {
+				line = " "
+			}
+			current.Lines = append(current.Lines, line)
+		default:
+			if strings.HasPrefix(line, "Binary files ") {
+				diff.Binary = true
+			}
+			if line != "" {
+				diff.Header = append(diff.Header, line)
+			}
+		}
+	}
+	if current != nil {
+		diff.Hunks = append(diff.Hunks, *current)
+	}
+	return diff
+}
+
+// parseHunkHeader reads "@@ -a,b +c,d @@ context".
+func parseHunkHeader(line string) Hunk {
+	hunk := Hunk{Header: line}
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return hunk
+	}
+	hunk.OldStart, hunk.OldLines = parseRange(strings.TrimPrefix(fields[1], "-"))
+	hunk.NewStart, hunk.NewLines = parseRange(strings.TrimPrefix(fields[2], "+"))
+	return hunk
+}
+
+func parseRange(s string) (start, count int) {
+	startText, countText, ok := strings.Cut(s, ",")
+	start, _ = strconv.Atoi(startText)
+	if !ok {
+		return start, 1
+	}
+	count, _ = strconv.Atoi(countText)
+	return start, count
+}
+
+// HunkPatch returns a patch containing only hunk i, suitable for git apply.
+func (d FileDiff) HunkPatch(i int) (string, error) {
+	if i < 0 || i >= len(d.Hunks) {
+		return "", fmt.Errorf("hunk %d out of range", i)
+	}
+	if len(d.Header) == 0 {
+		return "", fmt.Errorf("diff for %s has no header", d.Path)
+	}
+	var b strings.Builder
+	for _, line := range d.Header {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	hunk := d.Hunks[i]
+	b.WriteString(hunk.Header)
+	b.WriteByte('\n')
+	for _, line := range hunk.Lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
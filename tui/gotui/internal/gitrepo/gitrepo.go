@@ -0,0 +1,208 @@
+// Package gitrepo runs git commands against a working tree and parses their
+// output for the Git tab.
+package gitrepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Repo is a git working tree. An empty Dir uses the process working directory.
+type Repo struct {
+	Dir string
+	// GitDir is the repository's .git directory, when known.
+	GitDir string
+}
+
+// New returns a repo rooted at dir.
+func New(dir string) *Repo {
+	return &Repo{Dir: strings.TrimSpace(dir)}
+}
+
+// Open locates the repository containing dir and returns it rooted at its
+// top-level directory, so that status paths and pathspecs agree.
+func Open(ctx context.Context, dir string) (*Repo, error) {
+	probe := New(dir)
+	out, err := probe.Run(ctx, "rev-parse", "--show-toplevel", "--absolute-git-dir")
+	if err != nil {
+		return nil, err
+	}
+	lines := splitLines(out)
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("%s is not inside a git working tree", dir)
+	}
+	return &Repo{Dir: lines[0], GitDir: lines[1]}, nil
+}
+
+// Signature fingerprints the working tree cheaply: it changes when a listed
+// file is edited or the index or HEAD moves, without running git diff.
+func (r *Repo) Signature(status Status) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s|%s|%d|%d\n", status.Branch, status.Upstream, status.Ahead, status.Behind)
+	stamp := func(path string) {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, "%s %d %d\n", path, info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	if r.GitDir != "" {
+		stamp(filepath.Join(r.GitDir, "index"))
+		stamp(filepath.Join(r.GitDir, "HEAD"))
+		stamp(filepath.Join(r.GitDir, "logs", "HEAD"))
+	}
+	for _, entry := range status.Entries {
+		fmt.Fprintf(&b, "%c%c ", entry.Index, entry.Worktree)
+		stamp(filepath.Join(r.Dir, entry.Path))
+		b.WriteString(entry.Path)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Run executes git with args and returns its standard output. On failure the
+// error carries git's standard error.
+func (r *Repo) Run(ctx context.Context, args ...string) (string, error) {
+	return r.run(ctx, nil, args...)
+}
+
+func (r *Repo) run(ctx context.Context, stdin []byte, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	// Never block on an editor or credential prompt.
+	cmd.Env = append(cmd.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_EDITOR=true")
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), &Error{Args: args, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return stdout.String(), nil
+}
+
+// Error describes a failed git invocation.
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	name := "git"
+	if len(e.Args) > 0 {
+		name += " " + e.Args[0]
+	}
+	if e.Stderr != "" {
+		return fmt.Sprintf("%s: %s", name, firstLine(e.Stderr))
+	}
+	return fmt.Sprintf("%s: %v", name, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// exitCode returns the process exit code of a failed git run, or -1.
+func exitCode(err error) int {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// Root returns the top-level directory of the working tree.
+func (r *Repo) Root(ctx context.Context) (string, error) {
+	out, err := r.Run(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// Stage adds the paths to the index.
+func (r *Repo) Stage(ctx context.Context, paths ...string) error {
+	_, err := r.Run(ctx, append([]string{"add", "--all", "--"}, paths...)...)
+	return err
+}
+
+// Unstage removes the paths from the index, keeping working tree changes.
+func (r *Repo) Unstage(ctx context.Context, paths ...string) error {
+	if _, err := r.Run(ctx, "rev-parse", "--verify", "-q", "HEAD"); err != nil {
+		// No commits yet: there is nothing to restore the index from.
+		_, err := r.Run(ctx, append([]string{"rm", "--cached", "-r", "-q", "--"}, paths...)...)
+		return err
+	}
+	_, err := r.Run(ctx, append([]string{"restore", "--staged", "--"}, paths...)...)
+	return err
+}
+
+// Discard throws away working tree changes to the entry. Untracked files are
+// deleted; staged changes are kept.
+func (r *Repo) Discard(ctx context.Context, entry FileEntry) error {
+	if entry.Untracked() {
+		_, err := r.Run(ctx, "clean", "-f", "-q", "--", entry.Path)
+		return err
+	}
+	_, err := r.Run(ctx, "restore", "--worktree", "--", entry.Path)
+	return err
+}
+
+// ApplyPatch applies a patch to the index (cached) and/or working tree,
+// optionally in reverse.
+func (r *Repo) ApplyPatch(ctx context.Context, patch string, cached, reverse bool) error {
+	args := []string{"apply", "--whitespace=nowarn", "--unidiff-zero"}
+	if cached {
+		args = append(args, "--cached")
+	}
+	if reverse {
+		args = append(args, "--reverse")
+	}
+	args = append(args, "-")
+	_, err := r.run(ctx, []byte(patch), args...)
+	return err
+}
+
+// Commit records the staged changes with message.
+func (r *Repo) Commit(ctx context.Context, message string) (string, error) {
+	if strings.TrimSpace(message) == "" {
+		return "", errors.New("commit message is empty")
+	}
+	if _, err := r.run(ctx, []byte(message), "commit", "--cleanup=strip", "-F", "-"); err != nil {
+		return "", err
+	}
+	out, err := r.Run(ctx, "log", "-1", "--format=%h %s")
+	return strings.TrimSpace(out), err
+}
+
+// RecentCommits returns up to n one-line commit summaries.
+func (r *Repo) RecentCommits(ctx context.Context, n int) ([]string, error) {
+	out, err := r.Run(ctx, "log", "--oneline", "-n", fmt.Sprint(n))
+	if err != nil {
+		if _, headErr := r.Run(ctx, "rev-parse", "--verify", "-q", "HEAD"); headErr != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return splitLines(out), nil
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
@@ -0,0 +1,280 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Commit is one entry of the commit history.
+type Commit struct {
+	Hash      string
+	ShortHash string
+	Parents   []string
+	Author    string
+	Date      time.Time
+	// Refs lists branch and tag names pointing at the commit, as printed by %D.
+	Refs    string
+	Subject string
+}
+
+// LogLine is one row of `git log --graph`. Rows that only continue the graph
+// between commits have a nil Commit.
+type LogLine struct {
+	Graph  string
+	Commit *Commit
+}
+
+// LogOptions selects a page of history.
+type LogOptions struct {
+	// Path limits the history to commits touching the file or directory.
+	Path  string
+	Skip  int
+	Limit int
+}
+
+const logFieldSep = "\x1f"
+
+// Log returns a page of commit history with its branch graph.
+func (r *Repo) Log(ctx context.Context, opts LogOptions) ([]LogLine, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = 100
+	}
+	format := strings.Join([]string{"", "%H", "%h", "%P", "%an", "%at", "%D", "%s"}, logFieldSep)
+	args := []string{
+		"log", "--graph", "--date-order", "--no-color",
+		"--format=" + format,
+		"--skip=" + strconv.Itoa(opts.Skip),
+		"-n", strconv.Itoa(opts.Limit),
+	}
+	if opts.Path != "" {
+		args = append(args, "--", opts.Path)
+	} else {
+		args = append(args, "--all")
+	}
+	out, err := r.Run(ctx, args...)
+	if err != nil {
+		if _, headErr := r.Run(ctx, "rev-parse", "--verify", "-q", "HEAD"); headErr != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseLog(out), nil
+}
+
+func parseLog(out string) []LogLine {
+	var lines []LogLine
+	for _, raw := range splitLines(out) {
+		graph, rest, ok := strings.Cut(raw, logFieldSep)
+		if !ok {
+			lines = append(lines, LogLine{Graph: strings.TrimRight(raw, " ")})
+			continue
+		}
+		fields := strings.SplitN(rest, logFieldSep, 7)
+		if len(fields) < 7 {
+			lines = append(lines, LogLine{Graph: strings.TrimRight(graph, " ")})
+			continue
+		}
+		commit := &Commit{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Parents:   strings.Fields(fields[2]),
+			Author:    fields[3],
+			Refs:      fields[5],
+			Subject:   fields[6],
+		}
+		if secs, err := strconv.ParseInt(fields[4], 10, 64); err == nil {
+			commit.Date = time.Unix(secs, 0)
+		}
+		lines = append(lines, LogLine{Graph: strings.TrimRight(graph, " "), Commit: commit})
+	}
+	return lines
+}
+
+// FileStat is a file's line counts in a commit.
+type FileStat struct {
+	Path string
+	// OrigPath is the path before a rename.
+	OrigPath string
+	Added    int
+	Deleted  int
+	Binary   bool
+}
+
+// CommitDetail is the full description of a commit.
+type CommitDetail struct {
+	Commit
+	AuthorEmail string
+	Committer   string
+	CommitDate  time.Time
+	Message     string
+	Files       []FileStat
+}
+
+// Show returns the message and per-file stats of a commit. Merge commits are
+// compared against their first parent.
+func (r *Repo) Show(ctx context.Context, rev string) (CommitDetail, error) {
+	format := strings.Join([]string{"%H", "%h", "%P", "%an", "%ae", "%at", "%cn", "%ct", "%D", "%B"}, logFieldSep)
+	out, err := r.Run(ctx, "show", "-s", "--no-color", "--format="+format, rev)
+	if err != nil {
+		return CommitDetail{}, err
+	}
+	fields := strings.SplitN(strings.TrimRight(out, "\n"), logFieldSep, 10)
+	if len(fields) < 10 {
+		return CommitDetail{}, fmt.Errorf("unexpected git show output for %s", rev)
+	}
+	detail := CommitDetail{
+		Commit: Commit{
+			Hash:      fields[0],
+			ShortHash: fields[1],
+			Parents:   strings.Fields(fields[2]),
+			Author:    fields[3],
+			Refs:      fields[8],
+		},
+		AuthorEmail: fields[4],
+		Committer:   fields[6],
+		Message:     strings.TrimRight(fields[9], "\n"),
+	}
+	if secs, err := strconv.ParseInt(fields[5], 10, 64); err == nil {
+		detail.Date = time.Unix(secs, 0)
+	}
+	if secs, err := strconv.ParseInt(fields[7], 10, 64); err == nil {
+		detail.CommitDate = time.Unix(secs, 0)
+	}
+	detail.Subject, _, _ = strings.Cut(detail.Message, "\n")
+
+	stats, err := r.Run(ctx, "show", "--no-color", "--format=", "--numstat", "-m", "--first-parent", rev)
+	if err != nil {
+		return CommitDetail{}, err
+	}
+	for _, line := range splitLines(stats) {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		stat := FileStat{}
+		stat.OrigPath, stat.Path = statPaths(parts[2])
+		if parts[0] == "-" && parts[1] == "-" {
+			stat.Binary = true
+		} else {
+			stat.Added, _ = strconv.Atoi(parts[0])
+			stat.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		detail.Files = append(detail.Files, stat)
+	}
+	return detail, nil
+}
+
+// statPaths splits the path numstat prints for a rename, "old => new" or
+// "dir/{old => new}/file", into its two sides.
+func statPaths(p string) (orig, path string) {
+	if open := strings.Index(p, "{"); open >= 0 {
+		if end := strings.Index(p[open:], "}"); end >= 0 {
+			end += open
+			if before, after, ok := strings.Cut(p[open+1:end], " => "); ok {
+				prefix, suffix := p[:open], p[end+1:]
+				join := func(mid string) string {
+					return strings.ReplaceAll(prefix+mid+suffix, "//", "/")
+				}
+				return join(before), join(after)
+			}
+		}
+	}
+	if before, after, ok := strings.Cut(p, " => "); ok {
+		return before, after
+	}
+	return "", p
+}
+
+// ShowFile returns the diff a commit made to one file.
+func (r *Repo) ShowFile(ctx context.Context, rev string, file FileStat) (FileDiff, error) {
+	args := []string{"show", "--no-color", "--no-ext-diff", "--format=", "-m", "--first-parent", rev, "--", file.Path}
+	if file.OrigPath != "" {
+		args = append(args, file.OrigPath)
+	}
+	out, err := r.Run(ctx, args...)
+	if err != nil {
+		return FileDiff{Path: file.Path}, err
+	}
+	diff := ParseDiff(out)
+	diff.Path = file.Path
+	return diff, nil
+}
+
+// BlameLine is one line of `git blame` output.
+type BlameLine struct {
+	Hash    string
+	Author  string
+	Date    time.Time
+	Summary string
+	LineNo  int
+	Text    string
+}
+
+// Blame annotates each line of path at rev; an empty rev blames the working
+// tree copy.
+func (r *Repo) Blame(ctx context.Context, rev, path string) ([]BlameLine, error) {
+	args := []string{"blame", "--porcelain"}
+	if rev != "" {
+		args = append(args, rev)
+	}
+	args = append(args, "--", path)
+	out, err := r.Run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlame(out), nil
+}
+
+func parseBlame(out string) []BlameLine {
+	type info struct {
+		author  string
+		date    time.Time
+		summary string
+	}
+	commits := make(map[string]*info)
+	var (
+		lines   []BlameLine
+		current BlameLine
+	)
+	for _, raw := range strings.Split(out, "\n") {
+		if strings.HasPrefix(raw, "\t") {
+			current.Text = raw[1:]
+			if c := commits[current.Hash]; c != nil {
+				current.Author, current.Date, current.Summary = c.author, c.date, c.summary
+			}
+			lines = append(lines, current)
+			current = BlameLine{}
+			continue
+		}
+		key, value, _ := strings.Cut(raw, " ")
+		if len(key) == 40 && current.Hash == "" {
+			fields := strings.Fields(value)
+			current.Hash = key
+			if len(fields) >= 2 {
+				current.LineNo, _ = strconv.Atoi(fields[1])
+			}
+			if commits[key] == nil {
+				commits[key] = &info{}
+			}
+			continue
+		}
+		c := commits[current.Hash]
+		if c == nil {
+			continue
+		}
+		switch key {
+		case "author":
+			c.author = value
+		case "author-time":
+			if secs, err := strconv.ParseInt(value, 10, 64); err == nil {
+				c.date = time.Unix(secs, 0)
+			}
+		case "summary":
+			c.summary = value
+		}
+	}
+	return lines
+}
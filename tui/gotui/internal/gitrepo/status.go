@@ -0,0 +1,106 @@
+package gitrepo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FileEntry is one path reported by git status.
+type FileEntry struct {
+	Path string
+	// OrigPath is the source of a rename or copy.
+	OrigPath string
+	// Index and Worktree are the porcelain status codes, e.g. 'M', 'A', '?'.
+	Index    byte
+	Worktree byte
+}
+
+// Untracked reports whether git does not track the file yet.
+func (e FileEntry) Untracked() bool { return e.Index == '?' }
+
+// Conflicted reports whether the file has unresolved merge conflicts.
+func (e FileEntry) Conflicted() bool {
+	switch string([]byte{e.Index, e.Worktree}) {
+	case "DD", "AU", "UD", "UA", "DU", "AA", "UU":
+		return true
+	}
+	return false
+}
+
+// Staged reports whether the index differs from HEAD for this file.
+func (e FileEntry) Staged() bool {
+	return e.Index != ' ' && e.Index != '?' && e.Index != '!' && !e.Conflicted()
+}
+
+// Unstaged reports whether the working tree differs from the index.
+func (e FileEntry) Unstaged() bool {
+	return e.Untracked() || e.Conflicted() || (e.Worktree != ' ' && e.Worktree != 0)
+}
+
+// Status is the parsed output of git status.
+type Status struct {
+	Branch   string
+	Upstream string
+	Ahead    int
+	Behind   int
+	Entries  []FileEntry
+}
+
+// Clean reports whether there is nothing to stage or commit.
+func (s Status) Clean() bool { return len(s.Entries) == 0 }
+
+// Status reads the working tree status.
+func (r *Repo) Status(ctx context.Context) (Status, error) {
+	out, err := r.Run(ctx, "status", "--porcelain=v1", "--branch", "-z", "--untracked-files=all")
+	if err != nil {
+		return Status{}, err
+	}
+	return parseStatus(out), nil
+}
+
+func parseStatus(out string) Status {
+	var status Status
+	fields := strings.Split(out, "\x00")
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if len(field) < 3 {
+			continue
+		}
+		if strings.HasPrefix(field, "## ") {
+			parseBranch(&status, field[3:])
+			continue
+		}
+		entry := FileEntry{Index: field[0], Worktree: field[1], Path: field[3:]}
+		if entry.Index == 'R' || entry.Index == 'C' {
+			if i+1 < len(fields) {
+				entry.OrigPath = fields[i+1]
+				i++
+			}
+		}
+		status.Entries = append(status.Entries, entry)
+	}
+	return status
+}
+
+// parseBranch reads "main...origin/main [ahead 1, behind 2]".
+func parseBranch(status *Status, line string) {
+	if idx := strings.Index(line, " ["); idx >= 0 {
+		counts := strings.TrimSuffix(line[idx+2:], "]")
+		line = line[:idx]
+		for _, part := range strings.Split(counts, ", ") {
+			var n int
+			if _, err := fmt.Sscanf(part, "ahead %d", &n); err == nil {
+				status.Ahead = n
+			} else if _, err := fmt.Sscanf(part, "behind %d", &n); err == nil {
+				status.Behind = n
+			}
+		}
+	}
+	line = strings.TrimPrefix(line, "No commits yet on ")
+	if branch, upstream, ok := strings.Cut(line, "..."); ok {
+		status.Branch, status.Upstream = branch, upstream
+		return
+	}
+	status.Branch = line
+}
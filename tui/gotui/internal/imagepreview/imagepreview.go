@@ -0,0 +1,310 @@
+// Package imagepreview draws image thumbnails in the chat with the kitty
+// graphics protocol where the terminal supports it, and with half-block
+// characters everywhere else. Images are decoded in the background; Render
+// only reads what is already cached.
+package imagepreview
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoders for image.Decode
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/charmbracelet/x/ansi/kitty"
+	_ "golang.org/x/image/webp"
+)
+
+// Preview protocols.
+const (
+	// ProtocolAuto picks the best protocol the terminal understands.
+	ProtocolAuto = "auto"
+	// ProtocolKitty uses kitty graphics with Unicode placeholders.
+	ProtocolKitty = "kitty"
+	// ProtocolBlocks draws with coloured half-block characters.
+	ProtocolBlocks = "blocks"
+	// ProtocolOff disables previews.
+	ProtocolOff = "off"
+)
+
+// Protocols lists every preview protocol.
+var Protocols = []string{ProtocolAuto, ProtocolKitty, ProtocolBlocks, ProtocolOff}
+
+// Terminal cells are assumed to be 10×20 pixels when sizing graphics.
+const (
+	cellWidth  = 10
+	cellHeight = 20
+)
+
+// maxCached bounds the rendered previews kept in memory.
+const maxCached = 64
+
+// DetectProtocol resolves ProtocolAuto from the terminal's environment.
+// Graphics need passthrough inside tmux and screen, so half blocks are used
+// there.
+func DetectProtocol(getenv func(string) string) string {
+	program := strings.ToLower(getenv("TERM_PROGRAM"))
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case getenv("TMUX") != "", strings.HasPrefix(term, "screen"), strings.HasPrefix(term, "tmux"):
+		return ProtocolBlocks
+	case getenv("KITTY_WINDOW_ID") != "", strings.Contains(term, "kitty"),
+		program == "ghostty", strings.Contains(term, "ghostty"):
+		return ProtocolKitty
+	}
+	return ProtocolBlocks
+}
+
+type cacheKey struct {
+	path     string
+	cols     int
+	rows     int
+	protocol string
+}
+
+// entry is a cached preview. Failures are cached too, so a broken image is
+// decoded once rather than on every frame.
+type entry struct {
+	lines   []string
+	err     error
+	loading bool
+}
+
+var (
+	mu       sync.Mutex
+	protocol = ProtocolBlocks
+	cache    = make(map[cacheKey]*entry)
+	lastID   int
+	// pending holds kitty transmissions not yet written to the terminal.
+	pending bytes.Buffer
+	ready   = make(chan struct{}, 1)
+)
+
+// SetProtocol selects how previews are drawn. ProtocolAuto detects it from
+// the environment.
+func SetProtocol(p string) {
+	if p == ProtocolAuto || p == "" {
+		p = DetectProtocol(os.Getenv)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if p != protocol {
+		protocol = p
+		clear(cache)
+		pending.Reset()
+	}
+}
+
+// Protocol returns the protocol previews are drawn with.
+func Protocol() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return protocol
+}
+
+// Ready receives a value whenever a preview finished loading, so the UI can
+// redraw and write the Transmissions.
+func Ready() <-chan struct{} {
+	return ready
+}
+
+// Transmissions returns the kitty image data loaded since the last call.
+// It must be written to the terminal outside the rendered frame; the
+// previews only hold placeholders referring to it.
+func Transmissions() string {
+	mu.Lock()
+	defer mu.Unlock()
+	data := pending.String()
+	pending.Reset()
+	return data
+}
+
+// Render returns the preview of the image at path within cols×rows cells,
+// followed by caption. An image not loaded yet is loaded in the background
+// and only the caption is returned until it is ready. The error of an image
+// that cannot be decoded is returned on every call.
+func Render(path string, cols, rows int, caption string) ([]string, error) {
+	mu.Lock()
+	current := protocol
+	if current == ProtocolOff || cols <= 0 || rows <= 0 {
+		mu.Unlock()
+		return []string{caption}, nil
+	}
+	key := cacheKey{path: path, cols: cols, rows: rows, protocol: current}
+	cached, ok := cache[key]
+	if !ok {
+		if len(cache) >= maxCached {
+			clear(cache)
+		}
+		cached = &entry{loading: true}
+		cache[key] = cached
+		go load(key)
+	}
+	lines, err, loading := cached.lines, cached.err, cached.loading
+	mu.Unlock()
+
+	if loading {
+		return []string{caption}, nil
+	}
+	if err != nil {
+		return []string{caption}, err
+	}
+	return append(append([]string(nil), lines...), caption), nil
+}
+
+func load(key cacheKey) {
+	lines, transmit, err := render(key.path, key.cols, key.rows, key.protocol)
+	mu.Lock()
+	if cached, ok := cache[key]; ok {
+		*cached = entry{lines: lines, err: err}
+		pending.Write(transmit)
+	}
+	mu.Unlock()
+	select {
+	case ready <- struct{}{}:
+	default:
+	}
+}
+
+func render(path string, cols, rows int, protocol string) ([]string, []byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() <= 0 || bounds.Dy() <= 0 {
+		return nil, nil, errors.New("empty image")
+	}
+
+	if protocol == ProtocolKitty {
+		return renderKitty(img, cols, rows)
+	}
+	return renderBlocks(img, cols, rows), nil, nil
+}
+
+// fit returns the cells an image of w×h pixels covers within cols×rows,
+// keeping its aspect ratio with cells twice as tall as wide. Images are not
+// enlarged beyond pixelsPerCol pixels per column.
+func fit(w, h, cols, rows int, pixelsPerCol float64) (int, int) {
+	scale := math.Min(float64(cols)/float64(w), float64(rows*2)/float64(h))
+	scale = math.Min(scale, 1/pixelsPerCol)
+	c := max(1, int(math.Round(float64(w)*scale)))
+	r := max(1, int(math.Round(float64(h)*scale/2)))
+	return min(c, cols), min(r, rows)
+}
+
+// resize scales img to w×h by averaging the source pixels under each target
+// pixel.
+func resize(img image.Image, w, h int) *image.RGBA {
+	bounds := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	sx := float64(bounds.Dx()) / float64(w)
+	sy := float64(bounds.Dy()) / float64(h)
+	for y := 0; y < h; y++ {
+		y0 := bounds.Min.Y + int(float64(y)*sy)
+		y1 := max(y0+1, bounds.Min.Y+int(float64(y+1)*sy))
+		for x := 0; x < w; x++ {
+			x0 := bounds.Min.X + int(float64(x)*sx)
+			x1 := max(x0+1, bounds.Min.X+int(float64(x+1)*sx))
+			var r, g, b, a, n uint64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					cr, cg, cb, ca := img.At(px, py).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			out.SetRGBA(x, y, color.RGBA{R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: uint8(a / n >> 8)})
+		}
+	}
+	return out
+}
+
+// renderBlocks draws two pixels per cell with the upper half block, the top
+// pixel as foreground and the bottom one as background.
+func renderBlocks(img image.Image, cols, rows int) []string {
+	bounds := img.Bounds()
+	c, r := fit(bounds.Dx(), bounds.Dy(), cols, rows, 1)
+	small := resize(img, c, r*2)
+	lines := make([]string, 0, r+1)
+	for y := 0; y < r; y++ {
+		var b strings.Builder
+		for x := 0; x < c; x++ {
+			top, bottom := small.RGBAAt(x, y*2), small.RGBAAt(x, y*2+1)
+			switch {
+			case top.A < 128 && bottom.A < 128:
+				b.WriteString("\x1b[0m ")
+			case top.A < 128:
+				fmt.Fprintf(&b, "\x1b[0;38;2;%d;%d;%dm▄", bottom.R, bottom.G, bottom.B)
+			case bottom.A < 128:
+				fmt.Fprintf(&b, "\x1b[0;38;2;%d;%d;%dm▀", top.R, top.G, top.B)
+			default:
+				fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%d;48;2;%d;%d;%dm▀", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B)
+			}
+		}
+		b.WriteString("\x1b[0m")
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// renderKitty encodes the image for a virtual placement and draws it with
+// Unicode placeholders, which scroll and clip like text. The transmission is
+// returned separately so it is sent once rather than with every frame. The
+// placeholders' foreground colour carries the image ID, so IDs cycle through
+// the 255 indexed colours.
+func renderKitty(img image.Image, cols, rows int) ([]string, []byte, error) {
+	bounds := img.Bounds()
+	c, r := fit(bounds.Dx(), bounds.Dy(), cols, rows, cellWidth)
+	if w, h := c*cellWidth, r*cellHeight; bounds.Dx() > w || bounds.Dy() > h {
+		img = resize(img, min(w, bounds.Dx()), min(h, bounds.Dy()))
+	}
+
+	mu.Lock()
+	lastID = lastID%255 + 1
+	id := lastID
+	mu.Unlock()
+
+	var transmit bytes.Buffer
+	err := ansi.EncodeKittyGraphics(&transmit, img, &kitty.Options{
+		Action:           kitty.TransmitAndPut,
+		Transmission:     kitty.Direct,
+		Format:           kitty.PNG,
+		ID:               id,
+		Columns:          c,
+		Rows:             r,
+		VirtualPlacement: true,
+		Quite:            2,
+		Chunk:            true,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := make([]string, 0, r)
+	for y := 0; y < r; y++ {
+		var b strings.Builder
+		fmt.Fprintf(&b, "\x1b[38;5;%dm", id)
+		for x := 0; x < c; x++ {
+			b.WriteRune(kitty.Placeholder)
+			b.WriteRune(kitty.Diacritic(y))
+			b.WriteRune(kitty.Diacritic(x))
+		}
+		b.WriteString("\x1b[39m")
+		lines = append(lines, b.String())
+	}
+	return lines, transmit.Bytes(), nil
+}
@@ -0,0 +1,211 @@
+package keybindings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/v2/key"
+)
+
+// Scope describes where a binding is active. Bindings only conflict with other
+// bindings in the same scope or with global bindings. Window keys take every
+// key but quit and window_keys while they are on, so window bindings only
+// conflict with each other and those two.
+type Scope string
+
+const (
+	ScopeGlobal Scope = "global"
+	ScopeChat   Scope = "chat"
+	ScopeLogs   Scope = "logs"
+	ScopeGit    Scope = "git"
+	ScopeWindow Scope = "window"
+)
+
+// windowKeysPassThrough are the actions that still work while window keys
+// are on.
+var windowKeysPassThrough = map[string]bool{"quit": true, "window_keys": true}
+
+// Action names a KeyMap field so it can be referenced from keymap files.
+type Action struct {
+	Name    string
+	Scope   Scope
+	binding func(*KeyMap) *key.Binding
+}
+
+// Binding returns a pointer to the action's binding inside km.
+func (a Action) Binding(km *KeyMap) *key.Binding {
+	return a.binding(km)
+}
+
+var actions = []Action{
+	{"submit", ScopeChat, func(k *KeyMap) *key.Binding { return &k.Submit }},
+	{"newline", ScopeChat, func(k *KeyMap) *key.Binding { return &k.Newline }},
+	{"quit", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.Quit }},
+	{"retry", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.Retry }},
+	{"focus_chat", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.FocusChat }},
+	{"show_commands", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.ShowCommands }},
+	{"toggle_mode", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.ToggleMode }},
+	{"toggle_auto_tile", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.ToggleAutoTile }},
+	{"window_keys", ScopeChat, func(k *KeyMap) *key.Binding { return &k.WindowKeys }},
+	{"scroll_up", ScopeChat, func(k *KeyMap) *key.Binding { return &k.ScrollUp }},
+	{"scroll_down", ScopeChat, func(k *KeyMap) *key.Binding { return &k.ScrollDown }},
+	{"next_tab", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.NextTab }},
+	{"prev_tab", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.PrevTab }},
+	{"tab_chat", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.TabChat }},
+	{"tab_logs", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.TabLogs }},
+	{"tab_git", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.TabGit }},
+	{"toggle_status", ScopeLogs, func(k *KeyMap) *key.Binding { return &k.ToggleStatus }},
+	{"toggle_logs", ScopeLogs, func(k *KeyMap) *key.Binding { return &k.ToggleLogs }},
+	{"toggle_server", ScopeLogs, func(k *KeyMap) *key.Binding { return &k.ToggleServer }},
+	{"toggle_agent", ScopeLogs, func(k *KeyMap) *key.Binding { return &k.ToggleAgent }},
+	{"toggle_notifs", ScopeLogs, func(k *KeyMap) *key.Binding { return &k.ToggleNotifs }},
+	{"undo_change", ScopeChat, func(k *KeyMap) *key.Binding { return &k.UndoChange }},
+	{"history_search", ScopeChat, func(k *KeyMap) *key.Binding { return &k.HistorySearch }},
+	{"compose_editor", ScopeChat, func(k *KeyMap) *key.Binding { return &k.ComposeEditor }},
+	{"paste_image", ScopeChat, func(k *KeyMap) *key.Binding { return &k.PasteImage }},
+	{"git_commit", ScopeGit, func(k *KeyMap) *key.Binding { return &k.GitCommit }},
+	{"git_draft", ScopeGit, func(k *KeyMap) *key.Binding { return &k.GitDraft }},
+	{"help", ScopeGlobal, func(k *KeyMap) *key.Binding { return &k.Help }},
+	{"window_move_left", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowMoveLeft }},
+	{"window_move_right", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowMoveRight }},
+	{"window_move_up", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowMoveUp }},
+	{"window_move_down", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowMoveDown }},
+	{"window_resize_left", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowResizeLeft }},
+	{"window_resize_right", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowResizeRight }},
+	{"window_resize_up", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowResizeUp }},
+	{"window_resize_down", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowResizeDown }},
+	{"window_snap_left", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapLeft }},
+	{"window_snap_right", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapRight }},
+	{"window_snap_top", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapTop }},
+	{"window_snap_bottom", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapBottom }},
+	{"window_snap_top_left", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapTopLeft }},
+	{"window_snap_top_right", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapTopRight }},
+	{"window_snap_bottom_left", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapBottomLeft }},
+	{"window_snap_bottom_right", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSnapBottomRight }},
+	{"window_maximise", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowMaximise }},
+	{"window_swap_next", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSwapNext }},
+	{"window_swap_prev", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowSwapPrev }},
+	{"window_focus_next", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowFocusNext }},
+	{"window_focus_prev", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowFocusPrev }},
+	{"window_close", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowClose }},
+	{"window_reopen", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowReopen }},
+	{"window_done", ScopeWindow, func(k *KeyMap) *key.Binding { return &k.WindowDone }},
+}
+
+// Actions lists every configurable action in KeyMap field order.
+func Actions() []Action {
+	out := make([]Action, len(actions))
+	copy(out, actions)
+	return out
+}
+
+// ActionByName looks up an action by its keymap file name.
+func ActionByName(name string) (Action, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	normalized = strings.ReplaceAll(normalized, "-", "_")
+	for _, action := range actions {
+		if action.Name == normalized {
+			return action, true
+		}
+	}
+	return Action{}, false
+}
+
+// SetKeys rebinds b to keys while keeping its description. An empty key list
+// disables the binding.
+func SetKeys(b *key.Binding, keys []string) {
+	desc := b.Help().Desc
+	if len(keys) == 0 {
+		*b = key.NewBinding(key.WithDisabled(), key.WithHelp("", desc))
+		return
+	}
+	*b = key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, "/"), desc))
+}
+
+// Conflict records a key that triggers more than one action in overlapping scopes.
+type Conflict struct {
+	Key     string
+	Actions []string
+}
+
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s is bound to %s", c.Key, strings.Join(c.Actions, ", "))
+}
+
+// reservedKeys are intercepted by common terminals or shells before they reach
+// the application, or are indistinguishable from another key.
+var reservedKeys = map[string]string{
+	"ctrl+s":  "XOFF flow control in many terminals",
+	"ctrl+q":  "XON flow control in many terminals",
+	"ctrl+[":  "sent as esc by most terminals",
+	"ctrl+i":  "sent as tab by most terminals",
+	"ctrl+m":  "sent as enter by most terminals",
+	"ctrl+h":  "sent as backspace by some terminals",
+	"ctrl+z":  "suspends the process in most shells",
+	"ctrl+\\": "sends SIGQUIT in most shells",
+}
+
+// DetectConflicts reports keys shared by actions whose scopes overlap.
+func DetectConflicts(km KeyMap) []Conflict {
+	type owner struct {
+		name  string
+		scope Scope
+	}
+	owners := make(map[string][]owner)
+	for _, action := range actions {
+		b := action.Binding(&km)
+		if !b.Enabled() {
+			continue
+		}
+		for _, k := range b.Keys() {
+			owners[k] = append(owners[k], owner{action.Name, action.Scope})
+		}
+	}
+
+	var conflicts []Conflict
+	for k, list := range owners {
+		var names []string
+		for i, a := range list {
+			for j, b := range list {
+				if i == j {
+					continue
+				}
+				if scopesOverlap(a.name, a.scope, b.name, b.scope) {
+					names = append(names, a.name)
+					break
+				}
+			}
+		}
+		if len(names) > 1 {
+			conflicts = append(conflicts, Conflict{Key: k, Actions: names})
+		}
+	}
+	sort.Slice(conflicts, func(i, j int) bool { return conflicts[i].Key < conflicts[j].Key })
+	return conflicts
+}
+
+// scopesOverlap reports whether the same key press can reach both actions.
+func scopesOverlap(nameA string, a Scope, nameB string, b Scope) bool {
+	if a == ScopeWindow || b == ScopeWindow {
+		return a == b || windowKeysPassThrough[nameA] || windowKeysPassThrough[nameB]
+	}
+	return a == b || a == ScopeGlobal || b == ScopeGlobal
+}
+
+// ReservedKeyWarnings reports bindings that terminals are likely to swallow.
+func ReservedKeyWarnings(km KeyMap) []string {
+	var warnings []string
+	for _, action := range actions {
+		b := action.Binding(&km)
+		if !b.Enabled() {
+			continue
+		}
+		for _, k := range b.Keys() {
+			if reason, ok := reservedKeys[k]; ok {
+				warnings = append(warnings, fmt.Sprintf("%s uses %s (%s)", action.Name, k, reason))
+			}
+		}
+	}
+	return warnings
+}
@@ -12,6 +12,7 @@ type KeyMap struct {
 	ShowCommands   key.Binding
 	ToggleMode     key.Binding
 	ToggleAutoTile key.Binding
+	WindowKeys     key.Binding
 	ScrollUp       key.Binding
 	ScrollDown     key.Binding
 	NextTab        key.Binding
@@ -24,7 +25,39 @@ type KeyMap struct {
 	ToggleServer   key.Binding
 	ToggleAgent    key.Binding
 	ToggleNotifs   key.Binding
+	UndoChange     key.Binding
+	HistorySearch  key.Binding
+	ComposeEditor  key.Binding
+	PasteImage     key.Binding
+	GitCommit      key.Binding
+	GitDraft       key.Binding
 	Help           key.Binding
+
+	// Window keys, active while WindowKeys is on.
+	WindowMoveLeft        key.Binding
+	WindowMoveRight       key.Binding
+	WindowMoveUp          key.Binding
+	WindowMoveDown        key.Binding
+	WindowResizeLeft      key.Binding
+	WindowResizeRight     key.Binding
+	WindowResizeUp        key.Binding
+	WindowResizeDown      key.Binding
+	WindowSnapLeft        key.Binding
+	WindowSnapRight       key.Binding
+	WindowSnapTop         key.Binding
+	WindowSnapBottom      key.Binding
+	WindowSnapTopLeft     key.Binding
+	WindowSnapTopRight    key.Binding
+	WindowSnapBottomLeft  key.Binding
+	WindowSnapBottomRight key.Binding
+	WindowMaximise        key.Binding
+	WindowSwapNext        key.Binding
+	WindowSwapPrev        key.Binding
+	WindowFocusNext       key.Binding
+	WindowFocusPrev       key.Binding
+	WindowClose           key.Binding
+	WindowReopen          key.Binding
+	WindowDone            key.Binding
 }
 
 // DefaultKeyMap provides the default keyboard shortcuts used elsewhere.
@@ -32,24 +65,56 @@ func DefaultKeyMap() KeyMap {
 	return KeyMap{
 		Submit:         key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "send message")),
 		Newline:        key.NewBinding(key.WithKeys("ctrl+j"), key.WithHelp("ctrl+j", "new line")),
-		Quit:           key.NewBinding(key.WithKeys("ctrl+c", "ctrl+q"), key.WithHelp("ctrl+c", "quit")),
+		Quit:           key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
 		Retry:          key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "retry connection")),
 		FocusChat:      key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "focus chat/scroll")),
 		ShowCommands:   key.NewBinding(key.WithKeys("ctrl+k"), key.WithHelp("ctrl+k", "commands")),
 		ToggleMode:     key.NewBinding(key.WithKeys("ctrl+t"), key.WithHelp("ctrl+t", "toggle layout mode")),
 		ToggleAutoTile: key.NewBinding(key.WithKeys("ctrl+u"), key.WithHelp("ctrl+u", "toggle auto-tiling")),
+		WindowKeys:     key.NewBinding(key.WithKeys("alt+w"), key.WithHelp("alt+w", "move/resize windows")),
 		NextTab:        key.NewBinding(key.WithKeys("ctrl+]"), key.WithHelp("ctrl+]", "next tab")),
-		PrevTab:        key.NewBinding(key.WithKeys("ctrl+["), key.WithHelp("ctrl+[", "prev tab")),
+		PrevTab:        key.NewBinding(key.WithKeys("alt+["), key.WithHelp("alt+[", "prev tab")),
 		TabChat:        key.NewBinding(key.WithKeys("ctrl+1", "shift+1"), key.WithHelp("ctrl+1/shift+1", "chat tab")),
 		TabLogs:        key.NewBinding(key.WithKeys("ctrl+2", "shift+2"), key.WithHelp("ctrl+2/shift+2", "logs tab")),
 		TabGit:         key.NewBinding(key.WithKeys("ctrl+3", "shift+3"), key.WithHelp("ctrl+3/shift+3", "git tab")),
 		ScrollUp:       key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "scroll up")),
 		ScrollDown:     key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "scroll down")),
-		ToggleStatus:   key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "toggle connection")),
+		ToggleStatus:   key.NewBinding(key.WithKeys("alt+s"), key.WithHelp("alt+s", "toggle connection")),
 		ToggleLogs:     key.NewBinding(key.WithKeys("ctrl+l"), key.WithHelp("ctrl+l", "toggle logs")),
 		ToggleServer:   key.NewBinding(key.WithKeys("ctrl+v"), key.WithHelp("ctrl+v", "toggle server")),
 		ToggleAgent:    key.NewBinding(key.WithKeys("ctrl+a"), key.WithHelp("ctrl+a", "toggle agent")),
 		ToggleNotifs:   key.NewBinding(key.WithKeys("ctrl+n"), key.WithHelp("ctrl+n", "toggle notifications")),
-		Help:           key.NewBinding(key.WithKeys("?", "ctrl+h"), key.WithHelp("?", "toggle help")),
+		UndoChange:     key.NewBinding(key.WithKeys("alt+z"), key.WithHelp("alt+z", "undo last agent change")),
+		HistorySearch:  key.NewBinding(key.WithKeys("alt+r"), key.WithHelp("alt+r", "search prompt history")),
+		ComposeEditor:  key.NewBinding(key.WithKeys("ctrl+o"), key.WithHelp("ctrl+o", "compose in $EDITOR")),
+		PasteImage:     key.NewBinding(key.WithKeys("alt+v"), key.WithHelp("alt+v", "paste clipboard image")),
+		GitCommit:      key.NewBinding(key.WithKeys("alt+s"), key.WithHelp("alt+s", "commit / copy description")),
+		GitDraft:       key.NewBinding(key.WithKeys("alt+g"), key.WithHelp("alt+g", "draft with agent")),
+		Help:           key.NewBinding(key.WithKeys("?", "f1"), key.WithHelp("?", "toggle help")),
+
+		WindowMoveLeft:        key.NewBinding(key.WithKeys("left", "h"), key.WithHelp("←/h", "move window left")),
+		WindowMoveRight:       key.NewBinding(key.WithKeys("right", "l"), key.WithHelp("→/l", "move window right")),
+		WindowMoveUp:          key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "move window up")),
+		WindowMoveDown:        key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "move window down")),
+		WindowResizeLeft:      key.NewBinding(key.WithKeys("shift+left", "H"), key.WithHelp("shift+←/H", "narrow window")),
+		WindowResizeRight:     key.NewBinding(key.WithKeys("shift+right", "L"), key.WithHelp("shift+→/L", "widen window")),
+		WindowResizeUp:        key.NewBinding(key.WithKeys("shift+up", "K"), key.WithHelp("shift+↑/K", "shorten window")),
+		WindowResizeDown:      key.NewBinding(key.WithKeys("shift+down", "J"), key.WithHelp("shift+↓/J", "lengthen window")),
+		WindowSnapLeft:        key.NewBinding(key.WithKeys("4"), key.WithHelp("4", "snap window to left half")),
+		WindowSnapRight:       key.NewBinding(key.WithKeys("6"), key.WithHelp("6", "snap window to right half")),
+		WindowSnapTop:         key.NewBinding(key.WithKeys("8"), key.WithHelp("8", "snap window to top half")),
+		WindowSnapBottom:      key.NewBinding(key.WithKeys("2"), key.WithHelp("2", "snap window to bottom half")),
+		WindowSnapTopLeft:     key.NewBinding(key.WithKeys("7"), key.WithHelp("7", "snap window to top-left quarter")),
+		WindowSnapTopRight:    key.NewBinding(key.WithKeys("9"), key.WithHelp("9", "snap window to top-right quarter")),
+		WindowSnapBottomLeft:  key.NewBinding(key.WithKeys("1"), key.WithHelp("1", "snap window to bottom-left quarter")),
+		WindowSnapBottomRight: key.NewBinding(key.WithKeys("3"), key.WithHelp("3", "snap window to bottom-right quarter")),
+		WindowMaximise:        key.NewBinding(key.WithKeys("m", "5"), key.WithHelp("m/5", "maximise / restore window")),
+		WindowSwapNext:        key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "swap with next window")),
+		WindowSwapPrev:        key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "swap with previous window")),
+		WindowFocusNext:       key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "focus next window")),
+		WindowFocusPrev:       key.NewBinding(key.WithKeys("shift+tab"), key.WithHelp("shift+tab", "focus previous window")),
+		WindowClose:           key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "close window")),
+		WindowReopen:          key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "reopen closed windows")),
+		WindowDone:            key.NewBinding(key.WithKeys("esc", "enter", "q"), key.WithHelp("esc", "leave window keys")),
 	}
 }
@@ -0,0 +1,219 @@
+package keybindings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gotui/internal/configfile"
+)
+
+// Preset names accepted in keymap files.
+const (
+	PresetDefault = "default"
+	PresetVim     = "vim"
+	PresetEmacs   = "emacs"
+)
+
+// presetOverrides lists the bindings each preset changes relative to the defaults.
+var presetOverrides = map[string]map[string][]string{
+	PresetDefault: {},
+	PresetVim: {
+		"scroll_up":     {"k", "up", "ctrl+y"},
+		"scroll_down":   {"j", "down", "ctrl+e"},
+		"next_tab":      {"alt+l", "ctrl+]"},
+		"prev_tab":      {"alt+h", "alt+["},
+		"tab_chat":      {"alt+1"},
+		"tab_logs":      {"alt+2"},
+		"tab_git":       {"alt+3"},
+		"show_commands": {"alt+;", "ctrl+k"},
+		"toggle_logs":   {"alt+o"},
+		"undo_change":   {"alt+u", "alt+z"},
+	},
+	PresetEmacs: {
+		"scroll_up":      {"alt+p", "up"},
+		"scroll_down":    {"alt+n", "down"},
+		"next_tab":       {"alt+f", "ctrl+]"},
+		"prev_tab":       {"alt+b", "alt+["},
+		"show_commands":  {"alt+x", "ctrl+k"},
+		"quit":           {"ctrl+c"},
+		"newline":        {"ctrl+j", "alt+enter"},
+		"retry":          {"alt+r"},
+		"history_search": {"ctrl+r"},
+		"toggle_notifs":  {"alt+m"},
+		"toggle_agent":   {"alt+a"},
+		"undo_change":    {"ctrl+_", "alt+z"},
+		"window_keys":    {"ctrl+x"},
+	},
+}
+
+// PresetNames returns the available preset names.
+func PresetNames() []string {
+	return []string{PresetDefault, PresetVim, PresetEmacs}
+}
+
+// PresetKeyMap returns the key map for a named preset.
+func PresetKeyMap(name string) (KeyMap, bool) {
+	overrides, ok := presetOverrides[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return KeyMap{}, false
+	}
+	km := DefaultKeyMap()
+	for actionName, keys := range overrides {
+		if action, found := ActionByName(actionName); found {
+			SetKeys(action.Binding(&km), keys)
+		}
+	}
+	return km, true
+}
+
+// File is the on-disk keymap format, e.g.
+//
+//	preset = "vim"
+//	[bindings]
+//	next_tab = ["alt+l", "ctrl+]"]
+//	toggle_status = "alt+s"
+//	toggle_server = []   # disable
+type File struct {
+	Preset   string                 `json:"preset,omitempty"`
+	Bindings map[string]interface{} `json:"bindings,omitempty"`
+}
+
+// Report summarises how the effective key map was built so it can be shown at startup.
+type Report struct {
+	Source    string
+	Preset    string
+	Overrides []string
+	Conflicts []Conflict
+	Warnings  []string
+	Errors    []string
+}
+
+// Lines renders the report as human-readable log lines; problems are left
+// to ErrorLines and WarningLines.
+func (r Report) Lines() []string {
+	var lines []string
+	source := r.Source
+	if source == "" {
+		source = "built-in defaults"
+	}
+	lines = append(lines, fmt.Sprintf("⌨️  Keymap: %s preset from %s", r.Preset, source))
+	if len(r.Overrides) > 0 {
+		lines = append(lines, fmt.Sprintf("⌨️  Overridden: %s", strings.Join(r.Overrides, ", ")))
+	}
+	return lines
+}
+
+// ErrorLines renders the keymap errors as log lines.
+func (r Report) ErrorLines() []string {
+	var lines []string
+	for _, err := range r.Errors {
+		lines = append(lines, "❌ Keymap error: "+err)
+	}
+	return lines
+}
+
+// WarningLines renders key conflicts and warnings as log lines.
+func (r Report) WarningLines() []string {
+	var lines []string
+	for _, conflict := range r.Conflicts {
+		lines = append(lines, "⚠️  Key conflict: "+conflict.String())
+	}
+	for _, warning := range r.Warnings {
+		lines = append(lines, "⚠️  Key warning: "+warning)
+	}
+	return lines
+}
+
+// HasProblems reports whether the report contains errors or conflicts.
+func (r Report) HasProblems() bool {
+	return len(r.Errors) > 0 || len(r.Conflicts) > 0
+}
+
+// UserKeymapPath returns the keymap file in the user config directory, if any.
+func UserKeymapPath() string {
+	return configfile.FindFile(configfile.UserDir(), "keymap")
+}
+
+// Load builds the effective key map from an optional keymap file. A missing
+// path yields the defaults; problems in the file are reported rather than fatal.
+func Load(path string) (KeyMap, Report) {
+	var file File
+	report := Report{Source: path, Preset: PresetDefault}
+	if path != "" {
+		if err := configfile.Decode(path, &file); err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	}
+	return Apply(file, report)
+}
+
+// Apply layers a keymap file onto its preset and validates the result.
+func Apply(file File, report Report) (KeyMap, Report) {
+	preset := strings.ToLower(strings.TrimSpace(file.Preset))
+	if preset == "" {
+		preset = PresetDefault
+	}
+	km, ok := PresetKeyMap(preset)
+	if !ok {
+		report.Errors = append(report.Errors, fmt.Sprintf("unknown preset %q (available: %s)", file.Preset, strings.Join(PresetNames(), ", ")))
+		km = DefaultKeyMap()
+		preset = PresetDefault
+	}
+	report.Preset = preset
+
+	names := make([]string, 0, len(file.Bindings))
+	for name := range file.Bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		action, found := ActionByName(name)
+		if !found {
+			report.Errors = append(report.Errors, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		keys, err := keyList(file.Bindings[name])
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		SetKeys(action.Binding(&km), keys)
+		report.Overrides = append(report.Overrides, action.Name)
+	}
+
+	report.Conflicts = DetectConflicts(km)
+	report.Warnings = ReservedKeyWarnings(km)
+	return km, report
+}
+
+func keyList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return nil, nil
+		}
+		return []string{normalizeKey(v)}, nil
+	case []interface{}:
+		keys := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected key string, got %v", item)
+			}
+			if strings.TrimSpace(s) != "" {
+				keys = append(keys, normalizeKey(s))
+			}
+		}
+		return keys, nil
+	default:
+		return nil, fmt.Errorf("expected key string or list, got %v", value)
+	}
+}
+
+func normalizeKey(k string) string {
+	return strings.ReplaceAll(strings.TrimSpace(k), " ", "")
+}
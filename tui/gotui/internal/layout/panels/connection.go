@@ -20,6 +20,8 @@ type ConnectionPanel struct {
 	retryCount int
 	isRetrying bool
 	lastError  string
+	authMode   string
+	authError  string
 }
 
 func NewConnection(wsClient *wsclient.Client, host string, port int) *ConnectionPanel {
@@ -43,6 +45,23 @@ func (c *ConnectionPanel) SetRetryInfo(count int, retrying bool, lastError strin
 	c.lastError = lastError
 }
 
+// SetTarget points the panel at a new client and server address, clearing the
+// retry state of the previous connection.
+func (c *ConnectionPanel) SetTarget(wsClient *wsclient.Client, host string, port int) {
+	c.wsClient = wsClient
+	c.host = host
+	c.port = port
+	c.SetRetryInfo(0, false, "")
+	c.authError = ""
+}
+
+// SetAuth records how the client authenticates, e.g. "bearer token".
+func (c *ConnectionPanel) SetAuth(mode string) { c.authMode = mode }
+
+// SetAuthError marks the connection as rejected by the server; an empty
+// message clears the state.
+func (c *ConnectionPanel) SetAuthError(message string) { c.authError = message }
+
 func (c *ConnectionPanel) View() string {
 	// Rebuild content dynamically on each render
 	c.panel.Clear()
@@ -54,9 +73,22 @@ func (c *ConnectionPanel) View() string {
 	// Connection info
 	c.panel.AddLine(fmt.Sprintf("🖥️  Host: %s", c.host))
 	c.panel.AddLine(fmt.Sprintf("🔌 Port: %d", c.port))
+	if c.authMode != "" {
+		c.panel.AddLine(fmt.Sprintf("🔐 Auth: %s", c.authMode))
+	}
 	c.panel.AddLine("")
 
 	// Connection status
+	if c.authError != "" && (c.wsClient == nil || !c.wsClient.IsConnected()) {
+		c.panel.AddLine("⛔ Status: Authentication failed")
+		for _, line := range strings.Split(c.authError, "\n") {
+			c.panel.AddLine(line)
+		}
+		c.panel.AddLine("🔑 Check the profile token, then press Ctrl+R")
+		c.panel.AddLine("")
+		c.panel.AddLine(fmt.Sprintf("Updated: %s", time.Now().Format("15:04:05")))
+		return c.panel.View()
+	}
 	if c.wsClient != nil && c.wsClient.IsConnected() {
 		c.panel.AddLine("🟢 Status: Connected")
 		c.panel.AddLine("⚡ WebSocket: Active")
@@ -0,0 +1,166 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/styles"
+)
+
+// uncommittedHash is the hash git blame reports for lines not yet committed.
+const uncommittedHash = "0000000000000000000000000000000000000000"
+
+// GitBlamePanel annotates each line of a file with the commit that last
+// changed it.
+type GitBlamePanel struct {
+	width    int
+	height   int
+	active   bool
+	rev      string
+	path     string
+	lines    []gitrepo.BlameLine
+	selected int
+	offset   int
+	message  string
+}
+
+// NewGitBlamePanel creates an empty blame view.
+func NewGitBlamePanel() *GitBlamePanel {
+	return &GitBlamePanel{}
+}
+
+func (g *GitBlamePanel) SetSize(width, height int) { g.width, g.height = width, height }
+func (g *GitBlamePanel) SetActive(active bool)     { g.active = active }
+
+// SetLoading clears the view while path is blamed at rev; an empty rev is the
+// working tree.
+func (g *GitBlamePanel) SetLoading(rev, path string) {
+	g.rev = rev
+	g.path = path
+	g.lines = nil
+	g.selected = 0
+	g.offset = 0
+	g.message = "Loading blame…"
+}
+
+// Shows reports whether the panel is showing, or loading, path at rev.
+func (g *GitBlamePanel) Shows(rev, path string) bool {
+	return g.rev == rev && g.path == path
+}
+
+// SetBlame shows the annotated lines of the file.
+func (g *GitBlamePanel) SetBlame(lines []gitrepo.BlameLine) {
+	g.lines = lines
+	g.message = ""
+	if len(lines) == 0 {
+		g.message = "File is empty."
+	}
+	g.selected = clampIndex(g.selected, len(lines))
+}
+
+// SetMessage replaces the blame with message, e.g. an error.
+func (g *GitBlamePanel) SetMessage(message string) {
+	g.lines = nil
+	g.message = message
+}
+
+// Selected returns the blame of the highlighted line.
+func (g *GitBlamePanel) Selected() (gitrepo.BlameLine, bool) {
+	if g.selected < 0 || g.selected >= len(g.lines) {
+		return gitrepo.BlameLine{}, false
+	}
+	return g.lines[g.selected], true
+}
+
+// Move shifts the line cursor by delta.
+func (g *GitBlamePanel) Move(delta int) {
+	g.selected = clampIndex(g.selected+delta, len(g.lines))
+}
+
+// PageSize returns the number of lines visible at once.
+func (g *GitBlamePanel) PageSize() int {
+	if rows := g.height - 3; rows > 1 {
+		return rows
+	}
+	return 1
+}
+
+// Committed reports whether line belongs to a commit rather than to
+// uncommitted changes in the working tree.
+func Committed(line gitrepo.BlameLine) bool {
+	return line.Hash != "" && line.Hash != uncommittedHash
+}
+
+// View renders the blame.
+func (g *GitBlamePanel) View() string {
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	title := "Blame · " + g.path
+	if g.rev != "" {
+		title += " @ " + shortHashes([]string{g.rev})[0]
+	}
+	if g.message != "" {
+		return frameLines(title, []string{muted.Render(g.message)}, g.width, g.height, g.active)
+	}
+
+	hashStyle := lipgloss.NewStyle().Foreground(theme.Accent)
+	authorStyle := lipgloss.NewStyle().Foreground(theme.Secondary)
+	numberWidth := len(fmt.Sprint(len(g.lines)))
+	authorWidth := 12
+
+	rows := g.height - 3
+	g.offset = scrollWindow(g.offset, g.selected, rows, len(g.lines))
+	end := g.offset + rows
+	if end > len(g.lines) {
+		end = len(g.lines)
+	}
+
+	out := make([]string, 0, end-g.offset)
+	previous := ""
+	if g.offset > 0 {
+		previous = g.lines[g.offset-1].Hash
+	}
+	for i := g.offset; i < end; i++ {
+		line := g.lines[i]
+		// Only the first line of a run from the same commit is annotated.
+		annotation := strings.Repeat(" ", 8+1+authorWidth+1+10)
+		if line.Hash != previous || i == g.offset {
+			hash, author, date := "uncommit", "Not committed", ""
+			if Committed(line) {
+				hash = line.Hash[:8]
+				author = line.Author
+				date = line.Date.Format("2006-01-02")
+			}
+			annotation = hashStyle.Render(hash) + " " +
+				authorStyle.Render(padTruncate(author, authorWidth)) + " " +
+				muted.Render(padTruncate(date, 10))
+		}
+		previous = line.Hash
+
+		number := muted.Render(fmt.Sprintf("%*d", numberWidth, line.LineNo))
+		text := strings.ReplaceAll(line.Text, "\t", "    ")
+		marker := "  "
+		if i == g.selected {
+			marker = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+			text = lipgloss.NewStyle().Bold(true).Foreground(theme.Primary).Render(text)
+		}
+		out = append(out, marker+annotation+" "+number+" │ "+text)
+	}
+
+	if selected, ok := g.Selected(); ok && Committed(selected) {
+		title += " · " + selected.Summary
+	}
+	return frameLines(title, out, g.width, g.height, g.active)
+}
+
+func padTruncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
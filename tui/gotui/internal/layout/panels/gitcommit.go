@@ -0,0 +1,246 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/components/uicomponents/diffview"
+	"gotui/internal/gitrepo"
+	"gotui/internal/styles"
+)
+
+// GitCommitPanel shows a commit's message, changed files and the diff of the
+// selected file.
+type GitCommitPanel struct {
+	width  int
+	height int
+	active bool
+
+	hash    string
+	detail  gitrepo.CommitDetail
+	loaded  bool
+	file    int
+	offset  int
+	message string
+	// follow scrolls the selected file into view on the next render.
+	follow bool
+
+	diffPath    string
+	diff        gitrepo.FileDiff
+	diffMessage string
+}
+
+// NewGitCommitPanel creates an empty commit view.
+func NewGitCommitPanel() *GitCommitPanel {
+	return &GitCommitPanel{message: "Select a commit to see its details."}
+}
+
+func (g *GitCommitPanel) SetSize(width, height int) { g.width, g.height = width, height }
+func (g *GitCommitPanel) SetActive(active bool)     { g.active = active }
+
+// Hash returns the commit shown, or being loaded.
+func (g *GitCommitPanel) Hash() string { return g.hash }
+
+// SetLoading marks the panel as waiting for hash. A commit already shown
+// stays visible while it reloads.
+func (g *GitCommitPanel) SetLoading(hash string) {
+	if g.hash == hash {
+		return
+	}
+	g.hash = hash
+	g.detail = gitrepo.CommitDetail{}
+	g.loaded = false
+	g.file = 0
+	g.offset = 0
+	g.message = "Loading commit…"
+	g.diffPath = ""
+	g.diff = gitrepo.FileDiff{}
+	g.diffMessage = ""
+}
+
+// SetDetail shows detail if it is the commit the panel is waiting for.
+func (g *GitCommitPanel) SetDetail(detail gitrepo.CommitDetail) bool {
+	if detail.Hash != g.hash && !strings.HasPrefix(detail.Hash, g.hash) {
+		return false
+	}
+	g.hash = detail.Hash
+	g.detail = detail
+	g.loaded = true
+	g.message = ""
+	g.file = clampIndex(g.file, len(detail.Files))
+	g.follow = true
+	return true
+}
+
+// SetMessage replaces the commit view with message, e.g. an error.
+func (g *GitCommitPanel) SetMessage(message string) {
+	g.loaded = false
+	g.message = message
+}
+
+// SelectedFile returns the highlighted file of the commit.
+func (g *GitCommitPanel) SelectedFile() (gitrepo.FileStat, bool) {
+	if !g.loaded || g.file < 0 || g.file >= len(g.detail.Files) {
+		return gitrepo.FileStat{}, false
+	}
+	return g.detail.Files[g.file], true
+}
+
+// MoveFile shifts the file cursor by delta and reports whether it changed.
+func (g *GitCommitPanel) MoveFile(delta int) bool {
+	next := clampIndex(g.file+delta, len(g.detail.Files))
+	changed := next != g.file
+	g.file = next
+	g.follow = true
+	return changed
+}
+
+// SetFileLoading marks the file diff of path as loading.
+func (g *GitCommitPanel) SetFileLoading(path string) {
+	if g.diffPath == path {
+		return
+	}
+	g.diffPath = path
+	g.diff = gitrepo.FileDiff{}
+	g.diffMessage = "Loading diff…"
+}
+
+// SetFileDiff shows diff if it belongs to the selected file of the commit.
+func (g *GitCommitPanel) SetFileDiff(hash, path string, diff gitrepo.FileDiff) {
+	if hash != g.hash || path != g.diffPath {
+		return
+	}
+	g.diff = diff
+	g.diffMessage = ""
+	switch {
+	case diff.Binary:
+		g.diffMessage = "Binary file changed."
+	case diff.Empty():
+		g.diffMessage = "No textual changes."
+	}
+}
+
+// SetFileDiffError shows message in place of path's diff.
+func (g *GitCommitPanel) SetFileDiffError(hash, path, message string) {
+	if hash != g.hash || path != g.diffPath {
+		return
+	}
+	g.diff = gitrepo.FileDiff{}
+	g.diffMessage = message
+}
+
+// Scroll moves the view by delta lines.
+func (g *GitCommitPanel) Scroll(delta int) {
+	g.offset += delta
+	if g.offset < 0 {
+		g.offset = 0
+	}
+}
+
+// View renders the commit.
+func (g *GitCommitPanel) View() string {
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	title := "Commit"
+	if g.detail.ShortHash != "" {
+		title = "Commit " + g.detail.ShortHash
+	}
+	if !g.loaded {
+		return frameLines(title, []string{muted.Render(g.message)}, g.width, g.height, g.active)
+	}
+
+	label := lipgloss.NewStyle().Foreground(theme.Secondary).Bold(true)
+	d := g.detail
+	lines := []string{
+		label.Render("Author  ") + fmt.Sprintf("%s <%s>", d.Author, d.AuthorEmail),
+		label.Render("Date    ") + d.Date.Format("Mon Jan 2 15:04:05 2006") + muted.Render("  ("+relativeDate(d.Date)+")"),
+	}
+	if d.Committer != "" && d.Committer != d.Author {
+		lines = append(lines, label.Render("Commit  ")+d.Committer)
+	}
+	if len(d.Parents) > 1 {
+		lines = append(lines, label.Render("Merge   ")+strings.Join(shortHashes(d.Parents), " "))
+	}
+	if d.Refs != "" {
+		lines = append(lines, label.Render("Refs    ")+lipgloss.NewStyle().Foreground(theme.Warning).Render(d.Refs))
+	}
+	lines = append(lines, "")
+	for _, line := range strings.Split(d.Message, "\n") {
+		lines = append(lines, "    "+line)
+	}
+
+	added, deleted := 0, 0
+	for _, file := range d.Files {
+		added += file.Added
+		deleted += file.Deleted
+	}
+	lines = append(lines, "", label.Render(fmt.Sprintf("Files (%d)  ", len(d.Files)))+g.renderStat(added, deleted))
+	cursorLine := len(lines)
+	for i, file := range d.Files {
+		if i == g.file {
+			cursorLine = len(lines)
+		}
+		lines = append(lines, g.renderFile(file, i == g.file))
+	}
+
+	if g.diffPath != "" {
+		lines = append(lines, "", label.Render(g.diffPath))
+		if g.diffMessage != "" {
+			lines = append(lines, muted.Render(g.diffMessage))
+		} else {
+			diffLines, _ := DiffLinesFromHunks(g.diff.Hunks)
+			lines = append(lines, diffview.RenderUnified(diffLines, g.width-4, diffview.UnifiedOptions{ShowLineNumbers: true}, theme).Lines...)
+		}
+	}
+
+	rows := g.height - 3
+	if g.follow {
+		g.offset = scrollWindow(g.offset, cursorLine, rows, len(lines))
+		g.follow = false
+	}
+	if max := len(lines) - rows; g.offset > max {
+		g.offset = max
+	}
+	if g.offset < 0 {
+		g.offset = 0
+	}
+	return frameLines(title, lines[g.offset:], g.width, g.height, g.active)
+}
+
+func (g *GitCommitPanel) renderFile(file gitrepo.FileStat, selected bool) string {
+	theme := styles.CurrentTheme()
+	marker := "  "
+	name := lipgloss.NewStyle().Foreground(theme.Foreground)
+	if selected {
+		marker = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+		name = name.Bold(true)
+		if g.active {
+			name = name.Foreground(theme.Primary)
+		}
+	}
+	stat := lipgloss.NewStyle().Foreground(theme.Muted).Render("binary")
+	if !file.Binary {
+		stat = g.renderStat(file.Added, file.Deleted)
+	}
+	return marker + name.Render(file.Path) + "  " + stat
+}
+
+func (g *GitCommitPanel) renderStat(added, deleted int) string {
+	theme := styles.CurrentTheme()
+	return lipgloss.NewStyle().Foreground(theme.Success).Render(fmt.Sprintf("+%d", added)) + " " +
+		lipgloss.NewStyle().Foreground(theme.Error).Render(fmt.Sprintf("-%d", deleted))
+}
+
+func shortHashes(hashes []string) []string {
+	short := make([]string, len(hashes))
+	for i, hash := range hashes {
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		short[i] = hash
+	}
+	return short
+}
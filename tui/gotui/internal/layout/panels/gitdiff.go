@@ -0,0 +1,195 @@
+package panels
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/components/uicomponents/diffview"
+	"gotui/internal/gitrepo"
+	"gotui/internal/styles"
+)
+
+// GitDiffPanel previews the diff of the selected file and tracks a hunk
+// cursor for hunk-level staging.
+type GitDiffPanel struct {
+	width   int
+	height  int
+	active  bool
+	item    GitStatusItem
+	diff    gitrepo.FileDiff
+	hunk    int
+	offset  int
+	message string
+}
+
+// NewGitDiffPanel creates an empty diff preview.
+func NewGitDiffPanel() *GitDiffPanel {
+	return &GitDiffPanel{message: "Select a file to preview its diff."}
+}
+
+func (g *GitDiffPanel) SetSize(width, height int) { g.width, g.height = width, height }
+func (g *GitDiffPanel) SetActive(active bool)     { g.active = active }
+
+// SetDiff shows diff for item. The hunk cursor is kept when the same file is
+// refreshed so repeated staging walks through the remaining hunks.
+func (g *GitDiffPanel) SetDiff(item GitStatusItem, diff gitrepo.FileDiff) {
+	sameFile := g.item.Entry.Path == item.Entry.Path && g.item.Staged == item.Staged
+	g.item = item
+	g.diff = diff
+	g.message = ""
+	if !sameFile {
+		g.hunk = 0
+		g.offset = 0
+	}
+	g.hunk = clampIndex(g.hunk, len(diff.Hunks))
+	switch {
+	case diff.Binary:
+		g.message = "Binary file changed."
+	case diff.Empty():
+		g.message = "No changes to show."
+	}
+}
+
+// SetMessage clears the preview and shows message instead.
+func (g *GitDiffPanel) SetMessage(message string) {
+	g.item = GitStatusItem{}
+	g.diff = gitrepo.FileDiff{}
+	g.hunk = 0
+	g.offset = 0
+	g.message = message
+}
+
+// Shows reports whether the panel is showing, or loading, the diff for item.
+func (g *GitDiffPanel) Shows(item GitStatusItem) bool {
+	return g.item.Entry.Path == item.Entry.Path && g.item.Staged == item.Staged
+}
+
+// SetLoading marks the preview as waiting for item's diff. A diff already
+// shown for the same file stays visible while it reloads.
+func (g *GitDiffPanel) SetLoading(item GitStatusItem) {
+	if g.Shows(item) {
+		return
+	}
+	g.SetMessage("Loading diff…")
+	g.item = item
+}
+
+// Diff returns the displayed diff.
+func (g *GitDiffPanel) Diff() gitrepo.FileDiff { return g.diff }
+
+// SelectedHunk returns the index of the hunk under the cursor, or -1.
+func (g *GitDiffPanel) SelectedHunk() int {
+	if len(g.diff.Hunks) == 0 {
+		return -1
+	}
+	return g.hunk
+}
+
+// MoveHunk moves the hunk cursor by delta.
+func (g *GitDiffPanel) MoveHunk(delta int) {
+	g.hunk = clampIndex(g.hunk+delta, len(g.diff.Hunks))
+	g.offset = -1 // scroll the hunk into view on the next render
+}
+
+// Scroll moves the view by delta lines without changing the hunk cursor.
+func (g *GitDiffPanel) Scroll(delta int) {
+	if g.offset < 0 {
+		g.offset = 0
+	}
+	g.offset += delta
+	if g.offset < 0 {
+		g.offset = 0
+	}
+}
+
+// View renders the diff preview.
+func (g *GitDiffPanel) View() string {
+	theme := styles.CurrentTheme()
+	title := "Diff"
+	if g.item.Entry.Path != "" {
+		section := "unstaged"
+		if g.item.Staged {
+			section = "staged"
+		}
+		title = fmt.Sprintf("Diff · %s (%s)", g.item.Entry.Path, section)
+		if n := len(g.diff.Hunks); n > 0 {
+			title += fmt.Sprintf(" · hunk %d/%d", g.hunk+1, n)
+		}
+	}
+	if g.message != "" {
+		msg := lipgloss.NewStyle().Foreground(theme.Muted).Render(g.message)
+		return frameLines(title, []string{msg}, g.width, g.height, g.active)
+	}
+
+	diffLines, hunkStarts := DiffLinesFromHunks(g.diff.Hunks)
+	rendered := diffview.RenderUnified(diffLines, g.width-4, diffview.UnifiedOptions{ShowLineNumbers: true}, theme).Lines
+	if g.hunk < len(hunkStarts) {
+		start := hunkStarts[g.hunk]
+		marker := "▶ "
+		if !g.active {
+			marker = "▷ "
+		}
+		rendered[start] = lipgloss.NewStyle().Foreground(theme.Accent).Bold(true).Render(marker + g.diff.Hunks[g.hunk].Header)
+	}
+
+	rows := g.height - 3
+	if g.offset < 0 {
+		g.offset = 0
+		if g.hunk < len(hunkStarts) {
+			g.offset = scrollWindow(0, hunkStarts[g.hunk], rows, len(rendered))
+			// Prefer showing the hunk from its header downwards.
+			if hunkStarts[g.hunk] < len(rendered)-rows {
+				g.offset = hunkStarts[g.hunk]
+			}
+		}
+	}
+	if max := len(rendered) - rows; g.offset > max {
+		g.offset = max
+	}
+	if g.offset < 0 {
+		g.offset = 0
+	}
+	return frameLines(title, rendered[g.offset:], g.width, g.height, g.active)
+}
+
+// DiffLinesFromHunks converts parsed hunks to diffview lines with line
+// numbers, returning the index of each hunk header in the result.
+func DiffLinesFromHunks(hunks []gitrepo.Hunk) ([]diffview.DiffLine, []int) {
+	var lines []diffview.DiffLine
+	starts := make([]int, 0, len(hunks))
+	for _, hunk := range hunks {
+		starts = append(starts, len(lines))
+		lines = append(lines, diffview.DiffLine{Kind: diffview.DiffLineHeader, Header: hunk.Header})
+		oldLine, newLine := hunk.OldStart, hunk.NewStart
+		for _, raw := range hunk.Lines {
+			if raw == "" {
+				continue
+			}
+			text := strings.ReplaceAll(raw[1:], "\t", "    ")
+			switch raw[0] {
+			case '+':
+				lines = append(lines, diffview.DiffLine{Kind: diffview.DiffLineAdded, NewLine: strconv.Itoa(newLine), NewText: text})
+				newLine++
+			case '-':
+				lines = append(lines, diffview.DiffLine{Kind: diffview.DiffLineRemoved, OldLine: strconv.Itoa(oldLine), OldText: text})
+				oldLine++
+			case '\\':
+				lines = append(lines, diffview.DiffLine{Kind: diffview.DiffLineHeader, Header: raw})
+			default:
+				lines = append(lines, diffview.DiffLine{
+					Kind:    diffview.DiffLineUnchanged,
+					OldLine: strconv.Itoa(oldLine),
+					NewLine: strconv.Itoa(newLine),
+					OldText: text,
+					NewText: text,
+				})
+				oldLine++
+				newLine++
+			}
+		}
+	}
+	return lines, starts
+}
@@ -0,0 +1,70 @@
+package panels
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss/v2"
+	"github.com/charmbracelet/x/ansi"
+
+	"gotui/internal/styles"
+)
+
+// frameLines renders styled lines inside a bordered box of exactly
+// width×height cells. Lines are truncated rather than wrapped so that ANSI
+// styling is preserved.
+func frameLines(title string, lines []string, width, height int, active bool) string {
+	if width < 4 || height < 3 {
+		return ""
+	}
+	theme := styles.CurrentTheme()
+	borderColor := theme.Border
+	if active {
+		borderColor = theme.Primary
+	}
+
+	innerWidth := width - 4
+	innerHeight := height - 3
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Primary).Bold(true)
+	if !active {
+		titleStyle = titleStyle.Foreground(theme.Secondary)
+	}
+
+	body := make([]string, 0, innerHeight+1)
+	body = append(body, ansi.Truncate(titleStyle.Render(title), innerWidth, "…"))
+	for i := 0; i < innerHeight; i++ {
+		line := ""
+		if i < len(lines) {
+			line = ansi.Truncate(lines[i], innerWidth, "…")
+		}
+		body = append(body, line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Padding(0, 1).
+		Width(width).
+		Height(height).
+		Render(strings.Join(body, "\n"))
+}
+
+// scrollWindow returns the first visible index so that cursor stays within a
+// window of size rows starting at offset.
+func scrollWindow(offset, cursor, rows, total int) int {
+	if rows <= 0 {
+		return 0
+	}
+	if cursor < offset {
+		offset = cursor
+	}
+	if cursor >= offset+rows {
+		offset = cursor - rows + 1
+	}
+	if max := total - rows; offset > max {
+		offset = max
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return offset
+}
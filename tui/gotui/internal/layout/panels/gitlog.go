@@ -0,0 +1,239 @@
+package panels
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/styles"
+)
+
+// logLookahead is how close to the end of the loaded history the cursor may
+// get before the next page is requested.
+const logLookahead = 10
+
+// GitLogPanel lists the commit history with its branch graph. History is
+// loaded a page at a time as the cursor approaches the end.
+type GitLogPanel struct {
+	width   int
+	height  int
+	active  bool
+	path    string
+	lines   []gitrepo.LogLine
+	commits []int // indexes into lines of rows holding a commit
+	// selected indexes commits.
+	selected  int
+	offset    int
+	loading   bool
+	exhausted bool
+	message   string
+}
+
+// NewGitLogPanel creates an empty history list.
+func NewGitLogPanel() *GitLogPanel {
+	return &GitLogPanel{message: "History not loaded yet."}
+}
+
+func (g *GitLogPanel) SetSize(width, height int) { g.width, g.height = width, height }
+func (g *GitLogPanel) SetActive(active bool)     { g.active = active }
+
+// Reset clears the list before loading the history of path, or of the whole
+// repository when path is empty.
+func (g *GitLogPanel) Reset(path string) {
+	g.path = path
+	g.lines = nil
+	g.commits = nil
+	g.selected = 0
+	g.offset = 0
+	g.exhausted = false
+	g.loading = true
+	g.message = "Loading history…"
+}
+
+// Path returns the file the history is filtered to.
+func (g *GitLogPanel) Path() string { return g.path }
+
+// AppendPage adds a page of history. A page holding fewer than limit commits
+// marks the end of the history.
+func (g *GitLogPanel) AppendPage(lines []gitrepo.LogLine, limit int) {
+	g.loading = false
+	g.message = ""
+	count := 0
+	for _, line := range lines {
+		if line.Commit != nil {
+			g.commits = append(g.commits, len(g.lines))
+			count++
+		}
+		g.lines = append(g.lines, line)
+	}
+	if count < limit {
+		g.exhausted = true
+	}
+	if len(g.commits) == 0 {
+		g.message = "No commits yet."
+		if g.path != "" {
+			g.message = "No commits touch " + g.path + "."
+		}
+	}
+}
+
+// SetMessage stops loading and shows message, e.g. an error, in place of the
+// rest of the history.
+func (g *GitLogPanel) SetMessage(message string) {
+	g.loading = false
+	g.exhausted = true
+	g.message = message
+}
+
+// CommitCount returns the number of commits loaded so far.
+func (g *GitLogPanel) CommitCount() int { return len(g.commits) }
+
+// NeedsMore reports whether the next page should be requested and marks it
+// as loading.
+func (g *GitLogPanel) NeedsMore() bool {
+	if g.loading || g.exhausted || len(g.commits)-g.selected > logLookahead {
+		return false
+	}
+	g.loading = true
+	return true
+}
+
+// Selected returns the highlighted commit.
+func (g *GitLogPanel) Selected() (*gitrepo.Commit, bool) {
+	if g.selected < 0 || g.selected >= len(g.commits) {
+		return nil, false
+	}
+	return g.lines[g.commits[g.selected]].Commit, true
+}
+
+// Move shifts the selection by delta commits and reports whether it changed.
+func (g *GitLogPanel) Move(delta int) bool {
+	next := clampIndex(g.selected+delta, len(g.commits))
+	changed := next != g.selected
+	g.selected = next
+	return changed
+}
+
+// Select highlights the loaded commit whose hash starts with hash.
+func (g *GitLogPanel) Select(hash string) bool {
+	if hash == "" {
+		return false
+	}
+	for i, row := range g.commits {
+		if strings.HasPrefix(g.lines[row].Commit.Hash, hash) {
+			g.selected = i
+			return true
+		}
+	}
+	return false
+}
+
+// PageSize returns the number of rows visible at once.
+func (g *GitLogPanel) PageSize() int {
+	if rows := g.height - 3; rows > 1 {
+		return rows
+	}
+	return 1
+}
+
+// View renders the history.
+func (g *GitLogPanel) View() string {
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	title := "History"
+	if g.path != "" {
+		title = "History · " + g.path
+	}
+	if n := len(g.commits); n > 0 {
+		more := ""
+		if !g.exhausted {
+			more = "+"
+		}
+		title += fmt.Sprintf(" (%d%s)", n, more)
+	}
+
+	lines := make([]string, 0, len(g.lines)+1)
+	cursorLine := 0
+	for i, line := range g.lines {
+		selected := g.selected < len(g.commits) && g.commits[g.selected] == i
+		if selected {
+			cursorLine = len(lines)
+		}
+		lines = append(lines, g.renderLine(line, selected))
+	}
+	switch {
+	case g.message != "":
+		lines = append(lines, muted.Render(g.message))
+	case g.loading:
+		lines = append(lines, muted.Render("Loading more…"))
+	}
+
+	rows := g.height - 3
+	g.offset = scrollWindow(g.offset, cursorLine, rows, len(lines))
+	if g.offset > 0 && g.offset < len(lines) {
+		lines = lines[g.offset:]
+	}
+	return frameLines(title, lines, g.width, g.height, g.active)
+}
+
+func (g *GitLogPanel) renderLine(line gitrepo.LogLine, selected bool) string {
+	theme := styles.CurrentTheme()
+	graph := lipgloss.NewStyle().Foreground(theme.Secondary).Render(line.Graph)
+	if line.Commit == nil {
+		return "  " + graph
+	}
+	commit := line.Commit
+
+	marker := "  "
+	subject := lipgloss.NewStyle().Foreground(theme.Foreground)
+	if selected {
+		marker = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+		subject = subject.Bold(true)
+		if g.active {
+			subject = subject.Foreground(theme.Primary)
+		}
+	}
+	parts := []string{
+		marker + graph,
+		lipgloss.NewStyle().Foreground(theme.Accent).Render(commit.ShortHash),
+	}
+	if commit.Refs != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(theme.Warning).Bold(true).Render("("+commit.Refs+")"))
+	}
+	parts = append(parts,
+		subject.Render(commit.Subject),
+		lipgloss.NewStyle().Foreground(theme.Muted).Render(fmt.Sprintf("· %s, %s", commit.Author, relativeDate(commit.Date))),
+	)
+	return strings.Join(parts, " ")
+}
+
+// relativeDate formats t as a coarse age such as "3 days ago".
+func relativeDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	d := time.Since(t)
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", name)
+		}
+		return fmt.Sprintf("%d %ss ago", n, name)
+	}
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return unit(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return unit(int(d.Hours()), "hour")
+	case d < 30*24*time.Hour:
+		return unit(int(d.Hours()/24), "day")
+	case d < 365*24*time.Hour:
+		return unit(int(d.Hours()/24/30), "month")
+	}
+	return t.Format("2006-01-02")
+}
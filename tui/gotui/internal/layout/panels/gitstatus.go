@@ -1,33 +1,205 @@
 package panels
 
 import (
-	"gotui/internal/components/uicomponents/panel"
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/styles"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
-// GitStatusPanel renders repository status information.
+// GitStatusItem is one selectable row: a file in either the staged or the
+// unstaged section. A partially staged file appears in both.
+type GitStatusItem struct {
+	Entry  gitrepo.FileEntry
+	Staged bool
+}
+
+// GitStatusPanel lists changed files and tracks the selected one.
 type GitStatusPanel struct {
-	panel *panel.Panel
+	width    int
+	height   int
+	active   bool
+	status   gitrepo.Status
+	items    []GitStatusItem
+	selected int
+	offset   int
+	message  string
 }
 
 // NewGitStatusPanel creates a status panel with default content.
 func NewGitStatusPanel() *GitStatusPanel {
-	p := panel.New("Repository Status")
-	p.AddLine("Git status not loaded yet.")
-	return &GitStatusPanel{panel: p}
+	return &GitStatusPanel{message: "Git status not loaded yet."}
+}
+
+func (g *GitStatusPanel) SetSize(width, height int)  { g.width, g.height = width, height }
+func (g *GitStatusPanel) SetActive(active bool)      { g.active = active }
+func (g *GitStatusPanel) Update(msg tea.Msg) tea.Cmd { return nil }
+
+// SetStatus replaces the listed files, keeping the selection on the same file
+// where possible.
+func (g *GitStatusPanel) SetStatus(status gitrepo.Status) {
+	previous, hadSelection := g.Selected()
+	g.status = status
+	g.message = ""
+	g.items = g.items[:0]
+	for _, entry := range status.Entries {
+		if entry.Staged() {
+			g.items = append(g.items, GitStatusItem{Entry: entry, Staged: true})
+		}
+	}
+	for _, entry := range status.Entries {
+		if entry.Unstaged() {
+			g.items = append(g.items, GitStatusItem{Entry: entry})
+		}
+	}
+	if len(g.items) == 0 {
+		g.message = "Working tree clean."
+	}
+
+	g.selected = clampIndex(g.selected, len(g.items))
+	if hadSelection {
+		for i, item := range g.items {
+			if item.Entry.Path == previous.Entry.Path && item.Staged == previous.Staged {
+				g.selected = i
+				return
+			}
+		}
+		// The file moved sections (e.g. after staging); follow it.
+		for i, item := range g.items {
+			if item.Entry.Path == previous.Entry.Path {
+				g.selected = i
+				return
+			}
+		}
+	}
+}
+
+// SetMessage replaces the list with an informational message, e.g. an error.
+func (g *GitStatusPanel) SetMessage(message string) {
+	g.status = gitrepo.Status{}
+	g.items = nil
+	g.selected = 0
+	g.message = message
+}
+
+// Status returns the last loaded status.
+func (g *GitStatusPanel) Status() gitrepo.Status { return g.status }
+
+// Items returns the selectable rows.
+func (g *GitStatusPanel) Items() []GitStatusItem { return g.items }
+
+// Selected returns the highlighted row.
+func (g *GitStatusPanel) Selected() (GitStatusItem, bool) {
+	if g.selected < 0 || g.selected >= len(g.items) {
+		return GitStatusItem{}, false
+	}
+	return g.items[g.selected], true
 }
 
-func (g *GitStatusPanel) SetSize(width, height int)  { g.panel.SetSize(width, height) }
-func (g *GitStatusPanel) Update(msg tea.Msg) tea.Cmd { return g.panel.Update(msg) }
-func (g *GitStatusPanel) View() string               { return g.panel.View() }
+// Move shifts the selection by delta rows and reports whether it changed.
+func (g *GitStatusPanel) Move(delta int) bool {
+	next := clampIndex(g.selected+delta, len(g.items))
+	changed := next != g.selected
+	g.selected = next
+	return changed
+}
+
+// View renders the file list.
+func (g *GitStatusPanel) View() string {
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+	section := lipgloss.NewStyle().Foreground(theme.Secondary).Bold(true)
+
+	title := "Changes"
+	if g.status.Branch != "" {
+		title = fmt.Sprintf("Changes on %s", g.status.Branch)
+		if g.status.Ahead > 0 {
+			title += fmt.Sprintf(" ↑%d", g.status.Ahead)
+		}
+		if g.status.Behind > 0 {
+			title += fmt.Sprintf(" ↓%d", g.status.Behind)
+		}
+	}
+
+	var lines []string
+	cursorLine := 0
+	if g.message != "" {
+		lines = append(lines, muted.Render(g.message))
+	}
+	inStaged := false
+	inUnstaged := false
+	for i, item := range g.items {
+		if item.Staged && !inStaged {
+			lines = append(lines, section.Render("Staged"))
+			inStaged = true
+		}
+		if !item.Staged && !inUnstaged {
+			if inStaged {
+				lines = append(lines, "")
+			}
+			lines = append(lines, section.Render("Unstaged"))
+			inUnstaged = true
+		}
+		if i == g.selected {
+			cursorLine = len(lines)
+		}
+		lines = append(lines, g.renderItem(item, i == g.selected))
+	}
+
+	rows := g.height - 3
+	g.offset = scrollWindow(g.offset, cursorLine, rows, len(lines))
+	if g.offset > 0 && g.offset < len(lines) {
+		lines = lines[g.offset:]
+	}
+	return frameLines(title, lines, g.width, g.height, g.active)
+}
 
-// SetLines replaces the panel content with the provided lines.
-func (g *GitStatusPanel) SetLines(lines []string) {
-	g.panel.Clear()
-	if len(lines) == 0 {
-		g.panel.AddLine("No git status available.")
-		return
+func (g *GitStatusPanel) renderItem(item GitStatusItem, selected bool) string {
+	theme := styles.CurrentTheme()
+	code := item.Entry.Worktree
+	color := theme.Warning
+	switch {
+	case item.Entry.Conflicted():
+		code, color = 'U', theme.Error
+	case item.Entry.Untracked():
+		code, color = '?', theme.Info
+	case item.Staged:
+		code, color = item.Entry.Index, theme.Success
+	}
+	if code == 'D' {
+		color = theme.Error
+	}
+
+	name := item.Entry.Path
+	if item.Entry.OrigPath != "" && item.Staged {
+		name = fmt.Sprintf("%s → %s", item.Entry.OrigPath, item.Entry.Path)
+	}
+	marker := "  "
+	nameStyle := lipgloss.NewStyle().Foreground(theme.Foreground)
+	if selected {
+		marker = lipgloss.NewStyle().Foreground(theme.Accent).Render("➤ ")
+		nameStyle = nameStyle.Bold(true)
+		if g.active {
+			nameStyle = nameStyle.Foreground(theme.Primary)
+		}
+	}
+	badge := lipgloss.NewStyle().Foreground(color).Bold(true).Render(string(code))
+	return marker + badge + " " + nameStyle.Render(name)
+}
+
+func clampIndex(i, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	if i < 0 {
+		return 0
+	}
+	if i >= n {
+		return n - 1
 	}
-	g.panel.AddLines(lines)
+	return i
 }
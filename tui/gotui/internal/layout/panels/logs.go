@@ -1,20 +1,571 @@
 package panels
 
 import (
-	"gotui/internal/components/uicomponents/panel"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/charmbracelet/bubbles/v2/textinput"
 	tea "github.com/charmbracelet/bubbletea/v2"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/styles"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String returns the short upper-case name used in the log view.
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "DBG"
+	case LogWarn:
+		return "WRN"
+	case LogError:
+		return "ERR"
+	}
+	return "INF"
+}
+
+// ParseLogLevel maps level names such as "warn" or "ERROR" to a LogLevel.
+func ParseLogLevel(name string) LogLevel {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug", "dbg", "trace", "verbose":
+		return LogDebug
+	case "warn", "warning", "wrn":
+		return LogWarn
+	case "error", "err", "fatal", "critical":
+		return LogError
+	}
+	return LogInfo
+}
+
+// Log sources.
+const (
+	LogSourceApp    = "app"
+	LogSourceWS     = "ws"
+	LogSourceServer = "server"
+	LogSourceAgent  = "agent"
 )
 
-// LogsPanel wraps a generic panel for general logs
-type LogsPanel struct{ panel *panel.Panel }
+// LogField is a key/value attached to a log entry.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// LogEntry is one structured log line.
+type LogEntry struct {
+	Time    time.Time
+	Level   LogLevel
+	Source  string
+	Message string
+	Fields  []LogField
+}
+
+// String formats the entry as plain text, as written by exports.
+func (e LogEntry) String() string {
+	var b strings.Builder
+	b.WriteString(e.Time.Format("2006-01-02T15:04:05.000"))
+	b.WriteString(" ")
+	b.WriteString(e.Level.String())
+	b.WriteString(" ")
+	b.WriteString(e.Source)
+	b.WriteString(" ")
+	b.WriteString(e.Message)
+	for _, field := range e.Fields {
+		fmt.Fprintf(&b, " %s=%s", field.Key, field.Value)
+	}
+	return b.String()
+}
+
+// FieldsFromMap converts arbitrary values to sorted log fields.
+func FieldsFromMap(values map[string]any) []LogField {
+	fields := make([]LogField, 0, len(values))
+	for key, value := range values {
+		fields = append(fields, LogField{Key: key, Value: fmt.Sprint(value)})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// maxLogEntries bounds the memory used by a log panel.
+const maxLogEntries = 5000
+
+type logRow struct {
+	entry LogEntry
+	seq   int
+}
+
+// LogsPanel shows structured log entries with level and source filters,
+// incremental search and pause/follow. Entries may be added from any
+// goroutine.
+type LogsPanel struct {
+	mu      sync.Mutex
+	rows    []logRow
+	nextSeq int
+
+	title   string
+	width   int
+	height  int
+	visible bool
+	active  bool
+
+	minLevel LogLevel
+	source   string
+	sources  []string
+
+	search    textinput.Model
+	searching bool
+	query     string
+	match     int // seq of the focused search match, -1 for none
+
+	// pausedAt hides entries from seq onwards while paused.
+	paused   bool
+	pausedAt int
+	follow   bool
+	offset   int
+}
+
+func NewLogs(title string) *LogsPanel {
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "search"
+	return &LogsPanel{
+		title:    title,
+		visible:  true,
+		follow:   true,
+		minLevel: LogDebug,
+		match:    -1,
+		search:   search,
+	}
+}
+
+func (p *LogsPanel) SetSize(w, h int)  { p.width, p.height = w, h }
+func (p *LogsPanel) SetVisible(v bool) { p.visible = v }
+func (p *LogsPanel) IsVisible() bool   { return p.visible }
+func (p *LogsPanel) SetActive(a bool)  { p.active = a }
+
+// Update feeds non-key messages to the search field while it is open.
+func (p *LogsPanel) Update(msg tea.Msg) tea.Cmd {
+	if !p.searching {
+		return nil
+	}
+	if _, ok := msg.(tea.KeyPressMsg); ok {
+		return nil
+	}
+	var cmd tea.Cmd
+	p.search, cmd = p.search.Update(msg)
+	return cmd
+}
+
+// AddLine records a free-form info line from the app.
+func (p *LogsPanel) AddLine(s string) {
+	p.Add(LogEntry{Level: LogInfo, Message: s})
+}
+
+// AddWarning records a free-form warning from the app.
+func (p *LogsPanel) AddWarning(s string) {
+	p.Add(LogEntry{Level: LogWarn, Message: s})
+}
+
+// AddError records a free-form error from the app.
+func (p *LogsPanel) AddError(s string) {
+	p.Add(LogEntry{Level: LogError, Message: s})
+}
+
+// Add records a structured entry.
+func (p *LogsPanel) Add(entry LogEntry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if entry.Source == "" {
+		entry.Source = LogSourceApp
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rows = append(p.rows, logRow{entry: entry, seq: p.nextSeq})
+	p.nextSeq++
+	// Trim in batches so the buffer is not copied on every line.
+	if len(p.rows) > maxLogEntries+maxLogEntries/10 {
+		p.rows = append(p.rows[:0:0], p.rows[len(p.rows)-maxLogEntries:]...)
+	}
+	if !containsString(p.sources, entry.Source) {
+		p.sources = append(p.sources, entry.Source)
+		sort.Strings(p.sources)
+	}
+}
+
+// Entries returns the entries that pass the current filters, oldest first.
+func (p *LogsPanel) Entries() []LogEntry {
+	rows := p.visibleRows()
+	entries := make([]LogEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = row.entry
+	}
+	return entries
+}
+
+// Title returns the panel title.
+func (p *LogsPanel) Title() string { return p.title }
+
+// Searching reports whether the search field has focus.
+func (p *LogsPanel) Searching() bool { return p.searching }
+
+// visibleRows filters the buffer by level and source and hides entries added
+// while paused. Search matches are highlighted rather than filtered.
+func (p *LogsPanel) visibleRows() []logRow {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rows := make([]logRow, 0, len(p.rows))
+	for _, row := range p.rows {
+		if p.paused && row.seq >= p.pausedAt {
+			break
+		}
+		if row.entry.Level < p.minLevel {
+			continue
+		}
+		if p.source != "" && row.entry.Source != p.source {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (p *LogsPanel) pendingCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.paused {
+		return 0
+	}
+	return p.nextSeq - p.pausedAt
+}
+
+// HandleKey processes a key press while the panel is focused.
+func (p *LogsPanel) HandleKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if p.searching {
+		return p.handleSearchKey(msg), true
+	}
+	switch msg.String() {
+	case "up", "k":
+		p.scroll(-1)
+	case "down", "j":
+		p.scroll(1)
+	case "pgup", "K":
+		p.scroll(-p.rowsPerPage())
+	case "pgdown", "J":
+		p.scroll(p.rowsPerPage())
+	case "home", "g":
+		p.follow = false
+		p.offset = 0
+	case "end", "G":
+		p.follow = true
+	case "f":
+		p.follow = !p.follow
+	case "p", "space", " ":
+		p.TogglePause()
+	case "v":
+		p.minLevel = (p.minLevel + 1) % (LogError + 1)
+	case "s":
+		p.cycleSource()
+	case "/":
+		p.searching = true
+		p.search.SetValue(p.query)
+		p.search.CursorEnd()
+		return p.search.Focus(), true
+	case "n":
+		p.jumpMatch(1)
+	case "N":
+		p.jumpMatch(-1)
+	case "esc":
+		if p.query == "" {
+			return nil, false
+		}
+		p.query = ""
+		p.match = -1
+	case "c":
+		p.Clear()
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+func (p *LogsPanel) handleSearchKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		p.searching = false
+		p.search.Blur()
+		p.query = ""
+		p.match = -1
+		return nil
+	case "enter":
+		p.searching = false
+		p.search.Blur()
+		return nil
+	}
+	var cmd tea.Cmd
+	p.search, cmd = p.search.Update(msg)
+	if query := p.search.Value(); query != p.query {
+		// Incremental: jump to the latest match as the query is typed.
+		p.query = query
+		p.match = -1
+		p.jumpMatch(-1)
+	}
+	return cmd
+}
+
+// TogglePause freezes the view; entries keep being recorded and appear on
+// resume.
+func (p *LogsPanel) TogglePause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = !p.paused
+	p.pausedAt = p.nextSeq
+}
+
+// Clear drops all recorded entries.
+func (p *LogsPanel) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rows = nil
+	p.pausedAt = p.nextSeq
+	p.offset = 0
+	p.match = -1
+}
+
+func (p *LogsPanel) cycleSource() {
+	p.mu.Lock()
+	sources := append([]string(nil), p.sources...)
+	p.mu.Unlock()
+	next := ""
+	if p.source == "" && len(sources) > 0 {
+		next = sources[0]
+	}
+	for i, source := range sources {
+		if source == p.source && i+1 < len(sources) {
+			next = sources[i+1]
+		}
+	}
+	p.source = next
+}
+
+func (p *LogsPanel) rowsPerPage() int {
+	if rows := p.height - 3; rows > 1 {
+		return rows
+	}
+	return 1
+}
+
+func (p *LogsPanel) scroll(delta int) {
+	if p.follow {
+		total := len(p.visibleRows())
+		p.offset = max(total-p.rowsPerPage(), 0)
+	}
+	p.offset += delta
+	if p.offset < 0 {
+		p.offset = 0
+	}
+	// Scrolling back to the bottom resumes following.
+	total := len(p.visibleRows())
+	p.follow = p.offset >= total-p.rowsPerPage()
+}
+
+// jumpMatch moves to the next (dir > 0) or previous search match and
+// scrolls it into view.
+func (p *LogsPanel) jumpMatch(dir int) {
+	if p.query == "" {
+		return
+	}
+	rows := p.visibleRows()
+	var matches []int
+	for i, row := range rows {
+		if entryMatches(row.entry, p.query) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		p.match = -1
+		return
+	}
+	current := -1
+	for i, idx := range matches {
+		if rows[idx].seq == p.match {
+			current = i
+		}
+	}
+	next := len(matches) - 1
+	switch {
+	case current >= 0 && dir > 0:
+		next = (current + 1) % len(matches)
+	case current >= 0:
+		next = (current - 1 + len(matches)) % len(matches)
+	}
+	idx := matches[next]
+	p.match = rows[idx].seq
+	p.follow = false
+	page := p.rowsPerPage()
+	if idx < p.offset || idx >= p.offset+page {
+		p.offset = max(idx-page/2, 0)
+	}
+}
+
+func entryMatches(entry LogEntry, query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(entry.Message), query) {
+		return true
+	}
+	for _, field := range entry.Fields {
+		if strings.Contains(strings.ToLower(field.Value), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// View renders the log panel.
+func (p *LogsPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+	theme := styles.CurrentTheme()
+	rows := p.visibleRows()
+
+	page := p.rowsPerPage()
+	if p.searching {
+		page-- // the search field takes the last row
+	}
+	if p.follow {
+		p.offset = len(rows) - page
+	}
+	if maxOffset := len(rows) - page; p.offset > maxOffset {
+		p.offset = maxOffset
+	}
+	if p.offset < 0 {
+		p.offset = 0
+	}
+	end := min(p.offset+page, len(rows))
+
+	matchCount, matchIndex := 0, 0
+	if p.query != "" {
+		for _, row := range rows {
+			if entryMatches(row.entry, p.query) {
+				matchCount++
+				if row.seq == p.match {
+					matchIndex = matchCount
+				}
+			}
+		}
+	}
+
+	lines := make([]string, 0, page+1)
+	for _, row := range rows[p.offset:end] {
+		lines = append(lines, p.renderEntry(row))
+	}
+	if len(rows) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(theme.Muted).Render("No log entries match the filters."))
+	}
+	if p.searching {
+		for len(lines) < page {
+			lines = append(lines, "")
+		}
+		lines = append(lines, p.search.View())
+	}
+	return frameLines(p.titleLine(matchIndex, matchCount), lines, p.width, p.height, p.active)
+}
+
+func (p *LogsPanel) titleLine(matchIndex, matchCount int) string {
+	parts := []string{p.title}
+	if p.minLevel > LogDebug {
+		parts = append(parts, "≥"+strings.ToLower(p.minLevel.String()))
+	}
+	if p.source != "" {
+		parts = append(parts, "src:"+p.source)
+	}
+	if p.query != "" {
+		parts = append(parts, fmt.Sprintf("/%s (%d/%d)", p.query, matchIndex, matchCount))
+	}
+	switch {
+	case p.paused:
+		parts = append(parts, fmt.Sprintf("⏸ paused (+%d)", p.pendingCount()))
+	case !p.follow:
+		parts = append(parts, "scrolled")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (p *LogsPanel) renderEntry(row logRow) string {
+	theme := styles.CurrentTheme()
+	entry := row.entry
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	levelColor := theme.Info
+	switch entry.Level {
+	case LogDebug:
+		levelColor = theme.Muted
+	case LogWarn:
+		levelColor = theme.Warning
+	case LogError:
+		levelColor = theme.Error
+	}
+
+	marker := " "
+	if row.seq == p.match && p.query != "" {
+		marker = lipgloss.NewStyle().Foreground(theme.Accent).Render("▶")
+	}
+	text := entry.Message
+	for _, field := range entry.Fields {
+		text += " " + field.Key + "=" + field.Value
+	}
+	return marker +
+		muted.Render(entry.Time.Format("15:04:05")) + " " +
+		lipgloss.NewStyle().Foreground(levelColor).Bold(true).Render(entry.Level.String()) + " " +
+		lipgloss.NewStyle().Foreground(theme.Secondary).Render(fmt.Sprintf("%-6s", entry.Source)) + " " +
+		highlight(text, p.query, lipgloss.NewStyle().Foreground(theme.Foreground), lipgloss.NewStyle().Reverse(true).Foreground(theme.Accent))
+}
 
-func NewLogs(title string) *LogsPanel { return &LogsPanel{panel: panel.New(title)} }
+// highlight renders text with every case-insensitive occurrence of query in
+// the match style.
+func highlight(text, query string, base, match lipgloss.Style) string {
+	if query == "" {
+		return base.Render(text)
+	}
+	lower, needle := strings.ToLower(text), strings.ToLower(query)
+	if len(lower) != len(text) {
+		// Case folding changed byte offsets; fall back to an exact search.
+		lower, needle = text, query
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, needle)
+		if i < 0 {
+			b.WriteString(base.Render(text))
+			return b.String()
+		}
+		if i > 0 {
+			b.WriteString(base.Render(text[:i]))
+		}
+		b.WriteString(match.Render(text[i : i+len(needle)]))
+		text, lower = text[i+len(needle):], lower[i+len(needle):]
+	}
+}
 
-func (p *LogsPanel) SetSize(w, h int)           { p.panel.SetSize(w, h) }
-func (p *LogsPanel) SetVisible(v bool)          { p.panel.SetVisible(v) }
-func (p *LogsPanel) IsVisible() bool            { return p.panel.IsVisible() }
-func (p *LogsPanel) SetActive(a bool)           { p.panel.SetActive(a) }
-func (p *LogsPanel) Update(msg tea.Msg) tea.Cmd { return p.panel.Update(msg) }
-func (p *LogsPanel) AddLine(s string)           { p.panel.AddLine(s) }
-func (p *LogsPanel) View() string               { return p.panel.View() }
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
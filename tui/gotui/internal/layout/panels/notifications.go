@@ -1,29 +1,95 @@
 package panels
 
 import (
-	"time"
+	"fmt"
 
-	"gotui/internal/components/uicomponents/panel"
+	"github.com/charmbracelet/lipgloss/v2"
+
+	"gotui/internal/stores"
+	"gotui/internal/styles"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 )
 
-// NotificationsPanel shows server notifications
-type NotificationsPanel struct{ panel *panel.Panel }
+// NotificationsPanel shows the notification centre, newest first, with
+// unread entries marked.
+type NotificationsPanel struct {
+	width   int
+	height  int
+	visible bool
+	active  bool
+	store   *stores.NotificationStore
+}
 
 func NewNotifications() *NotificationsPanel {
-	return &NotificationsPanel{panel: panel.New("Notifications")}
+	return &NotificationsPanel{visible: true, store: stores.SharedNotificationStore()}
+}
+
+func (p *NotificationsPanel) SetSize(w, h int)       { p.width, p.height = w, h }
+func (p *NotificationsPanel) SetVisible(v bool)      { p.visible = v }
+func (p *NotificationsPanel) IsVisible() bool        { return p.visible }
+func (p *NotificationsPanel) SetActive(a bool)       { p.active = a }
+func (p *NotificationsPanel) Update(tea.Msg) tea.Cmd { return nil }
+
+// View renders the notifications that fit, newest first.
+func (p *NotificationsPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	items := p.store.Notifications()
+	unread := 0
+	for _, n := range items {
+		if !n.Read {
+			unread++
+		}
+	}
+	title := "Notifications"
+	if unread > 0 {
+		title = fmt.Sprintf("Notifications · %d unread", unread)
+	}
+	if len(items) == 0 {
+		return frameLines(title, []string{muted.Render("No notifications yet.")}, p.width, p.height, p.active)
+	}
+
+	rows := max(p.height-3, 1)
+	lines := make([]string, 0, rows)
+	for i, n := range items {
+		if len(lines) == rows-1 && len(items)-i > 1 {
+			lines = append(lines, muted.Render(fmt.Sprintf("… %d older", len(items)-i)))
+			break
+		}
+		lines = append(lines, p.renderNotification(n))
+	}
+	return frameLines(title, lines, p.width, p.height, p.active)
 }
 
-func (p *NotificationsPanel) SetSize(w, h int)           { p.panel.SetSize(w, h) }
-func (p *NotificationsPanel) SetVisible(v bool)          { p.panel.SetVisible(v) }
-func (p *NotificationsPanel) IsVisible() bool            { return p.panel.IsVisible() }
-func (p *NotificationsPanel) SetActive(a bool)           { p.panel.SetActive(a) }
-func (p *NotificationsPanel) Update(msg tea.Msg) tea.Cmd { return p.panel.Update(msg) }
-func (p *NotificationsPanel) AddLine(s string)           { p.panel.AddLine(s) }
-func (p *NotificationsPanel) View() string               { return p.panel.View() }
-
-// Helper to add timestamped lines if needed elsewhere
-func (p *NotificationsPanel) AddTimestamped(s string) {
-	p.panel.AddLine(time.Now().Format("15:04:05") + " " + s)
+func (p *NotificationsPanel) renderNotification(n stores.Notification) string {
+	theme := styles.CurrentTheme()
+	icon, color := "ℹ", theme.Info
+	switch n.Severity {
+	case stores.SeveritySuccess:
+		icon, color = "✔", theme.Success
+	case stores.SeverityWarning:
+		icon, color = "✋", theme.Warning
+	case stores.SeverityError:
+		icon, color = "✖", theme.Error
+	}
+
+	marker := "  "
+	titleStyle := lipgloss.NewStyle().Foreground(theme.Foreground)
+	if !n.Read {
+		marker = lipgloss.NewStyle().Foreground(theme.Accent).Render("● ")
+		titleStyle = titleStyle.Bold(true)
+	}
+	line := marker +
+		lipgloss.NewStyle().Foreground(theme.Muted).Render(n.At.Format("15:04:05")) + " " +
+		lipgloss.NewStyle().Foreground(color).Render(icon) + " " +
+		titleStyle.Render(n.Title)
+	if n.Body != "" {
+		line += lipgloss.NewStyle().Foreground(theme.Muted).Render(" — " + n.Body)
+	}
+	return line
 }
@@ -1,14 +1,23 @@
 package tabpages
 
 import (
+	"fmt"
+	"strings"
+
+	"gotui/internal/agentcontext"
 	"gotui/internal/components/chat"
 	"gotui/internal/layout/panels"
+	"gotui/internal/stores"
 	"gotui/internal/styles"
+	"gotui/internal/usage"
 
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
 
+// contextBarWidth is the width of the context window gauge.
+const contextBarWidth = 24
+
 // ChatPage composes the chat component with its supporting sidebar panels.
 type ChatPage struct {
 	chat *chat.Chat
@@ -16,9 +25,21 @@ type ChatPage struct {
 	subagentsPanel *panels.InfoPanel
 	todoPanel      *panels.InfoPanel
 	mcpPanel       *panels.InfoPanel
-	modifiedPanel  *panels.InfoPanel
+	changesPanel   *panels.InfoPanel
 	nextTasksPanel *panels.InfoPanel
 	contextPanel   *panels.InfoPanel
+	usagePanel     *panels.InfoPanel
+
+	// changesKey identifies the conversation and store version the changes
+	// panel was last built from.
+	changesKey string
+	// usageKey does the same for the usage panel.
+	usageKey string
+	// contextKey identifies what the context panel was last built from;
+	// contextLines caches its content to skip redundant rebuilds of the
+	// drawer.
+	contextKey   string
+	contextLines string
 }
 
 // NewChatPage constructs a chat page with default sidebar panels.
@@ -30,21 +51,24 @@ func NewChatPage() *ChatPage {
 		subagentsPanel: panels.NewInfoPanel("Subagents"),
 		todoPanel:      panels.NewInfoPanel("Todo"),
 		mcpPanel:       panels.NewInfoPanel("MCP"),
-		modifiedPanel:  panels.NewInfoPanel("Modified Files"),
+		changesPanel:   panels.NewInfoPanel("Changes"),
 		nextTasksPanel: panels.NewInfoPanel("Next Scheduled Tasks"),
 		contextPanel:   panels.NewInfoPanel("Context"),
+		usagePanel:     panels.NewInfoPanel("Usage"),
 	}
 
 	p.chat.SetRightSidebarPanels(
 		p.subagentsPanel,
 		p.todoPanel,
 		p.mcpPanel,
-		p.modifiedPanel,
+		p.changesPanel,
 		p.nextTasksPanel,
 		p.contextPanel,
+		p.usagePanel,
 	)
 
 	p.seedRightSidebarDefaults()
+	p.syncContext()
 
 	return p
 }
@@ -69,9 +93,157 @@ func (p *ChatPage) Update(msg tea.Msg) tea.Cmd {
 	}
 	var cmd tea.Cmd
 	p.chat, cmd = p.chat.Update(msg)
+	p.syncChanges()
+	p.syncContext()
+	p.syncUsage()
 	return cmd
 }
 
+// syncChanges lists the active conversation's agent file changes.
+func (p *ChatPage) syncChanges() {
+	if p.changesPanel == nil {
+		return
+	}
+	store := stores.SharedChangeStore()
+	conversationID := stores.SharedConversationStore().ActiveID()
+	key := fmt.Sprintf("%s@%d", conversationID, store.Version())
+	if key == p.changesKey {
+		return
+	}
+	p.changesKey = key
+
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+	list := store.Changes(conversationID)
+	if len(list) == 0 {
+		p.changesPanel.SetLines([]string{muted.Render("(none)")})
+		return
+	}
+	added := lipgloss.NewStyle().Foreground(theme.Success)
+	removed := lipgloss.NewStyle().Foreground(theme.Error)
+	lines := make([]string, 0, len(list)+1)
+	for _, change := range list {
+		status := lipgloss.NewStyle().Foreground(theme.Warning).Render("M")
+		if change.Operation == "create" || (!change.Existed && change.Baseline != "") {
+			status = added.Render("A")
+		}
+		plus, minus := change.DiffStat()
+		line := fmt.Sprintf("%s %s", status, change.Display)
+		if plus > 0 || minus > 0 {
+			line += " " + added.Render(fmt.Sprintf("+%d", plus)) + " " + removed.Render(fmt.Sprintf("-%d", minus))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, muted.Render("/changes to review or revert"))
+	p.changesPanel.SetLines(lines)
+}
+
+// syncContext shows what the next request sends and how much of the
+// model's context window it fills.
+func (p *ChatPage) syncContext() {
+	if p.contextPanel == nil {
+		return
+	}
+	// Building the window stats mentioned files, so only do it when its
+	// inputs change.
+	theme := styles.CurrentTheme()
+	key := fmt.Sprintf("%s|%v%v%v%v", p.chat.ContextKey(), theme.Success, theme.Warning, theme.Error, theme.Muted)
+	if key == p.contextKey {
+		return
+	}
+	p.contextKey = key
+	window := p.chat.ContextWindow()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+
+	used := window.Used()
+	lines := make([]string, 0, 6)
+	if window.Limit > 0 {
+		fraction := window.Fraction()
+		color := theme.Success
+		switch {
+		case fraction >= 0.9:
+			color = theme.Error
+		case fraction >= 0.7:
+			color = theme.Warning
+		}
+		filled := min(int(fraction*contextBarWidth+0.5), contextBarWidth)
+		bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+			muted.Render(strings.Repeat("░", contextBarWidth-filled))
+		lines = append(lines, bar, fmt.Sprintf("~%s / %s tokens (%.0f%%)", usage.FormatTokens(used), usage.FormatTokens(window.Limit), fraction*100))
+	} else {
+		lines = append(lines, fmt.Sprintf("~%s tokens", usage.FormatTokens(used)), muted.Render("Model context size unknown"))
+	}
+	for _, kind := range []struct{ kind, label string }{
+		{agentcontext.KindFile, "Files"},
+		{agentcontext.KindImage, "Images"},
+		{agentcontext.KindTurn, "Prior turns"},
+	} {
+		total, dropped := window.Count(kind.kind)
+		if total == 0 {
+			continue
+		}
+		line := fmt.Sprintf("%s: %d", kind.label, total)
+		if dropped > 0 {
+			line += muted.Render(fmt.Sprintf(" (%d dropped)", dropped))
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, muted.Render("@path mentions a file · /context to drop"))
+	if joined := strings.Join(lines, "\n"); joined != p.contextLines {
+		p.contextLines = joined
+		p.contextPanel.SetLines(lines)
+	}
+}
+
+// syncUsage shows the token usage and cost of the active conversation and the
+// session, with any budgets, in the usage panel.
+func (p *ChatPage) syncUsage() {
+	if p.usagePanel == nil {
+		return
+	}
+	store := stores.SharedUsageStore()
+	conversationID := stores.SharedConversationStore().ActiveID()
+	key := fmt.Sprintf("%s@%d", conversationID, store.Version())
+	if key == p.usageKey {
+		return
+	}
+	p.usageKey = key
+
+	theme := styles.CurrentTheme()
+	muted := lipgloss.NewStyle().Foreground(theme.Muted)
+	conversation, session := store.Conversation(conversationID), store.Session()
+	if session.IsZero() {
+		p.usagePanel.SetLines([]string{muted.Render("No usage reported yet")})
+		return
+	}
+	lines := []string{"This chat: " + usage.Describe(conversation)}
+	if records := store.Records(conversationID); len(records) > 0 {
+		last := records[len(records)-1]
+		lines = append(lines, muted.Render("Last reply: "+usage.Describe(last.Usage)))
+	}
+	lines = append(lines, "Session: "+usage.Describe(session))
+
+	budgets := store.Budgets()
+	budgetLine := func(scope string, budget usage.Budget, used usage.Usage) {
+		if budget.IsZero() {
+			return
+		}
+		fraction := budget.Fraction(used)
+		style := muted
+		switch {
+		case fraction >= 1:
+			style = lipgloss.NewStyle().Foreground(theme.Error)
+		case fraction >= usage.WarnFraction:
+			style = lipgloss.NewStyle().Foreground(theme.Warning)
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%s budget: %.0f%% of %s", scope, fraction*100, budget)))
+	}
+	budgetLine("Chat", budgets.Conversation, conversation)
+	budgetLine("Session", budgets.Session, session)
+	lines = append(lines, muted.Render("/usage for per-reply details"))
+	p.usagePanel.SetLines(lines)
+}
+
 // View renders the chat page content.
 func (p *ChatPage) View() string {
 	if p.chat == nil {
@@ -107,14 +279,14 @@ func (p *ChatPage) seedRightSidebarDefaults() {
 			errorStyle.Render("puppeteer: disconnected"),
 		})
 	}
-	if p.modifiedPanel != nil {
-		p.modifiedPanel.SetLines([]string{muted.Render("(none)")})
+	if p.changesPanel != nil {
+		p.changesPanel.SetLines([]string{muted.Render("(none)")})
 	}
 	if p.nextTasksPanel != nil {
 		p.nextTasksPanel.SetLines([]string{muted.Render("No upcoming tasks")})
 	}
-	if p.contextPanel != nil {
-		p.contextPanel.SetLines([]string{"Tokens: 0 / 128K", "Uptime: 0m"})
+	if p.usagePanel != nil {
+		p.usagePanel.SetLines([]string{muted.Render("No usage reported yet")})
 	}
 }
 
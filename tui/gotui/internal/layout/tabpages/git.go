@@ -1,27 +1,142 @@
 package tabpages
 
 import (
+	"fmt"
+	"strings"
+
+	"gotui/internal/gitrepo"
+	"gotui/internal/keybindings"
 	"gotui/internal/layout/panels"
+	"gotui/internal/styles"
 
+	"github.com/charmbracelet/bubbles/v2/key"
+	"github.com/charmbracelet/bubbles/v2/textarea"
 	tea "github.com/charmbracelet/bubbletea/v2"
 	"github.com/charmbracelet/lipgloss/v2"
 )
 
+// GitOp identifies a git operation requested by the Git tab.
+type GitOp int
+
+const (
+	GitOpStatus GitOp = iota
+	GitOpDiff
+	GitOpStage
+	GitOpUnstage
+	GitOpDiscard
+	GitOpStageAll
+	GitOpCommit
+	// GitOpDraftCommit asks the agent for a commit message for the staged diff.
+	GitOpDraftCommit
+	// GitOpDraftPR asks the agent for a pull request description of the branch.
+	GitOpDraftPR
+	// GitOpLog loads a page of history, filtered to Path if set.
+	GitOpLog
+	// GitOpShow loads the details of commit Rev.
+	GitOpShow
+	// GitOpShowFile loads the diff commit Rev made to Path.
+	GitOpShowFile
+	// GitOpBlame blames Path at Rev, or the working tree copy if Rev is empty.
+	GitOpBlame
+)
+
+// GitLogPageSize is the number of commits loaded per history page.
+const GitLogPageSize = 200
+
+// GitRequestMsg asks the app to run a git operation for the Git tab.
+type GitRequestMsg struct {
+	Op   GitOp
+	Item panels.GitStatusItem
+	// Patch limits stage, unstage and discard to a single hunk.
+	Patch   string
+	Message string
+	Rev     string
+	Path    string
+	// OrigPath is the name Path had before being renamed in Rev.
+	OrigPath string
+	Skip     int
+}
+
+type gitFocus int
+
+const (
+	gitFocusFiles gitFocus = iota
+	gitFocusDiff
+	gitFocusLog
+	// gitFocusDetail is the commit or blame view beside the history.
+	gitFocusDetail
+)
+
+type gitView int
+
+const (
+	gitViewStatus gitView = iota
+	gitViewLog
+)
+
+type gitEditorMode int
+
+const (
+	gitEditorCommit gitEditorMode = iota
+	gitEditorPR
+)
+
+const commitEditorHeight = 8
+
 // GitPage composes the git-related panels for the Git tab.
 type GitPage struct {
 	width  int
 	height int
 
 	status  *panels.GitStatusPanel
+	diff    *panels.GitDiffPanel
 	commits *panels.GitCommitsPanel
+	log     *panels.GitLogPanel
+	commit  *panels.GitCommitPanel
+	blame   *panels.GitBlamePanel
+
+	view gitView
+	// blaming shows the blame view in place of the commit details.
+	blaming bool
+	focus   gitFocus
+	editor  textarea.Model
+	editing bool
+	mode    gitEditorMode
+	// otherDraft keeps the text of the editor mode not currently shown.
+	otherDraft string
+	// drafting is set while an agent-written draft streams into the editor.
+	drafting bool
+	confirm  *GitRequestMsg
+	prompt   string
+	notice   string
+	keyMap   keybindings.KeyMap
 }
 
 // NewGitPage constructs a git tab page with default content.
 func NewGitPage() *GitPage {
-	return &GitPage{
+	editor := textarea.New()
+	editor.Placeholder = "Commit message (first line is the summary)"
+	editor.ShowLineNumbers = false
+	editor.CharLimit = 0
+	editor.Prompt = ""
+
+	g := &GitPage{
 		status:  panels.NewGitStatusPanel(),
+		diff:    panels.NewGitDiffPanel(),
 		commits: panels.NewGitCommitsPanel(),
+		log:     panels.NewGitLogPanel(),
+		commit:  panels.NewGitCommitPanel(),
+		blame:   panels.NewGitBlamePanel(),
+		editor:  editor,
+		keyMap:  keybindings.DefaultKeyMap(),
 	}
+	g.applyFocus()
+	return g
+}
+
+// SetKeyMap sets the keys that commit and draft in the message editor.
+func (g *GitPage) SetKeyMap(km keybindings.KeyMap) {
+	g.keyMap = km
 }
 
 // SetSize allocates space for each git sub-panel.
@@ -29,57 +144,735 @@ func (g *GitPage) SetSize(width, height int) {
 	g.width = width
 	g.height = height
 
-	if height < 2 {
-		height = 2
+	bodyHeight := height - 1 // hint line
+	if g.view == gitViewLog && !g.editing {
+		logWidth := width / 2
+		if logWidth < 30 {
+			logWidth = min(30, width)
+		}
+		g.log.SetSize(logWidth, bodyHeight)
+		g.commit.SetSize(width-logWidth, bodyHeight)
+		g.blame.SetSize(width-logWidth, bodyHeight)
+		return
 	}
-
-	statusHeight := height / 2
-	if statusHeight < 3 {
-		statusHeight = 3
+	bottomHeight := commitEditorHeight
+	if !g.editing {
+		bottomHeight = bodyHeight / 4
+		if bottomHeight < 5 {
+			bottomHeight = 5
+		}
+		if bottomHeight > 8 {
+			bottomHeight = 8
+		}
 	}
-	if statusHeight > height {
-		statusHeight = height
+	topHeight := bodyHeight - bottomHeight
+	if topHeight < 5 {
+		topHeight = bodyHeight
+		bottomHeight = 0
 	}
 
-	commitsHeight := height - statusHeight
-	if commitsHeight < 3 && height >= 6 {
-		commitsHeight = 3
-		statusHeight = height - commitsHeight
+	listWidth := width * 2 / 5
+	if listWidth < 30 {
+		listWidth = 30
 	}
-	if commitsHeight < 0 {
-		commitsHeight = 0
+	if listWidth > width {
+		listWidth = width
 	}
 
-	g.status.SetSize(width, statusHeight)
-	g.commits.SetSize(width, commitsHeight)
+	g.status.SetSize(listWidth, topHeight)
+	g.diff.SetSize(width-listWidth, topHeight)
+	g.commits.SetSize(width, bottomHeight)
+	g.editor.SetWidth(width - 4)
+	g.editor.SetHeight(commitEditorHeight - 4)
 }
 
 // Update forwards messages to the git panels.
 func (g *GitPage) Update(msg tea.Msg) tea.Cmd {
+	if g.editing {
+		if _, ok := msg.(tea.KeyPressMsg); !ok {
+			var cmd tea.Cmd
+			g.editor, cmd = g.editor.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+	return g.commits.Update(msg)
+}
+
+// SetStatus replaces the file list and requests the diff of the selection.
+func (g *GitPage) SetStatus(status gitrepo.Status) tea.Cmd {
+	g.status.SetStatus(status)
+	return g.requestDiff()
+}
+
+// SetStatusError replaces the file list with an error message.
+func (g *GitPage) SetStatusError(message string) {
+	g.status.SetMessage(message)
+	g.diff.SetMessage("")
+}
+
+// SetDiff shows the diff for item if it is still selected.
+func (g *GitPage) SetDiff(item panels.GitStatusItem, diff gitrepo.FileDiff) {
+	if selected, ok := g.status.Selected(); ok && selected == item {
+		g.diff.SetDiff(item, diff)
+	}
+}
+
+// SetDiffError shows an error in place of item's diff.
+func (g *GitPage) SetDiffError(item panels.GitStatusItem, message string) {
+	if selected, ok := g.status.Selected(); ok && selected == item {
+		g.diff.SetMessage(message)
+	}
+}
+
+// SetCommits replaces the recent commits list.
+func (g *GitPage) SetCommits(lines []string) {
+	g.commits.SetLines(lines)
+}
+
+// SetLog adds a page of history requested by req. Pages for an outdated
+// filter or offset are dropped.
+func (g *GitPage) SetLog(req GitRequestMsg, lines []gitrepo.LogLine) tea.Cmd {
+	if req.Path != g.log.Path() || req.Skip != g.log.CommitCount() {
+		return nil
+	}
+	g.log.AppendPage(lines, GitLogPageSize)
+	return g.selectCommit()
+}
+
+// SetLogError shows an error in place of the history requested by req.
+func (g *GitPage) SetLogError(req GitRequestMsg, message string) {
+	if req.Path == g.log.Path() {
+		g.log.SetMessage(message)
+	}
+}
+
+// SetCommitDetail shows a commit's details if it is still selected and
+// requests the diff of its first file.
+func (g *GitPage) SetCommitDetail(detail gitrepo.CommitDetail) tea.Cmd {
+	if !g.commit.SetDetail(detail) {
+		return nil
+	}
+	return g.requestCommitFile()
+}
+
+// SetCommitError shows an error in place of commit rev.
+func (g *GitPage) SetCommitError(rev, message string) {
+	if g.commit.Hash() == rev {
+		g.commit.SetMessage(message)
+	}
+}
+
+// SetCommitFileDiff shows the diff commit rev made to path.
+func (g *GitPage) SetCommitFileDiff(rev, path string, diff gitrepo.FileDiff) {
+	g.commit.SetFileDiff(rev, path, diff)
+}
+
+// SetCommitFileDiffError shows an error in place of path's diff in rev.
+func (g *GitPage) SetCommitFileDiffError(rev, path, message string) {
+	g.commit.SetFileDiffError(rev, path, message)
+}
+
+// SetBlame shows the blame of path at rev if it is still wanted.
+func (g *GitPage) SetBlame(rev, path string, lines []gitrepo.BlameLine) {
+	if g.blame.Shows(rev, path) {
+		g.blame.SetBlame(lines)
+	}
+}
+
+// SetBlameError shows an error in place of the blame of path at rev.
+func (g *GitPage) SetBlameError(rev, path, message string) {
+	if g.blame.Shows(rev, path) {
+		g.blame.SetMessage(message)
+	}
+}
+
+// SetNotice shows the result of the last operation in the hint line.
+func (g *GitPage) SetNotice(notice string) {
+	g.notice = notice
+}
+
+// CommitDone clears and closes the commit editor after a successful commit.
+func (g *GitPage) CommitDone() {
+	g.editor.Reset()
+	g.closeEditor()
+}
+
+// Drafting reports whether the editor is waiting for an agent-written draft.
+func (g *GitPage) Drafting() bool { return g.drafting }
+
+// SetDraft replaces the editor text with the latest streamed draft. It is
+// ignored once the user has stopped the draft by editing.
+func (g *GitPage) SetDraft(text string) {
+	if !g.drafting {
+		return
+	}
+	g.editor.SetValue(text)
+}
+
+// DraftDone ends the current draft and shows notice in the hint line.
+func (g *GitPage) DraftDone(notice string) {
+	g.drafting = false
+	g.notice = notice
+}
+
+// CapturesInput reports whether keys should go to the page before global
+// shortcuts, e.g. while typing a commit message.
+func (g *GitPage) CapturesInput() bool {
+	return g.editing || g.confirm != nil
+}
+
+// Editing reports whether the commit editor is open.
+func (g *GitPage) Editing() bool { return g.editing }
+
+// HandleKey processes a key press on the Git tab.
+func (g *GitPage) HandleKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if g.confirm != nil {
+		req := *g.confirm
+		g.confirm = nil
+		g.prompt = ""
+		if msg.String() == "y" || msg.String() == "Y" {
+			return request(req), true
+		}
+		g.notice = "Discard cancelled"
+		return nil, true
+	}
+	if g.editing {
+		return g.handleEditorKey(msg), true
+	}
+	if g.view == gitViewLog {
+		return g.handleLogViewKey(msg)
+	}
+
+	switch msg.String() {
+	case "L":
+		return g.openLog(""), true
+	case "r":
+		return request(GitRequestMsg{Op: GitOpStatus}), true
+	case "c":
+		return g.openEditor(gitEditorCommit), true
+	case "m":
+		return g.startDraft(gitEditorCommit), true
+	case "P":
+		return g.startDraft(gitEditorPR), true
+	case "a":
+		return request(GitRequestMsg{Op: GitOpStageAll}), true
+	}
+
+	if g.focus == gitFocusDiff {
+		return g.handleDiffKey(msg)
+	}
+	return g.handleFilesKey(msg)
+}
+
+func (g *GitPage) handleFilesKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	item, hasItem := g.status.Selected()
+	switch msg.String() {
+	case "up", "k":
+		if g.status.Move(-1) {
+			return g.requestDiff(), true
+		}
+		return nil, true
+	case "down", "j":
+		if g.status.Move(1) {
+			return g.requestDiff(), true
+		}
+		return nil, true
+	case "enter", "right", "l":
+		if hasItem {
+			g.focus = gitFocusDiff
+			g.applyFocus()
+		}
+		return nil, true
+	case "H":
+		if hasItem {
+			return g.openLog(item.Entry.Path), true
+		}
+		return nil, true
+	case "b":
+		switch {
+		case !hasItem:
+		case item.Entry.Untracked():
+			g.notice = "Untracked files have no history to blame"
+		default:
+			return tea.Batch(g.openLog(item.Entry.Path), g.openBlame("", item.Entry.Path)), true
+		}
+		return nil, true
+	case "space", " ":
+		if !hasItem {
+			return nil, true
+		}
+		if item.Staged {
+			return request(GitRequestMsg{Op: GitOpUnstage, Item: item}), true
+		}
+		return request(GitRequestMsg{Op: GitOpStage, Item: item}), true
+	case "s":
+		if hasItem {
+			return g.fileRequest(GitOpStage, item), true
+		}
+		return nil, true
+	case "u":
+		if hasItem {
+			return g.fileRequest(GitOpUnstage, item), true
+		}
+		return nil, true
+	case "d":
+		if hasItem {
+			return g.fileRequest(GitOpDiscard, item), true
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+func (g *GitPage) handleDiffKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	item, hasItem := g.status.Selected()
+	switch msg.String() {
+	case "esc", "left", "h":
+		g.focus = gitFocusFiles
+		g.applyFocus()
+		return nil, true
+	case "down", "j", "n":
+		g.diff.MoveHunk(1)
+		return nil, true
+	case "up", "k", "p":
+		g.diff.MoveHunk(-1)
+		return nil, true
+	case "pgdown", "J":
+		g.diff.Scroll(10)
+		return nil, true
+	case "pgup", "K":
+		g.diff.Scroll(-10)
+		return nil, true
+	case "s", "u", "d", "space", " ":
+		if !hasItem {
+			return nil, true
+		}
+		op := map[string]GitOp{"s": GitOpStage, "u": GitOpUnstage, "d": GitOpDiscard}[msg.String()]
+		if msg.String() == "space" || msg.String() == " " {
+			op = GitOpStage
+			if item.Staged {
+				op = GitOpUnstage
+			}
+		}
+		return g.hunkRequest(op, item), true
+	}
+	return nil, false
+}
+
+func (g *GitPage) handleLogViewKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "L":
+		g.closeLog()
+		return nil, true
+	case "r":
+		return g.openLog(g.log.Path()), true
+	}
+	switch {
+	case g.focus != gitFocusDetail:
+		return g.handleLogKey(msg)
+	case g.blaming:
+		return g.handleBlameKey(msg)
+	}
+	return g.handleCommitKey(msg)
+}
+
+func (g *GitPage) handleLogKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	delta := 0
+	switch msg.String() {
+	case "esc":
+		g.closeLog()
+		return nil, true
+	case "up", "k":
+		delta = -1
+	case "down", "j":
+		delta = 1
+	case "pgup", "K":
+		delta = -g.log.PageSize()
+	case "pgdown", "J":
+		delta = g.log.PageSize()
+	case "home", "g":
+		delta = -g.log.CommitCount()
+	case "end", "G":
+		delta = g.log.CommitCount()
+	case "enter", "right", "l":
+		if _, ok := g.log.Selected(); ok {
+			g.focus = gitFocusDetail
+			g.applyFocus()
+		}
+		return nil, true
+	case "H":
+		if g.log.Path() != "" {
+			return g.openLog(""), true
+		}
+		g.notice = "Open a file's history with H on the file"
+		return nil, true
+	default:
+		return nil, false
+	}
+	if g.log.Move(delta) {
+		g.blaming = false
+		return g.selectCommit(), true
+	}
+	return nil, true
+}
+
+func (g *GitPage) handleCommitKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	file, hasFile := g.commit.SelectedFile()
+	switch msg.String() {
+	case "esc", "left", "h":
+		g.focus = gitFocusLog
+		g.applyFocus()
+		return nil, true
+	case "down", "j":
+		if g.commit.MoveFile(1) {
+			return g.requestCommitFile(), true
+		}
+		return nil, true
+	case "up", "k":
+		if g.commit.MoveFile(-1) {
+			return g.requestCommitFile(), true
+		}
+		return nil, true
+	case "pgdown", "J":
+		g.commit.Scroll(10)
+		return nil, true
+	case "pgup", "K":
+		g.commit.Scroll(-10)
+		return nil, true
+	case "H":
+		if hasFile {
+			return g.openLog(file.Path), true
+		}
+		return nil, true
+	case "b":
+		if hasFile {
+			return g.openBlame(g.commit.Hash(), file.Path), true
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+func (g *GitPage) handleBlameKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "esc", "left", "h":
+		g.blaming = false
+		if g.commit.Hash() == "" {
+			g.focus = gitFocusLog
+			g.applyFocus()
+		}
+		return nil, true
+	case "down", "j":
+		g.blame.Move(1)
+	case "up", "k":
+		g.blame.Move(-1)
+	case "pgdown", "J":
+		g.blame.Move(g.blame.PageSize())
+	case "pgup", "K":
+		g.blame.Move(-g.blame.PageSize())
+	case "enter":
+		line, ok := g.blame.Selected()
+		if !ok || !panels.Committed(line) {
+			g.notice = "Line is not committed yet"
+			return nil, true
+		}
+		g.blaming = false
+		if g.log.Select(line.Hash) {
+			return g.selectCommit(), true
+		}
+		g.commit.SetLoading(line.Hash)
+		return request(GitRequestMsg{Op: GitOpShow, Rev: line.Hash}), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// openLog switches to the history view, filtered to path if set.
+func (g *GitPage) openLog(path string) tea.Cmd {
+	g.view = gitViewLog
+	g.blaming = false
+	g.focus = gitFocusLog
+	g.applyFocus()
+	g.SetSize(g.width, g.height)
+	g.log.Reset(path)
+	return request(GitRequestMsg{Op: GitOpLog, Path: path})
+}
+
+// openBlame shows the blame of path at rev beside the history.
+func (g *GitPage) openBlame(rev, path string) tea.Cmd {
+	g.blaming = true
+	g.focus = gitFocusDetail
+	g.applyFocus()
+	g.blame.SetLoading(rev, path)
+	return request(GitRequestMsg{Op: GitOpBlame, Rev: rev, Path: path})
+}
+
+func (g *GitPage) closeLog() {
+	g.view = gitViewStatus
+	g.blaming = false
+	g.focus = gitFocusFiles
+	g.applyFocus()
+	g.SetSize(g.width, g.height)
+}
+
+// selectCommit loads the highlighted commit and, near the end of the loaded
+// history, the next page.
+func (g *GitPage) selectCommit() tea.Cmd {
 	var cmds []tea.Cmd
-	if cmd := g.status.Update(msg); cmd != nil {
-		cmds = append(cmds, cmd)
+	if commit, ok := g.log.Selected(); ok && commit.Hash != g.commit.Hash() {
+		g.commit.SetLoading(commit.Hash)
+		cmds = append(cmds, request(GitRequestMsg{Op: GitOpShow, Rev: commit.Hash}))
 	}
-	if cmd := g.commits.Update(msg); cmd != nil {
-		cmds = append(cmds, cmd)
+	if g.log.NeedsMore() {
+		cmds = append(cmds, request(GitRequestMsg{Op: GitOpLog, Path: g.log.Path(), Skip: g.log.CommitCount()}))
 	}
 	return tea.Batch(cmds...)
 }
 
+func (g *GitPage) requestCommitFile() tea.Cmd {
+	file, ok := g.commit.SelectedFile()
+	if !ok {
+		return nil
+	}
+	g.commit.SetFileLoading(file.Path)
+	return request(GitRequestMsg{Op: GitOpShowFile, Rev: g.commit.Hash(), Path: file.Path, OrigPath: file.OrigPath})
+}
+
+// fileRequest validates op against the item's section before requesting it.
+func (g *GitPage) fileRequest(op GitOp, item panels.GitStatusItem) tea.Cmd {
+	switch {
+	case op == GitOpStage && item.Staged:
+		g.notice = "Already staged; select the unstaged entry to stage further changes"
+		return nil
+	case op == GitOpUnstage && !item.Staged:
+		g.notice = "Nothing staged for " + item.Entry.Path
+		return nil
+	case op == GitOpDiscard && item.Staged:
+		g.notice = "Unstage the change before discarding it"
+		return nil
+	case op == GitOpDiscard:
+		action := "Discard changes to"
+		if item.Entry.Untracked() {
+			action = "Delete untracked file"
+		}
+		g.askConfirm(fmt.Sprintf("%s %s?", action, item.Entry.Path), GitRequestMsg{Op: op, Item: item})
+		return nil
+	}
+	return request(GitRequestMsg{Op: op, Item: item})
+}
+
+// hunkRequest applies op to the hunk under the cursor. Untracked files have no
+// index entry to patch, so they fall back to whole-file operations.
+func (g *GitPage) hunkRequest(op GitOp, item panels.GitStatusItem) tea.Cmd {
+	hunk := g.diff.SelectedHunk()
+	// Staged hunks can only be unstaged; unstaged hunks staged or discarded.
+	allowed := (item.Staged && op == GitOpUnstage) || (!item.Staged && op != GitOpUnstage)
+	if item.Entry.Untracked() || hunk < 0 || !g.diff.Shows(item) || !allowed {
+		return g.fileRequest(op, item)
+	}
+	patch, err := g.diff.Diff().HunkPatch(hunk)
+	if err != nil {
+		g.notice = err.Error()
+		return nil
+	}
+	req := GitRequestMsg{Op: op, Item: item, Patch: patch}
+	if op == GitOpDiscard {
+		g.askConfirm(fmt.Sprintf("Discard hunk %d of %s?", hunk+1, item.Entry.Path), req)
+		return nil
+	}
+	return request(req)
+}
+
+func (g *GitPage) askConfirm(prompt string, req GitRequestMsg) {
+	g.confirm = &req
+	g.prompt = prompt + " (y/N)"
+}
+
+func (g *GitPage) handleEditorKey(msg tea.KeyPressMsg) tea.Cmd {
+	switch {
+	case msg.String() == "esc":
+		g.closeEditor()
+		g.notice = "Kept as draft"
+		return nil
+	case key.Matches(msg, g.keyMap.GitDraft):
+		return g.startDraft(g.mode)
+	case key.Matches(msg, g.keyMap.GitCommit):
+		text := strings.TrimSpace(g.editor.Value())
+		if text == "" {
+			g.notice = "Nothing written yet"
+			return nil
+		}
+		g.drafting = false
+		if g.mode == gitEditorPR {
+			g.notice = "Pull request description copied to clipboard"
+			return tea.SetClipboard(text)
+		}
+		return request(GitRequestMsg{Op: GitOpCommit, Message: text})
+	}
+	if g.drafting {
+		// Typing takes over from the agent; later chunks are ignored.
+		g.drafting = false
+		g.notice = "Draft stopped; editing by hand"
+	}
+	var cmd tea.Cmd
+	g.editor, cmd = g.editor.Update(msg)
+	return cmd
+}
+
+func (g *GitPage) openEditor(mode gitEditorMode) tea.Cmd {
+	if mode != g.mode {
+		current := g.editor.Value()
+		g.editor.SetValue(g.otherDraft)
+		g.otherDraft = current
+		g.mode = mode
+		g.drafting = false
+	}
+	if mode == gitEditorCommit && g.stagedCount() == 0 {
+		g.notice = "Nothing staged to commit"
+	}
+	g.editing = true
+	g.SetSize(g.width, g.height)
+	return g.editor.Focus()
+}
+
+// startDraft opens the editor for mode and asks the app to have the agent
+// write its contents.
+func (g *GitPage) startDraft(mode gitEditorMode) tea.Cmd {
+	if mode == gitEditorCommit && g.stagedCount() == 0 {
+		g.notice = "Stage changes before drafting a commit message"
+		return nil
+	}
+	focus := g.openEditor(mode)
+	g.editor.Reset()
+	g.drafting = true
+	op := GitOpDraftCommit
+	if mode == gitEditorPR {
+		op = GitOpDraftPR
+	}
+	return tea.Batch(focus, request(GitRequestMsg{Op: op}))
+}
+
+func (g *GitPage) stagedCount() int {
+	staged := 0
+	for _, item := range g.status.Items() {
+		if item.Staged {
+			staged++
+		}
+	}
+	return staged
+}
+
+func (g *GitPage) closeEditor() {
+	g.editing = false
+	g.drafting = false
+	g.editor.Blur()
+	g.SetSize(g.width, g.height)
+}
+
+func (g *GitPage) applyFocus() {
+	g.status.SetActive(g.focus == gitFocusFiles)
+	g.diff.SetActive(g.focus == gitFocusDiff)
+	g.log.SetActive(g.focus == gitFocusLog)
+	g.commit.SetActive(g.focus == gitFocusDetail)
+	g.blame.SetActive(g.focus == gitFocusDetail)
+}
+
+func (g *GitPage) requestDiff() tea.Cmd {
+	item, ok := g.status.Selected()
+	if !ok {
+		g.diff.SetMessage("Select a file to preview its diff.")
+		if g.focus == gitFocusDiff {
+			g.focus = gitFocusFiles
+			g.applyFocus()
+		}
+		return nil
+	}
+	g.diff.SetLoading(item)
+	return request(GitRequestMsg{Op: GitOpDiff, Item: item})
+}
+
+func request(req GitRequestMsg) tea.Cmd {
+	return func() tea.Msg { return req }
+}
+
 // View renders the git tab content.
 func (g *GitPage) View() string {
+	if g.view == gitViewLog && !g.editing {
+		detail := g.commit.View()
+		if g.blaming {
+			detail = g.blame.View()
+		}
+		return lipgloss.NewStyle().
+			Width(g.width).
+			Height(g.height).
+			Render(lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Top, g.log.View(), detail), g.hintLine()))
+	}
+	top := lipgloss.JoinHorizontal(lipgloss.Top, g.status.View(), g.diff.View())
+	bottom := g.commits.View()
+	if g.editing {
+		bottom = g.editorView()
+	}
 	return lipgloss.NewStyle().
 		Width(g.width).
 		Height(g.height).
-		Render(lipgloss.JoinVertical(
-			lipgloss.Left,
-			g.status.View(),
-			g.commits.View(),
-		))
+		Render(lipgloss.JoinVertical(lipgloss.Left, top, bottom, g.hintLine()))
 }
 
-// Refresh replaces the git status and commit information.
-func (g *GitPage) Refresh(statusLines, commitLines []string) {
-	g.status.SetLines(statusLines)
-	g.commits.SetLines(commitLines)
+func (g *GitPage) editorView() string {
+	theme := styles.CurrentTheme()
+	label := "Commit message"
+	if g.mode == gitEditorPR {
+		label = "Pull request description"
+	}
+	title := lipgloss.NewStyle().Foreground(theme.Primary).Bold(true).Render(label)
+	if g.drafting {
+		title += lipgloss.NewStyle().Foreground(theme.Accent).Render("  ✨ drafting…")
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(theme.Primary).
+		Padding(0, 1).
+		Width(g.width).
+		Height(commitEditorHeight).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, g.editor.View()))
+}
+
+// editorHint names b's key for an editor action, or "" if it is unbound.
+func editorHint(b key.Binding, action string) string {
+	if !b.Enabled() || b.Help().Key == "" {
+		return ""
+	}
+	return b.Help().Key + " " + action + " • "
+}
+
+func (g *GitPage) hintLine() string {
+	theme := styles.CurrentTheme()
+	var text string
+	switch {
+	case g.confirm != nil:
+		return lipgloss.NewStyle().Foreground(theme.Warning).Bold(true).Width(g.width).MaxWidth(g.width).Render(g.prompt)
+	case g.editing && g.mode == gitEditorPR:
+		text = editorHint(g.keyMap.GitCommit, "copy") + editorHint(g.keyMap.GitDraft, "redraft") + "esc close"
+	case g.editing:
+		text = editorHint(g.keyMap.GitCommit, "commit") + editorHint(g.keyMap.GitDraft, "draft with agent") + "esc close"
+	case g.view == gitViewLog && g.focus == gitFocusDetail && g.blaming:
+		text = "j/k line • pgup/pgdn or K/J page • enter open commit • esc close blame • L changes"
+	case g.view == gitViewLog && g.focus == gitFocusDetail:
+		text = "j/k file • pgup/pgdn or K/J scroll • b blame file • H file history • esc history • L changes"
+	case g.view == gitViewLog:
+		text = "j/k select • pgup/pgdn or K/J page • g/G first/last • enter details • r reload • esc or L changes"
+		if g.log.Path() != "" {
+			text += " • H all files"
+		}
+	case g.focus == gitFocusDiff:
+		text = "j/k hunk • s stage • u unstage • d discard • pgup/pgdn or K/J scroll • esc files • c commit • m draft message"
+	default:
+		text = "j/k select • space toggle • s stage • u unstage • d discard • a stage all • enter hunks • c commit • m draft message • P draft PR • L history • H file history • b blame • r refresh"
+	}
+	if g.notice != "" {
+		text = g.notice + "  │  " + text
+	}
+	return lipgloss.NewStyle().Foreground(theme.Muted).Width(g.width).MaxWidth(g.width).MaxHeight(1).Render(text)
 }
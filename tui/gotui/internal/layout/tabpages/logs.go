@@ -2,6 +2,7 @@ package tabpages
 
 import (
 	"gotui/internal/layout/panels"
+	"gotui/internal/stores"
 	"gotui/internal/styles"
 	"gotui/internal/wsclient"
 
@@ -9,6 +10,12 @@ import (
 	"github.com/charmbracelet/lipgloss/v2"
 )
 
+// LogExportMsg asks the app to write a log panel's filtered entries to a file.
+type LogExportMsg struct {
+	Panel   string
+	Entries []panels.LogEntry
+}
+
 // LogsPage renders the logs tab by composing the various sidebar panels.
 type LogsPage struct {
 	width    int
@@ -31,6 +38,10 @@ type LogsPage struct {
 	showAgent  bool
 	showNotifs bool
 
+	// serverFocused directs log keys to the server panel instead of the
+	// general one.
+	serverFocused bool
+
 	// Connection state
 	retryCount int
 	isRetrying bool
@@ -39,7 +50,7 @@ type LogsPage struct {
 
 // NewLogsPage builds a new logs tab page backed by the shared panels.
 func NewLogsPage(wsClient *wsclient.Client, host string, port int) *LogsPage {
-	return &LogsPage{
+	p := &LogsPage{
 		wsClient:    wsClient,
 		host:        host,
 		port:        port,
@@ -54,6 +65,8 @@ func NewLogsPage(wsClient *wsclient.Client, host string, port int) *LogsPage {
 		showAgent:   true,
 		showNotifs:  true,
 	}
+	p.applyFocus()
+	return p
 }
 
 // SetSize updates the layout dimensions for the logs page.
@@ -104,6 +117,21 @@ func (p *LogsPage) SetRetryInfo(count int, retrying bool, lastError string) {
 	p.lastError = lastError
 }
 
+// SetConnection switches the page to a new client and server address.
+func (p *LogsPage) SetConnection(wsClient *wsclient.Client, host string, port int) {
+	p.wsClient = wsClient
+	p.host = host
+	p.port = port
+	p.retryCount, p.isRetrying, p.lastError = 0, false, ""
+	p.statusPanel.SetTarget(wsClient, host, port)
+}
+
+// SetAuth records how the client authenticates with the server.
+func (p *LogsPage) SetAuth(mode string) { p.statusPanel.SetAuth(mode) }
+
+// SetAuthError shows (or, with an empty message, clears) an authentication failure.
+func (p *LogsPage) SetAuthError(message string) { p.statusPanel.SetAuthError(message) }
+
 // ConnectionPanel exposes the underlying connection panel.
 func (p *LogsPage) ConnectionPanel() *panels.ConnectionPanel { return p.statusPanel }
 
@@ -119,6 +147,55 @@ func (p *LogsPage) AgentPanel() *panels.AgentPanel { return p.agentPanel }
 // NotificationsPanel exposes the notifications panel.
 func (p *LogsPage) NotificationsPanel() *panels.NotificationsPanel { return p.notifPanel }
 
+// ShowsNotifications reports whether the notifications panel is visible, so
+// notifications count as read while the page is open.
+func (p *LogsPage) ShowsNotifications() bool { return p.showNotifs }
+
+// CapturesInput reports whether keys should go to the page before global
+// shortcuts, e.g. while typing a search.
+func (p *LogsPage) CapturesInput() bool {
+	return p.focusedLogs().Searching()
+}
+
+// HandleKey processes a key press on the Logs tab.
+func (p *LogsPage) HandleKey(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	focused := p.focusedLogs()
+	if !focused.Searching() {
+		switch msg.String() {
+		case "tab":
+			if p.showLogs && p.showServer {
+				p.serverFocused = !p.serverFocused
+				p.applyFocus()
+			}
+			return nil, true
+		case "X":
+			if p.showNotifs {
+				stores.SharedNotificationStore().Clear()
+				return nil, true
+			}
+		case "e":
+			entries := focused.Entries()
+			title := focused.Title()
+			return func() tea.Msg { return LogExportMsg{Panel: title, Entries: entries} }, true
+		}
+	}
+	return focused.HandleKey(msg)
+}
+
+// focusedLogs returns the log panel receiving keys, preferring a visible one.
+func (p *LogsPage) focusedLogs() *panels.LogsPanel {
+	if (p.serverFocused && p.showServer) || !p.showLogs {
+		return p.serverPanel
+	}
+	return p.logsPanel
+}
+
+func (p *LogsPage) applyFocus() {
+	focused := p.focusedLogs()
+	p.logsPanel.SetActive(focused == p.logsPanel)
+	p.serverPanel.SetActive(focused == p.serverPanel)
+}
+
 // ToggleStatus toggles the connection panel visibility.
 func (p *LogsPage) ToggleStatus() {
 	p.showStatus = !p.showStatus
@@ -129,12 +206,14 @@ func (p *LogsPage) ToggleStatus() {
 func (p *LogsPage) ToggleLogs() {
 	p.showLogs = !p.showLogs
 	p.logsPanel.SetVisible(p.showLogs)
+	p.applyFocus()
 }
 
 // ToggleServer toggles the server logs panel visibility.
 func (p *LogsPage) ToggleServer() {
 	p.showServer = !p.showServer
 	p.serverPanel.SetVisible(p.showServer)
+	p.applyFocus()
 }
 
 // ToggleAgent toggles the agent panel visibility.
@@ -192,12 +271,12 @@ func (p *LogsPage) View() string {
 			Align(lipgloss.Center, lipgloss.Center).
 			Render(lipgloss.NewStyle().
 				Foreground(theme.Muted).
-				Render("Sidebar hidden\nPress Ctrl+S/L/V/A/N\nto show panels"),
+				Render("Sidebar hidden\nUse the panel toggle\nkeys to show panels"),
 			)
 	}
 
 	borderSpace := visiblePanels * 3
-	available := p.height - borderSpace
+	available := p.height - 1 - borderSpace // hint line
 	if available < visiblePanels {
 		available = visiblePanels * 3
 	}
@@ -229,6 +308,7 @@ func (p *LogsPage) View() string {
 		blocks = append(blocks, p.notifPanel.View())
 	}
 
+	blocks = append(blocks, p.hintLine())
 	content := lipgloss.JoinVertical(lipgloss.Left, blocks...)
 
 	return lipgloss.NewStyle().
@@ -236,3 +316,18 @@ func (p *LogsPage) View() string {
 		Height(p.height).
 		Render(content)
 }
+
+func (p *LogsPage) hintLine() string {
+	theme := styles.CurrentTheme()
+	text := "j/k scroll • v level • s source • / search • n/N match • p pause • f follow • e export • c clear"
+	if p.showLogs && p.showServer {
+		text += " • tab switch panel"
+	}
+	if p.showNotifs {
+		text += " • X clear notifications"
+	}
+	if p.focusedLogs().Searching() {
+		text = "type to search • enter keep • esc clear"
+	}
+	return lipgloss.NewStyle().Foreground(theme.Muted).Width(p.width).MaxWidth(p.width).MaxHeight(1).Render(text)
+}
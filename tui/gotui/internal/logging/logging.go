@@ -1,69 +1,117 @@
 package logging
 
 import (
+	"context"
+	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"os"
-	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
-const defaultFlags = log.Ldate | log.Ltime | log.Lmicroseconds
+// Options configures the shared logger.
+type Options struct {
+	// Path is the log file; empty keeps the current output.
+	Path string
+	// Truncate starts a fresh file instead of appending. With backups enabled
+	// the previous file is rotated away first.
+	Truncate bool
+	// Level is the minimum level written.
+	Level slog.Level
+	// MaxSize rotates the file once it would grow past this many bytes; zero
+	// disables rotation.
+	MaxSize int64
+	// MaxBackups is how many rotated files are kept as path.1, path.2, ...
+	MaxBackups int
+	// RedactBodies replaces message contents with their length.
+	RedactBodies bool
+}
 
 var (
 	initOnce sync.Once
-	logger   *log.Logger
-	logFile  *os.File
-	mu       sync.RWMutex
+	logger   *slog.Logger
+	level    slog.LevelVar
+	bodies   atomic.Bool
+	tee      atomic.Pointer[func(slog.Record)]
+
+	mu      sync.RWMutex
+	output  io.Writer = os.Stdout
+	logFile io.Closer
 )
 
+// outputWriter forwards to the current output so the handler survives
+// reconfiguration.
+type outputWriter struct{}
+
+func (outputWriter) Write(p []byte) (int, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return output.Write(p)
+}
+
 func ensureLogger() {
 	initOnce.Do(func() {
-		logger = log.New(os.Stdout, "", defaultFlags)
+		bodies.Store(true)
+		text := slog.NewTextHandler(outputWriter{}, &slog.HandlerOptions{Level: &level})
+		logger = slog.New(&redactHandler{
+			next:   text,
+			bodies: bodies.Load,
+			tee: func() func(slog.Record) {
+				if fn := tee.Load(); fn != nil {
+					return *fn
+				}
+				return nil
+			},
+		})
 	})
 }
 
-// ConfigureFile configures the shared logger to write to the given path.
-// When truncate is true, the file will be overwritten on each run; otherwise logs are appended.
-func ConfigureFile(path string, truncate bool) error {
+// Configure applies opts, replacing any previously opened log file.
+func Configure(opts Options) error {
 	ensureLogger()
-	flags := os.O_CREATE | os.O_WRONLY
-	if truncate {
-		flags |= os.O_TRUNC
-	} else {
-		flags |= os.O_APPEND
+	level.Set(opts.Level)
+	bodies.Store(opts.RedactBodies)
+	if opts.Path == "" {
+		return nil
 	}
-
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+	file, err := openRotating(opts.Path, opts.Truncate, opts.MaxSize, opts.MaxBackups)
+	if err != nil {
 		return err
 	}
+	setOutput(file, file)
+	return nil
+}
 
-	file, err := os.OpenFile(path, flags, 0o644)
+// ConfigureFile configures the shared logger to write to the given path.
+// When truncate is true, the file will be overwritten on each run; otherwise logs are appended.
+func ConfigureFile(path string, truncate bool) error {
+	ensureLogger()
+	file, err := openRotating(path, truncate, 0, 0)
 	if err != nil {
 		return err
 	}
-
-	mu.Lock()
-	if logFile != nil {
-		_ = logFile.Close()
-	}
-	logger.SetOutput(file)
-	logFile = file
-	mu.Unlock()
-
+	setOutput(file, file)
 	return nil
 }
 
 // SetOutput allows callers to redirect log output to the supplied writer.
 func SetOutput(w io.Writer) {
 	ensureLogger()
+	setOutput(w, nil)
+}
+
+func setOutput(w io.Writer, closer io.Closer) {
 	mu.Lock()
+	defer mu.Unlock()
 	if logFile != nil {
 		_ = logFile.Close()
-		logFile = nil
 	}
-	logger.SetOutput(w)
-	mu.Unlock()
+	output = w
+	logFile = closer
 }
 
 // Close releases the current log file, if any.
@@ -74,34 +122,92 @@ func Close() error {
 	if logFile != nil {
 		err := logFile.Close()
 		logFile = nil
-		logger.SetOutput(os.Stdout)
+		output = os.Stdout
 		return err
 	}
 	return nil
 }
 
-// Logger returns the shared *log.Logger instance.
-func Logger() *log.Logger {
+// SetLevel changes the minimum level written.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Level returns the minimum level written.
+func Level() slog.Level {
+	return level.Level()
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) to a slog.Level.
+func ParseLevel(name string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", name)
+	}
+	return l, nil
+}
+
+// SetTee installs fn to receive every record that passes the level filter,
+// already redacted. Passing nil removes it. fn is called from the logging
+// goroutine and must not block.
+func SetTee(fn func(slog.Record)) {
+	if fn == nil {
+		tee.Store(nil)
+		return
+	}
+	tee.Store(&fn)
+}
+
+// Logger returns the shared *slog.Logger instance.
+func Logger() *slog.Logger {
 	ensureLogger()
 	return logger
 }
 
-// Printf writes a formatted log entry using the shared logger.
+// Debug logs msg with key/value attributes at debug level.
+func Debug(msg string, args ...any) { write(slog.LevelDebug, msg, args...) }
+
+// Info logs msg with key/value attributes at info level.
+func Info(msg string, args ...any) { write(slog.LevelInfo, msg, args...) }
+
+// Warn logs msg with key/value attributes at warn level.
+func Warn(msg string, args ...any) { write(slog.LevelWarn, msg, args...) }
+
+// Error logs msg with key/value attributes at error level.
+func Error(msg string, args ...any) { write(slog.LevelError, msg, args...) }
+
+// write logs with the caller of the exported helper as the record's source.
+func write(l slog.Level, msg string, args ...any) {
+	ensureLogger()
+	ctx := context.Background()
+	if !logger.Enabled(ctx, l) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	record := slog.NewRecord(time.Now(), l, msg, pcs[0])
+	record.Add(args...)
+	_ = logger.Handler().Handle(ctx, record)
+}
+
+// Printf writes a formatted info entry using the shared logger.
 func Printf(format string, args ...any) {
-	Logger().Printf(format, args...)
+	write(slog.LevelInfo, fmt.Sprintf(format, args...))
 }
 
-// Println writes a log entry using the shared logger.
+// Println writes an info entry using the shared logger.
 func Println(args ...any) {
-	Logger().Println(args...)
+	write(slog.LevelInfo, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
 }
 
-// Fatalf writes a formatted log entry and exits.
+// Fatalf writes a formatted error entry and exits.
 func Fatalf(format string, args ...any) {
-	Logger().Fatalf(format, args...)
+	write(slog.LevelError, fmt.Sprintf(format, args...))
+	os.Exit(1)
 }
 
-// Fatal writes a log entry and exits.
+// Fatal writes an error entry and exits.
 func Fatal(args ...any) {
-	Logger().Fatal(args...)
+	write(slog.LevelError, fmt.Sprint(args...))
+	os.Exit(1)
 }
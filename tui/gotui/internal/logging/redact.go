@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretKeys are attribute key fragments whose values are never logged.
+var secretKeys = []string{"token", "password", "secret", "authorization", "apikey", "api_key", "api-key", "cookie", "credential"}
+
+// bodyKeys are attributes holding message contents, hidden when bodies are
+// redacted.
+var bodyKeys = map[string]bool{"body": true, "content": true, "payload": true, "text": true, "data": true}
+
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(bearer\s+)[^\s"',]+`),
+	regexp.MustCompile(`(?i)((?:token|api[_-]?key|password|secret|authorization)["']?\s*[:=]\s*["']?)[^\s"'&,}]+`),
+}
+
+// Scrub masks credentials embedded in free-form text.
+func Scrub(s string) string {
+	for _, pattern := range secretPatterns {
+		s = pattern.ReplaceAllString(s, "${1}"+redacted)
+	}
+	return s
+}
+
+func isSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, fragment := range secretKeys {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHandler masks secrets, and optionally message bodies, before records
+// reach the file and the tee.
+type redactHandler struct {
+	next   slog.Handler
+	bodies func() bool
+	tee    func() func(slog.Record)
+	attrs  []slog.Attr
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	clean := slog.NewRecord(record.Time, record.Level, Scrub(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		clean.AddAttrs(h.redact(attr))
+		return true
+	})
+	if tee := h.tee(); tee != nil {
+		teed := clean.Clone()
+		teed.AddAttrs(h.attrs...)
+		tee(teed)
+	}
+	return h.next.Handle(ctx, clean)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clean := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		clean[i] = h.redact(attr)
+	}
+	return &redactHandler{
+		next:   h.next.WithAttrs(clean),
+		bodies: h.bodies,
+		tee:    h.tee,
+		attrs:  append(append([]slog.Attr(nil), h.attrs...), clean...),
+	}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	return &redactHandler{next: h.next.WithGroup(name), bodies: h.bodies, tee: h.tee, attrs: h.attrs}
+}
+
+func (h *redactHandler) redact(attr slog.Attr) slog.Attr {
+	value := attr.Value.Resolve()
+	switch {
+	case isSecretKey(attr.Key):
+		return slog.String(attr.Key, redacted)
+	case bodyKeys[strings.ToLower(attr.Key)] && h.bodies():
+		return slog.String(attr.Key, fmt.Sprintf("[%d bytes redacted]", len(value.String())))
+	case value.Kind() == slog.KindGroup:
+		group := value.Group()
+		clean := make([]any, len(group))
+		for i, member := range group {
+			clean[i] = h.redact(member)
+		}
+		return slog.Group(attr.Key, clean...)
+	case value.Kind() == slog.KindString:
+		return slog.String(attr.Key, Scrub(value.String()))
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// rotatingFile is an io.Writer that moves the file aside once it grows past
+// maxSize, keeping up to maxBackups older files as path.1, path.2, ...
+type rotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	size       int64
+	maxSize    int64
+	maxBackups int
+}
+
+func openRotating(path string, truncate bool, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if truncate && maxBackups > 0 {
+		// Keep the previous session's log as a backup instead of losing it.
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			if err := r.shift(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := r.open(truncate); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open(truncate bool) error {
+	flags := os.O_CREATE | os.O_WRONLY
+	if truncate {
+		flags |= os.O_TRUNC
+	} else {
+		flags |= os.O_APPEND
+	}
+	file, err := os.OpenFile(r.path, flags, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+// Write appends p, rotating first if it would exceed the size limit.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate moves the file aside and starts a new one. When the backups cannot
+// be shifted the file is reopened for appending, so logging goes on and the
+// rotation is tried again on a later write.
+func (r *rotatingFile) rotate() error {
+	err := r.file.Close()
+	r.file = nil
+	if err == nil {
+		err = r.shift()
+	}
+	if err != nil {
+		if openErr := r.open(false); openErr != nil {
+			return errors.Join(err, openErr)
+		}
+		return nil
+	}
+	return r.open(true)
+}
+
+// shift renames path.N-1 to path.N down to path to path.1, dropping the
+// oldest backup. With no backups the current file is simply truncated.
+func (r *rotatingFile) shift() error {
+	if r.maxBackups <= 0 {
+		return nil
+	}
+	_ = os.Remove(r.backup(r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(r.backup(i), r.backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, r.backup(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (r *rotatingFile) backup(n int) string {
+	return fmt.Sprintf("%s.%d", r.path, n)
+}
+
+// Close closes the underlying file.
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -4,16 +4,93 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
+	"gotui/internal/changes"
 	"gotui/internal/components/chat"
 	"gotui/internal/components/chattemplates"
 	"gotui/internal/logging"
+	"gotui/internal/stores"
 )
 
 // Handler processes inbound websocket messages and routes them to the chat UI.
 type Handler struct {
 	chat    *chat.Chat
 	logFunc func(string)
+
+	mu         sync.Mutex
+	intercepts map[string]func(Reply)
+	// routes sends the messages of a thread to a conversation other than the
+	// active one.
+	routes map[string]string
+}
+
+// Reply is an inbound message delivered to a thread interceptor instead of
+// the chat.
+type Reply struct {
+	ThreadID string
+	// Type is the resolved chat message type, e.g. "ai", "user" or "error".
+	Type    string
+	Content string
+	// Stopped marks the server's end-of-run message for the thread.
+	Stopped bool
+}
+
+// Intercept routes messages for threadID to fn instead of the chat until the
+// thread is released. fn is called from the websocket read goroutine.
+func (h *Handler) Intercept(threadID string, fn func(Reply)) {
+	if h == nil || threadID == "" || fn == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.intercepts == nil {
+		h.intercepts = make(map[string]func(Reply))
+	}
+	h.intercepts[threadID] = fn
+}
+
+// Route shows messages for threadID in conversationID instead of the active
+// conversation until the thread is released or its run stops.
+func (h *Handler) Route(threadID, conversationID string) {
+	if h == nil || threadID == "" || conversationID == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.routes == nil {
+		h.routes = make(map[string]string)
+	}
+	h.routes[threadID] = conversationID
+}
+
+// Release stops intercepting or routing messages for threadID.
+func (h *Handler) Release(threadID string) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.intercepts, threadID)
+	delete(h.routes, threadID)
+}
+
+func (h *Handler) route(threadID string) string {
+	if threadID == "" {
+		return ""
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.routes[threadID]
+}
+
+func (h *Handler) interceptor(threadID string) func(Reply) {
+	if threadID == "" {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.intercepts[threadID]
 }
 
 // New creates a handler bound to the provided chat component.
@@ -51,6 +128,28 @@ func (h *Handler) HandleRaw(data []byte) {
 
     metadata, buttons := extractMetadata(envelope)
 
+	threadID := firstNonEmpty(
+		stringValue(envelope["threadId"]),
+		getNestedString(envelope, "message", "threadId"),
+		getNestedString(envelope, "data", "threadId"),
+		getNestedString(envelope, "payload", "threadId"),
+	)
+	conversationID := h.route(threadID)
+
+	if fn := h.interceptor(threadID); fn != nil {
+		// Intercepted threads belong to no conversation; their usage is kept
+		// under the thread so it only counts toward the session.
+		h.recordUsage(envelope, threadID)
+		fn(Reply{
+			ThreadID: threadID,
+			Type:     resolveChatMessageType(senderType, templateType, messageType),
+			Content:  content,
+			Stopped:  processStopped(messageType) || processStopped(templateType) || processStopped(stringValue(envelope["actionType"])),
+		})
+		return
+	}
+	h.recordUsage(envelope, conversationID)
+
     if strings.TrimSpace(content) == "" {
         content = firstNonEmpty(
             getNestedString(envelope, "payload", "path"),
@@ -62,16 +161,44 @@ func (h *Handler) HandleRaw(data []byte) {
         content = string(data)
     }
 
-    logging.Printf("messagehandler content: %s", content)
     chatType := resolveChatMessageType(senderType, templateType, messageType)
+    logging.Debug("message handled", "type", chatType, "content", content)
 
-	if len(metadata) > 0 || len(buttons) > 0 {
+	if chatType == "write_file" {
+		h.recordChange(conversationID, metadata, content)
+	}
+	h.notify(conversationID, envelope, metadata, chatType, content)
+
+	switch {
+	case conversationID != "":
+		if processStopped(messageType) || processStopped(templateType) || processStopped(stringValue(envelope["actionType"])) {
+			h.Release(threadID)
+		}
+		// The server echoes the prompt, which the conversation already shows.
+		if chatType != "user" {
+			h.chat.AddConversationMessageWithMetadata(conversationID, chatType, content, metadata, buttons)
+		}
+	case len(metadata) > 0 || len(buttons) > 0:
 		h.chat.AddMessageWithMetadata(chatType, content, metadata, buttons)
-	} else {
+	default:
 		h.chat.AddMessage(chatType, content)
 	}
 }
 
+// recordChange adds a write_file event to the change set of conversationID,
+// or of the active conversation when it is empty.
+func (h *Handler) recordChange(conversationID string, metadata map[string]any, content string) {
+	projectPath := stores.SharedApplicationStateStore().State().ProjectPath
+	edit, ok := changes.EditFromEvent(projectPath, metadata, content)
+	if !ok {
+		return
+	}
+	if conversationID == "" {
+		conversationID = stores.SharedConversationStore().ActiveID()
+	}
+	stores.SharedChangeStore().Record(conversationID, edit)
+}
+
 func resolveChatMessageType(senderType, templateType, messageType string) string {
 	senderType = strings.ToLower(senderType)
 	templateType = strings.ToLower(templateType)
@@ -0,0 +1,82 @@
+package messagehandler
+
+import (
+	"strings"
+
+	"gotui/internal/notify"
+	"gotui/internal/stores"
+)
+
+// approvalActions names the actions the server asks approval for.
+var approvalActions = map[string]string{
+	"writefile":         "Write",
+	"readfile":          "Read",
+	"readmanyfiles":     "Read files in",
+	"folderread":        "List",
+	"smartedit":         "Edit",
+	"replaceinfile":     "Edit",
+	"searchfilecontent": "Search",
+}
+
+// notify adds a notification for the events a user switches away and waits
+// for: the agent finishing its run, asking for approval, or failing.
+func (h *Handler) notify(conversationID string, envelope, metadata map[string]any, chatType, content string) {
+	actionType := strings.ToLower(stringValue(envelope["actionType"]))
+	if conversationID == "" {
+		conversationID = stores.SharedConversationStore().ActiveID()
+	}
+	n := stores.Notification{ConversationID: conversationID}
+	switch {
+	case processStopped(stringValue(envelope["type"])) || processStopped(actionType) || processStopped(stringValue(envelope["templateType"])):
+		n.Event = notify.EventRunComplete
+		n.Severity = stores.SeveritySuccess
+		n.Title = "Agent finished"
+		n.Body = summarize(content)
+	case stringValue(metadata["stateEvent"]) == "askForConfirmation":
+		n.Event = notify.EventApproval
+		n.Severity = stores.SeverityWarning
+		n.Title = "Approval needed"
+		n.Body = summarize(content)
+		if path := stringValue(metadata["path"]); path != "" {
+			verb := approvalActions[actionType]
+			if verb == "" {
+				verb = "Access"
+			}
+			n.Body = verb + " " + path
+		}
+	case chatType == "error":
+		n.Event = notify.EventError
+		n.Severity = stores.SeverityError
+		n.Title = "Agent error"
+		n.Body = summarize(content)
+	default:
+		return
+	}
+	stores.SharedNotificationStore().Add(n)
+}
+
+// processStopped matches the server's end-of-run marker, which is spelled
+// both "processStoped" and "processStopped".
+func processStopped(value string) bool {
+	value = strings.ToLower(value)
+	return value == "processstoped" || value == "processstopped"
+}
+
+// summarize returns the first line of content, shortened for a one-line
+// notification. Raw JSON payloads carry nothing worth showing.
+func summarize(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			return ""
+		}
+		if runes := []rune(line); len(runes) > 120 {
+			line = string(runes[:119]) + "…"
+		}
+		return line
+	}
+	return ""
+}
@@ -0,0 +1,52 @@
+package messagehandler
+
+import (
+	"fmt"
+	"strings"
+
+	"gotui/internal/notify"
+	"gotui/internal/stores"
+	"gotui/internal/usage"
+)
+
+// recordUsage adds token usage reported with a message to the usage store
+// and raises a notification for each budget it crosses.
+func (h *Handler) recordUsage(envelope map[string]any, conversationID string) {
+	report, ok := usage.FromEnvelope(envelope)
+	if !ok {
+		return
+	}
+	if conversationID == "" {
+		conversationID = stores.SharedConversationStore().ActiveID()
+	}
+	model := report.Model
+	if model == "" {
+		if conv := stores.SharedConversationStore().Conversation(conversationID); conv != nil && conv.Options.SelectedModel != nil {
+			model = conv.Options.SelectedModel.Name
+		} else if selected := stores.SharedApplicationStateStore().State().SelectedModel; selected != nil {
+			model = selected.Name
+		}
+	}
+	for _, warning := range stores.SharedUsageStore().Record(conversationID, model, report.Usage) {
+		n := stores.Notification{
+			Event:          notify.EventBudget,
+			Severity:       stores.SeverityWarning,
+			Title:          fmt.Sprintf("%s budget %d%% used", budgetScope(warning.Scope), int(warning.Threshold*100)),
+			Body:           fmt.Sprintf("%s of %s", usage.Describe(warning.Usage), warning.Budget),
+			ConversationID: conversationID,
+		}
+		if warning.Threshold >= 1 {
+			n.Severity = stores.SeverityError
+			n.Title = budgetScope(warning.Scope) + " budget exceeded"
+		}
+		stores.SharedNotificationStore().Add(n)
+		h.logFunc(fmt.Sprintf("⚠️  %s: %s", n.Title, n.Body))
+	}
+}
+
+func budgetScope(scope string) string {
+	if scope == "" {
+		return ""
+	}
+	return strings.ToUpper(scope[:1]) + scope[1:]
+}
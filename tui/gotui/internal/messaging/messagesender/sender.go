@@ -3,18 +3,22 @@ package messagesender
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 
+	"gotui/internal/agentcontext"
 	"gotui/internal/stores"
 	"gotui/internal/wsclient"
 )
 
 // Sender is responsible for encoding outbound user messages and delivering
-// them to the websocket client.
+// them to the websocket client. It is safe for concurrent use.
 type Sender struct {
+	mu     sync.Mutex
 	client *wsclient.Client
 	agent  stores.AgentSelection
 }
@@ -27,19 +31,69 @@ func New(client *wsclient.Client, agent stores.AgentSelection) *Sender {
 
 // SetAgent updates the default agent selection used for outbound messages.
 func (s *Sender) SetAgent(agent stores.AgentSelection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.agent = agent
 }
 
+// SetClient rebinds the sender to a new websocket client, e.g. after switching
+// server profiles.
+func (s *Sender) SetClient(client *wsclient.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// Bound returns a sender fixed to s's current client and agent. Commands take
+// one before they leave the update loop, so a message goes out on the
+// connection it was written for even if the profile is switched meanwhile.
+func (s *Sender) Bound() *Sender {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &Sender{client: s.client, agent: s.agent}
+}
+
+// Request describes an outbound user message. Zero fields fall back to the
+// sender's defaults.
+type Request struct {
+	Content string
+	// ThreadID lets the caller match replies to this message; a new one is
+	// generated when empty.
+	ThreadID string
+	Agent    *stores.AgentSelection
+	Model    *stores.ModelOption
+	// Context lists the mentioned and attached files to send along.
+	Context agentcontext.Request
+}
+
 // Send transmits the provided content to the server encoded as a user message.
 func (s *Sender) Send(content string) error {
-	if s == nil || s.client == nil {
-		return errors.New("websocket client not configured")
+	_, err := s.SendRequest(Request{Content: content})
+	return err
+}
+
+// SendRequest transmits req and returns the thread ID replies will carry.
+func (s *Sender) SendRequest(req Request) (string, error) {
+	if s == nil {
+		return "", errors.New("websocket client not configured")
 	}
+	s.mu.Lock()
+	client, agent := s.client, s.agent
+	s.mu.Unlock()
+	if client == nil {
+		return "", errors.New("websocket client not configured")
+	}
+	content := req.Content
 	if strings.TrimSpace(content) == "" {
-		return errors.New("message content cannot be empty")
+		return "", errors.New("message content cannot be empty")
 	}
 
-	agent := s.agent
+	if req.Agent != nil && req.Agent.ID != "" {
+		agent = *req.Agent
+	}
 	if agent.ID == "" {
 		agent.ID = uuid.NewString()
 	}
@@ -48,7 +102,10 @@ func (s *Sender) Send(content string) error {
 	}
 
 	messageID := uuid.NewString()
-	threadID := uuid.NewString()
+	threadID := req.ThreadID
+	if threadID == "" {
+		threadID = uuid.NewString()
+	}
 	selectedAgent := map[string]any{
 		"id":   agent.ID,
 		"name": agent.Name,
@@ -60,21 +117,59 @@ func (s *Sender) Send(content string) error {
 		selectedAgent["agentDetails"] = agent.AgentDetails
 	}
 
+	content, err := agentcontext.Inline(content, req.Context.Attachments)
+	if err != nil {
+		return "", fmt.Errorf("attach file: %w", err)
+	}
+	images := make([]string, 0, len(req.Context.Images))
+	total := 0
+	for _, path := range req.Context.Images {
+		image, err := agentcontext.DataURL(path)
+		if err != nil {
+			return "", fmt.Errorf("attach image: %w", err)
+		}
+		// Encoded images are a third larger than the files they came from.
+		total += len(image) * 3 / 4
+		if total > agentcontext.MaxImagesTotal {
+			return "", fmt.Errorf("attach image: images in a message are limited to %s in total", agentcontext.FormatSize(agentcontext.MaxImagesTotal))
+		}
+		images = append(images, image)
+	}
+
+	// mentionedFiles are relative to the project, mentionedFullPaths absolute.
+	projectPath := stores.SharedApplicationStateStore().State().ProjectPath
+	files := make([]string, 0, len(req.Context.Files))
+	for _, path := range req.Context.Files {
+		if rel, err := filepath.Rel(projectPath, path); projectPath != "" && err == nil && !strings.HasPrefix(rel, "..") {
+			path = filepath.ToSlash(rel)
+		}
+		files = append(files, path)
+	}
+	fullPaths := append([]string{}, req.Context.Files...)
+
+	message := map[string]any{
+		"userMessage":        content,
+		"selectedAgent":      selectedAgent,
+		"mentionedFiles":     files,
+		"mentionedFullPaths": fullPaths,
+		"mentionedFolders":   []string{},
+		"mentionedMCPs":      []string{},
+		"uploadedImages":     images,
+		"mentionedAgents":    []any{},
+		"mentionedDocs":      []any{},
+		"links":              []any{},
+		"messageId":          messageID,
+		"threadId":           threadID,
+	}
+	if req.Model != nil && req.Model.Name != "" {
+		message["selectedModel"] = map[string]any{
+			"name":     req.Model.Name,
+			"provider": req.Model.Provider,
+		}
+	}
+
 	payload := map[string]any{
-		"message": map[string]any{
-			"userMessage":        content,
-			"selectedAgent":      selectedAgent,
-			"mentionedFiles":     []string{},
-			"mentionedFullPaths": []string{},
-			"mentionedFolders":   []string{},
-			"mentionedMCPs":      []string{},
-			"uploadedImages":     []string{},
-			"mentionedAgents":    []any{},
-			"mentionedDocs":      []any{},
-			"links":              []any{},
-			"messageId":          messageID,
-			"threadId":           threadID,
-		},
+		"message": message,
 		"sender": map[string]any{
 			"senderType": "user",
 			"senderInfo": map[string]any{"name": "user"},
@@ -88,5 +183,5 @@ func (s *Sender) Send(content string) error {
 		"type":      "messageResponse",
 	}
 
-	return s.client.Send("messageResponse", payload)
+	return threadID, client.Send("messageResponse", payload)
 }
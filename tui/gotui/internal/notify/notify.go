@@ -0,0 +1,109 @@
+// Package notify defines notification event types and the escape sequences
+// that raise terminal and desktop alerts.
+package notify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Event types that can raise an alert.
+const (
+	// EventRunComplete fires when the agent finishes a run.
+	EventRunComplete = "run_complete"
+	// EventApproval fires when the agent waits for the user to approve an action.
+	EventApproval = "approval"
+	// EventError fires when the agent reports an error.
+	EventError = "error"
+	// EventServer fires for notifications pushed by the server.
+	EventServer = "server"
+	// EventBudget fires when token or cost usage crosses a soft budget.
+	EventBudget = "budget"
+)
+
+// Events lists every event type in display order.
+var Events = []string{EventRunComplete, EventApproval, EventError, EventServer, EventBudget}
+
+// DefaultEnabled reports whether event alerts when the config does not say.
+func DefaultEnabled(event string) bool {
+	return event == EventRunComplete || event == EventApproval || event == EventBudget
+}
+
+// Alert methods.
+const (
+	// MethodAuto picks a desktop notification the terminal understands and
+	// falls back to the bell.
+	MethodAuto = "auto"
+	// MethodBell rings the terminal bell.
+	MethodBell = "bell"
+	// MethodOSC9 sends an iTerm2-style desktop notification.
+	MethodOSC9 = "osc9"
+	// MethodOSC777 sends an rxvt/VTE-style desktop notification.
+	MethodOSC777 = "osc777"
+	// MethodOff disables alerts.
+	MethodOff = "off"
+)
+
+// Methods lists every alert method.
+var Methods = []string{MethodAuto, MethodBell, MethodOSC9, MethodOSC777, MethodOff}
+
+// DetectMethod resolves MethodAuto from the terminal's environment.
+func DetectMethod(getenv func(string) string) string {
+	program := strings.ToLower(getenv("TERM_PROGRAM"))
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case program == "iterm.app", program == "wezterm", program == "ghostty",
+		strings.Contains(term, "ghostty"), getenv("WEZTERM_EXECUTABLE") != "":
+		return MethodOSC9
+	case strings.Contains(term, "rxvt"), strings.Contains(term, "foot"), getenv("VTE_VERSION") != "":
+		return MethodOSC777
+	}
+	return MethodBell
+}
+
+// Sequence returns the escape sequence that alerts with title and body using
+// method, which must not be MethodAuto. Inside tmux desktop notifications are
+// wrapped for passthrough and followed by a bell so tmux can flag the window.
+func Sequence(method, title, body string, getenv func(string) string) string {
+	title, body = clean(title), clean(body)
+	var seq string
+	switch method {
+	case MethodBell:
+		return "\a"
+	case MethodOSC9:
+		text := title
+		if body != "" {
+			text += ": " + body
+		}
+		seq = fmt.Sprintf("\x1b]9;%s\a", text)
+	case MethodOSC777:
+		seq = fmt.Sprintf("\x1b]777;notify;%s;%s\a", title, body)
+	default:
+		return ""
+	}
+	if getenv("TMUX") != "" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\" + "\a"
+	}
+	return seq
+}
+
+// clean strips control characters that would end the sequence early,
+// replaces the OSC 777 field separator and keeps the text short enough for a
+// desktop popup.
+func clean(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n', r == '\t':
+			return ' '
+		case r == ';':
+			return ','
+		case r < 0x20, r == 0x7f:
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+	if runes := []rune(s); len(runes) > 200 {
+		s = string(runes[:199]) + "…"
+	}
+	return s
+}
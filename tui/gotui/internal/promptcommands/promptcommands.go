@@ -0,0 +1,391 @@
+// Package promptcommands loads user-defined slash commands: Markdown prompt
+// templates with TOML front matter that are expanded with arguments, the
+// current selection, the git diff and file contents before being sent.
+package promptcommands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gotui/internal/agentcontext"
+	"gotui/internal/configfile"
+)
+
+// Argument is a named parameter of a command. In front matter it may be
+// written as a bare name or as a table.
+type Argument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// UnmarshalJSON accepts a plain string as a required argument of that name.
+func (a *Argument) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*a = Argument{Name: name, Required: true}
+		return nil
+	}
+	type plain Argument
+	return json.Unmarshal(data, (*plain)(a))
+}
+
+// Command is a prompt template invoked as /Name.
+type Command struct {
+	Name        string
+	Description string
+	// Agent and Model, when set, route the prompt to that agent or model
+	// instead of the current selection. They match by ID or name.
+	Agent     string
+	Model     string
+	Arguments []Argument
+	Template  string
+	// Path is the file the command was loaded from.
+	Path string
+	// Project marks commands from the project directory, which comes with
+	// the checkout and is not trusted: their {{file:...}} paths stay inside
+	// Variables.Root unless AllowOutside is set.
+	Project bool
+}
+
+type frontMatter struct {
+	Description string     `json:"description"`
+	Agent       string     `json:"agent"`
+	Model       string     `json:"model"`
+	Arguments   []Argument `json:"arguments"`
+}
+
+// LoadFor reads every *.md file in the user's commands directory and then the
+// project's, sorted by name, so project commands win on name clashes. Those
+// from the project are marked as Project. Invalid files are skipped and
+// reported in the returned errors.
+func LoadFor(projectPath string) ([]Command, []error) {
+	var (
+		commands []Command
+		errs     []error
+	)
+	if dir := configfile.UserDir(); dir != "" {
+		commands, errs = loadDir(filepath.Join(dir, "commands"), false, commands, errs)
+	}
+	if dir := configfile.ProjectDir(projectPath); dir != "" {
+		commands, errs = loadDir(filepath.Join(dir, "commands"), true, commands, errs)
+	}
+	return commands, errs
+}
+
+func loadDir(dir string, project bool, commands []Command, errs []error) ([]Command, []error) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.md"))
+	sort.Strings(paths)
+	for _, path := range paths {
+		command, err := LoadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		command.Project = project
+		replaced := false
+		for i := range commands {
+			if strings.EqualFold(commands[i].Name, command.Name) {
+				commands[i] = command
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			commands = append(commands, command)
+		}
+	}
+	return commands, errs
+}
+
+// LoadFile parses a single command file. The command is named after the
+// file, e.g. test.md defines /test.
+func LoadFile(path string) (Command, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Command{}, err
+	}
+	command, err := Parse(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), data)
+	if err != nil {
+		return Command{}, fmt.Errorf("%s: %w", path, err)
+	}
+	command.Path = path
+	return command, nil
+}
+
+// Parse reads a command from data: optional TOML front matter between "+++"
+// lines, followed by the template.
+func Parse(name string, data []byte) (Command, error) {
+	name = strings.TrimSpace(name)
+	if !validName(name) {
+		return Command{}, fmt.Errorf("invalid command name %q: use letters, digits, '-' and '_'", name)
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var meta frontMatter
+	if rest, ok := strings.CutPrefix(text, "+++\n"); ok {
+		header, body, found := strings.Cut(rest, "\n+++")
+		if !found {
+			return Command{}, errors.New("front matter is not closed with +++")
+		}
+		if err := configfile.DecodeBytes([]byte(header), "toml", &meta); err != nil {
+			return Command{}, fmt.Errorf("front matter: %w", err)
+		}
+		text = strings.TrimPrefix(body, "\n")
+		if line, after, ok := strings.Cut(text, "\n"); ok && strings.TrimSpace(line) == "" {
+			text = after
+		}
+	}
+	template := strings.TrimSpace(text)
+	if template == "" {
+		return Command{}, errors.New("empty template")
+	}
+
+	seen := make(map[string]bool)
+	for i, arg := range meta.Arguments {
+		arg.Name = strings.TrimSpace(arg.Name)
+		if !validName(arg.Name) {
+			return Command{}, fmt.Errorf("invalid argument name %q", arg.Name)
+		}
+		if builtinVariables[arg.Name] {
+			return Command{}, fmt.Errorf("argument %q shadows a built-in variable", arg.Name)
+		}
+		if seen[arg.Name] {
+			return Command{}, fmt.Errorf("duplicate argument %q", arg.Name)
+		}
+		seen[arg.Name] = true
+		meta.Arguments[i] = arg
+	}
+
+	return Command{
+		Name:        name,
+		Description: strings.TrimSpace(meta.Description),
+		Agent:       strings.TrimSpace(meta.Agent),
+		Model:       strings.TrimSpace(meta.Model),
+		Arguments:   meta.Arguments,
+		Template:    template,
+	}, nil
+}
+
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// Usage renders how the command is invoked, e.g. "/test <file> [focus]".
+func (c Command) Usage() string {
+	var b strings.Builder
+	b.WriteString("/" + c.Name)
+	for _, arg := range c.Arguments {
+		if arg.Required {
+			b.WriteString(" <" + arg.Name + ">")
+		} else {
+			b.WriteString(" [" + arg.Name + "]")
+		}
+	}
+	return b.String()
+}
+
+// ParseArgs assigns the words of input to the command's arguments in order.
+// Words may be quoted with ' or "; the last argument takes the rest of the
+// line. Arguments left empty take their default, and required ones still
+// empty are returned as missing.
+func (c Command) ParseArgs(input string) (map[string]string, []Argument) {
+	values := make(map[string]string, len(c.Arguments))
+	rest := strings.TrimSpace(input)
+	for i, arg := range c.Arguments {
+		var value string
+		if i == len(c.Arguments)-1 {
+			value = unquote(rest)
+			rest = ""
+		} else {
+			value, rest = nextWord(rest)
+		}
+		values[arg.Name] = value
+	}
+	values["args"] = strings.TrimSpace(input)
+	return values, c.Missing(values)
+}
+
+// Missing fills defaults into values and returns the required arguments
+// that are still empty.
+func (c Command) Missing(values map[string]string) []Argument {
+	var missing []Argument
+	for _, arg := range c.Arguments {
+		if strings.TrimSpace(values[arg.Name]) == "" {
+			values[arg.Name] = arg.Default
+		}
+		if arg.Required && strings.TrimSpace(values[arg.Name]) == "" {
+			missing = append(missing, arg)
+		}
+	}
+	return missing
+}
+
+// nextWord splits the first, possibly quoted, word from s.
+func nextWord(s string) (string, string) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	if s == "" {
+		return "", ""
+	}
+	if quote := s[0]; quote == '"' || quote == '\'' {
+		if end := strings.IndexByte(s[1:], quote); end >= 0 {
+			return s[1 : end+1], strings.TrimSpace(s[end+2:])
+		}
+	}
+	if end := strings.IndexFunc(s, unicode.IsSpace); end >= 0 {
+		return s[:end], strings.TrimSpace(s[end:])
+	}
+	return s, ""
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// builtinVariables are always available to templates.
+var builtinVariables = map[string]bool{"args": true, "selection": true, "diff": true}
+
+// Variables supplies the values templates can refer to besides arguments.
+// Selection and Diff are only called when the template uses them.
+type Variables struct {
+	// Root resolves relative paths in {{file:...}}.
+	Root string
+	// AllowOutside lets a project command read files outside Root, once the
+	// user agreed to it.
+	AllowOutside bool
+	Selection    func() (string, error)
+	Diff         func() (string, error)
+}
+
+// OutsideRootError is returned when a project command reads a file outside
+// the project root without AllowOutside.
+type OutsideRootError struct {
+	Path string
+}
+
+func (e *OutsideRootError) Error() string {
+	return fmt.Sprintf("%s is outside the project", e.Path)
+}
+
+// Expand substitutes the template's variables:
+//
+//	{{name}}        an argument
+//	{{args}}        everything typed after the command
+//	{{selection}}   the current text selection
+//	{{diff}}        the working tree's git diff
+//	{{file:path}}   a file's contents; path may also name an argument
+//
+// Unknown variables are an error so typos do not reach the model.
+func (c Command) Expand(values map[string]string, vars Variables) (string, error) {
+	var (
+		b    strings.Builder
+		rest = c.Template
+	)
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			b.WriteString(rest)
+			break
+		}
+		b.WriteString(rest[:start])
+		name := strings.TrimSpace(rest[start+2 : start+end])
+		rest = rest[start+end+2:]
+
+		value, err := c.variable(name, values, vars)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+	}
+	return b.String(), nil
+}
+
+func (c Command) variable(name string, values map[string]string, vars Variables) (string, error) {
+	if path, ok := strings.CutPrefix(name, "file:"); ok {
+		path = strings.TrimSpace(path)
+		if value, ok := values[path]; ok {
+			path = value
+		}
+		return readFile(path, vars.Root, c.Project && !vars.AllowOutside)
+	}
+	switch name {
+	case "selection":
+		if vars.Selection == nil {
+			return "", errors.New("no selection is available")
+		}
+		return vars.Selection()
+	case "diff":
+		if vars.Diff == nil {
+			return "", errors.New("no git diff is available")
+		}
+		return vars.Diff()
+	}
+	if value, ok := values[name]; ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("/%s: unknown variable {{%s}}", c.Name, name)
+}
+
+// readFile returns the text file at path as a fenced block headed by the
+// path, within the attachment size limit. When confined, a path that leaves
+// root, directly or through a symlink, is an *OutsideRootError.
+func readFile(path, root string, confined bool) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", errors.New("{{file:...}} needs a path")
+	}
+	full := path
+	if strings.HasPrefix(full, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			full = filepath.Join(home, full[2:])
+		}
+	}
+	if !filepath.IsAbs(full) {
+		full = filepath.Join(root, full)
+	}
+	full = filepath.Clean(full)
+	if confined && !agentcontext.Within(full, root) {
+		return "", &OutsideRootError{Path: path}
+	}
+	attachment, err := agentcontext.NewAttachment(full)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("%s does not exist", path)
+		}
+		return "", err
+	}
+	if attachment.Image {
+		return "", fmt.Errorf("%s is an image; attach it with /attach instead", path)
+	}
+	data, err := os.ReadFile(attachment.Path)
+	if err != nil {
+		return "", err
+	}
+	fence := "```"
+	for bytes.Contains(data, []byte(fence)) {
+		fence += "`"
+	}
+	return fmt.Sprintf("%s:\n%s\n%s\n%s", path, fence, strings.TrimRight(string(data), "\n"), fence), nil
+}
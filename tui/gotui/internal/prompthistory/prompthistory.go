@@ -0,0 +1,188 @@
+// Package prompthistory persists the prompts sent from a project so they can
+// be recalled and searched in later sessions.
+package prompthistory
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gotui/internal/configfile"
+)
+
+// MaxEntries bounds the history kept per project; older prompts are dropped
+// when the file is compacted.
+const MaxEntries = 1000
+
+// History is the prompt history of one project, oldest first, along with
+// the prompts left unsent when the last session ended.
+type History struct {
+	mu      sync.Mutex
+	path    string
+	entries []string
+	drafts  Drafts
+}
+
+// Drafts are the prompts typed but not sent when a session ended.
+type Drafts struct {
+	// Active is the draft of the conversation that was active.
+	Active string `json:"active,omitempty"`
+	// Other holds the drafts of the other conversations.
+	Other []string `json:"other,omitempty"`
+}
+
+// PathFor returns the history file of projectPath inside the user config
+// directory. Keeping it out of the checkout avoids committing prompts.
+func PathFor(projectPath string) string {
+	return configfile.UserProjectFile("history", projectPath, ".jsonl")
+}
+
+// Open loads the history stored at path. A missing file is an empty
+// history; an empty path keeps the history in memory only.
+func Open(path string) (*History, error) {
+	h := &History{path: path}
+	if path == "" {
+		return h, nil
+	}
+	if data, err := os.ReadFile(draftsPath(path)); err == nil {
+		json.Unmarshal(data, &h.drafts)
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var prompt string
+		if json.Unmarshal(scanner.Bytes(), &prompt) != nil || strings.TrimSpace(prompt) == "" {
+			continue
+		}
+		h.entries = append(h.entries, prompt)
+	}
+	if err := scanner.Err(); err != nil {
+		return h, err
+	}
+	if len(h.entries) > MaxEntries {
+		h.entries = append([]string(nil), h.entries[len(h.entries)-MaxEntries:]...)
+		return h, h.rewrite()
+	}
+	return h, nil
+}
+
+// Entries returns a copy of the history, oldest first.
+func (h *History) Entries() []string {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.entries...)
+}
+
+// Drafts returns the prompts left unsent when the last session ended.
+func (h *History) Drafts() Drafts {
+	if h == nil {
+		return Drafts{}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return Drafts{Active: h.drafts.Active, Other: append([]string(nil), h.drafts.Other...)}
+}
+
+// SaveDrafts stores the unsent prompts for the next session next to the
+// history file. Empty drafts are dropped; with none left the file is removed.
+func (h *History) SaveDrafts(drafts Drafts) error {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.drafts = Drafts{}
+	if strings.TrimSpace(drafts.Active) != "" {
+		h.drafts.Active = drafts.Active
+	}
+	for _, draft := range drafts.Other {
+		if strings.TrimSpace(draft) != "" {
+			h.drafts.Other = append(h.drafts.Other, draft)
+		}
+	}
+	if h.path == "" {
+		return nil
+	}
+	path := draftsPath(h.path)
+	if h.drafts.Active == "" && len(h.drafts.Other) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(h.drafts)
+	if err != nil {
+		return err
+	}
+	return configfile.WriteFile(path, data)
+}
+
+// draftsPath returns the drafts file kept next to the history at path.
+func draftsPath(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".drafts.json"
+}
+
+// Add records prompt and appends it to the history file. Repeating the
+// previous prompt is not recorded twice.
+func (h *History) Add(prompt string) error {
+	if h == nil || strings.TrimSpace(prompt) == "" {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if n := len(h.entries); n > 0 && h.entries[n-1] == prompt {
+		return nil
+	}
+	h.entries = append(h.entries, prompt)
+	if h.path == "" {
+		return nil
+	}
+	if len(h.entries) > MaxEntries*2 {
+		h.entries = append([]string(nil), h.entries[len(h.entries)-MaxEntries:]...)
+		return h.rewrite()
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(prompt)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// rewrite replaces the file with the in-memory entries. Callers hold mu.
+func (h *History) rewrite() error {
+	var b bytes.Buffer
+	for _, prompt := range h.entries {
+		line, _ := json.Marshal(prompt)
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return configfile.WriteFile(h.path, b.Bytes())
+}
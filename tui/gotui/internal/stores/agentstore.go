@@ -3,19 +3,110 @@ package stores
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
+
+	"gotui/internal/transport"
 )
 
 // AgentOption represents a single agent entry exposed by the agent server.
+// The server passes marketplace metadata through, so everything past the
+// description may be missing.
 type AgentOption struct {
-	ID          string `json:"agentId"`
-	Name        string `json:"title"`
-	Description string `json:"agentDescription"`
+	ID              string `json:"agentId"`
+	Name            string `json:"title"`
+	Description     string `json:"agentDescription"`
+	LongDescription string `json:"longDescription,omitempty"`
+	Version         string `json:"version,omitempty"`
+	Type            string `json:"agentType,omitempty"`
+	Author          string `json:"author,omitempty"`
+	Tags            Names  `json:"tags,omitempty"`
+	// Tools lists the tools the agent needs.
+	Tools Names `json:"tools,omitempty"`
+	// Installed is false for agents the server offers but has not activated;
+	// nil means the server does not say, and the agent is usable.
+	Installed *bool `json:"installed,omitempty"`
+}
+
+// Available reports whether the agent can be selected without installing it.
+func (a AgentOption) Available() bool {
+	return a.Installed == nil || *a.Installed
+}
+
+// clone returns a copy of a that shares no slices or pointers with it.
+func (a AgentOption) clone() AgentOption {
+	a.Tags = slices.Clone(a.Tags)
+	a.Tools = slices.Clone(a.Tools)
+	if a.Installed != nil {
+		installed := *a.Installed
+		a.Installed = &installed
+	}
+	return a
+}
+
+// UnmarshalJSON also reads required tools from "requiredTools".
+func (a *AgentOption) UnmarshalJSON(data []byte) error {
+	type plain AgentOption
+	var decoded struct {
+		plain
+		RequiredTools Names `json:"requiredTools"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*a = AgentOption(decoded.plain)
+	for _, tool := range decoded.RequiredTools {
+		if !slices.Contains(a.Tools, tool) {
+			a.Tools = append(a.Tools, tool)
+		}
+	}
+	return nil
+}
+
+// Names is a list of names that servers may send as strings or as objects
+// with a "name" or "title"; other entries are skipped.
+type Names []string
+
+// UnmarshalJSON accepts strings and named objects.
+func (n *Names) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		// A single value, or something else entirely, is not a list.
+		*n = nil
+		return nil
+	}
+	names := make(Names, 0, len(raw))
+	for _, item := range raw {
+		var name string
+		if err := json.Unmarshal(item, &name); err == nil {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+			continue
+		}
+		var named struct {
+			Name  string `json:"name"`
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(item, &named); err == nil {
+			if named.Name == "" {
+				named.Name = named.Title
+			}
+			if name = strings.TrimSpace(named.Name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	*n = names
+	return nil
 }
 
 type agentListener func([]AgentOption)
@@ -28,6 +119,7 @@ type AgentStore struct {
 	agents     []AgentOption
 	listeners  map[int64]agentListener
 	nextListen int64
+	validators validators
 }
 
 var (
@@ -77,7 +169,7 @@ func (s *AgentStore) AgentByID(id string) (*AgentOption, bool) {
 	defer s.mu.RUnlock()
 	for _, agent := range s.agents {
 		if strings.EqualFold(agent.ID, id) {
-			agentCopy := agent
+			agentCopy := agent.clone()
 			return &agentCopy, true
 		}
 	}
@@ -123,43 +215,64 @@ func (s *AgentStore) SetAgents(agents []AgentOption) {
 	}
 }
 
+// SetHTTPClient replaces the client used by Fetch, e.g. to add credentials or
+// TLS settings for a different server.
+func (s *AgentStore) SetHTTPClient(client *http.Client) {
+	if s == nil || client == nil {
+		return
+	}
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+}
+
 // Fetch retrieves the agent list from the remote server and updates the cache.
 func (s *AgentStore) Fetch(ctx context.Context, protocol, host string, port int) ([]AgentOption, error) {
-	if s == nil {
-		return nil, fmt.Errorf("AgentStore is not initialized")
-	}
+	agents, _, err := s.fetch(ctx, protocol, host, port)
+	return agents, err
+}
 
-	scheme := "http"
-	protocol = strings.ToLower(strings.TrimSpace(protocol))
-	if protocol == "https" || protocol == "wss" {
-		scheme = "https"
-	}
+// Refresh fetches the agent list again and reports whether it changed. The
+// request is conditional, so an unchanged list costs no body.
+func (s *AgentStore) Refresh(ctx context.Context, protocol, host string, port int) (bool, error) {
+	_, changed, err := s.fetch(ctx, protocol, host, port)
+	return changed, err
+}
 
-	host = strings.TrimSpace(host)
-	if host == "" {
-		host = "localhost"
+func (s *AgentStore) fetch(ctx context.Context, protocol, host string, port int) ([]AgentOption, bool, error) {
+	if s == nil {
+		return nil, false, fmt.Errorf("AgentStore is not initialized")
 	}
 
-	url := fmt.Sprintf("%s://%s:%d/agents", scheme, host, port)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, agentsURL(protocol, host, port, ""), nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	resp, err := s.client.Do(req)
+	s.mu.RLock()
+	client := s.client
+	s.validators.apply(req)
+	s.mu.RUnlock()
+
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return s.Agents(), false, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
 		snippet := strings.TrimSpace(string(body))
+		if transport.IsAuthStatus(resp.StatusCode) {
+			return nil, false, &transport.AuthError{StatusCode: resp.StatusCode, Detail: snippet}
+		}
 		if snippet != "" {
-			return nil, fmt.Errorf("agents request failed: %d %s", resp.StatusCode, snippet)
+			return nil, false, fmt.Errorf("agents request failed: %d %s", resp.StatusCode, snippet)
 		}
-		return nil, fmt.Errorf("agents request failed with status %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("agents request failed with status %d", resp.StatusCode)
 	}
 
 	var payload struct {
@@ -167,11 +280,81 @@ func (s *AgentStore) Fetch(ctx context.Context, protocol, host string, port int)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
+	s.mu.Lock()
+	s.validators.update(req.URL.String(), resp.Header)
+	changed := !reflect.DeepEqual(cloneAgents(s.agents), cloneAgents(payload.Agents))
+	s.mu.Unlock()
+
 	s.SetAgents(payload.Agents)
-	return cloneAgents(payload.Agents), nil
+	return cloneAgents(payload.Agents), changed, nil
+}
+
+// ErrInstallUnsupported is returned by Install when the server has no
+// install endpoint, as older agent servers do not.
+var ErrInstallUnsupported = errors.New("the server does not support installing agents")
+
+// Install asks the server to install and enable the agent with the given
+// ID, then refetches the agent list. It fails if the agent is still not
+// listed as installed.
+func (s *AgentStore) Install(ctx context.Context, protocol, host string, port int, id string) error {
+	if s == nil {
+		return fmt.Errorf("AgentStore is not initialized")
+	}
+	endpoint := agentsURL(protocol, host, port, "/"+url.PathEscape(id)+"/install")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	client := s.client
+	s.mu.RUnlock()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if _, err := s.Fetch(ctx, protocol, host, port); err != nil {
+			return fmt.Errorf("reloading agents after the install: %w", err)
+		}
+		if agent, ok := s.AgentByID(id); !ok || !agent.Available() {
+			return fmt.Errorf("the server accepted the install but does not list the agent as installed")
+		}
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+	snippet := strings.TrimSpace(string(body))
+	switch {
+	case transport.IsAuthStatus(resp.StatusCode):
+		return &transport.AuthError{StatusCode: resp.StatusCode, Detail: snippet}
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed:
+		return ErrInstallUnsupported
+	case snippet != "":
+		return fmt.Errorf("install request failed: %d %s", resp.StatusCode, snippet)
+	}
+	return fmt.Errorf("install request failed with status %d", resp.StatusCode)
+}
+
+// agentsURL returns the address of the server's agents endpoint plus path.
+func agentsURL(protocol, host string, port int, path string) string {
+	scheme := "http"
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if protocol == "https" || protocol == "wss" {
+		scheme = "https"
+	}
+
+	host = strings.TrimSpace(host)
+	if host == "" {
+		host = "localhost"
+	}
+
+	return fmt.Sprintf("%s://%s:%d/agents%s", scheme, host, port, path)
 }
 
 func cloneAgents(agents []AgentOption) []AgentOption {
@@ -179,6 +362,8 @@ func cloneAgents(agents []AgentOption) []AgentOption {
 		return []AgentOption{}
 	}
 	copies := make([]AgentOption, len(agents))
-	copy(copies, agents)
+	for i, agent := range agents {
+		copies[i] = agent.clone()
+	}
 	return copies
 }
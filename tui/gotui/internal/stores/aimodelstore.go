@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strings"
 	"sync"
 	"sync/atomic"
+
+	"gotui/internal/transport"
 )
 
 // ModelOption represents a single AI model entry exposed by the agent server.
@@ -30,6 +33,7 @@ type AIModelStore struct {
 	models     []ModelOption
 	listeners  map[int64]modelListener
 	nextListen int64
+	validators validators
 }
 
 var (
@@ -123,10 +127,33 @@ func (s *AIModelStore) SetModels(models []ModelOption) {
 	}
 }
 
+// SetHTTPClient replaces the client used by Fetch, e.g. to add credentials or
+// TLS settings for a different server.
+func (s *AIModelStore) SetHTTPClient(client *http.Client) {
+	if s == nil || client == nil {
+		return
+	}
+	s.mu.Lock()
+	s.client = client
+	s.mu.Unlock()
+}
+
 // Fetch retrieves model options from the configured server and updates the cache.
 func (s *AIModelStore) Fetch(ctx context.Context, protocol, host string, port int) ([]ModelOption, error) {
+	models, _, err := s.fetch(ctx, protocol, host, port)
+	return models, err
+}
+
+// Refresh fetches the model list again and reports whether it changed. The
+// request is conditional, so an unchanged list costs no body.
+func (s *AIModelStore) Refresh(ctx context.Context, protocol, host string, port int) (bool, error) {
+	_, changed, err := s.fetch(ctx, protocol, host, port)
+	return changed, err
+}
+
+func (s *AIModelStore) fetch(ctx context.Context, protocol, host string, port int) ([]ModelOption, bool, error) {
 	if s == nil {
-		return nil, fmt.Errorf("AIModelStore is not initialized")
+		return nil, false, fmt.Errorf("AIModelStore is not initialized")
 	}
 
 	scheme := "http"
@@ -144,22 +171,33 @@ func (s *AIModelStore) Fetch(ctx context.Context, protocol, host string, port in
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	resp, err := s.client.Do(req)
+	s.mu.RLock()
+	client := s.client
+	s.validators.apply(req)
+	s.mu.RUnlock()
+
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return s.Models(), false, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
 		snippet := strings.TrimSpace(string(body))
+		if transport.IsAuthStatus(resp.StatusCode) {
+			return nil, false, &transport.AuthError{StatusCode: resp.StatusCode, Detail: snippet}
+		}
 		if snippet != "" {
-			return nil, fmt.Errorf("models request failed: %d %s", resp.StatusCode, snippet)
+			return nil, false, fmt.Errorf("models request failed: %d %s", resp.StatusCode, snippet)
 		}
-		return nil, fmt.Errorf("models request failed with status %d", resp.StatusCode)
+		return nil, false, fmt.Errorf("models request failed with status %d", resp.StatusCode)
 	}
 
 	var payload struct {
@@ -167,11 +205,16 @@ func (s *AIModelStore) Fetch(ctx context.Context, protocol, host string, port in
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
+	s.mu.Lock()
+	s.validators.update(req.URL.String(), resp.Header)
+	changed := !reflect.DeepEqual(cloneModels(s.models), cloneModels(payload.Models))
+	s.mu.Unlock()
+
 	s.SetModels(payload.Models)
-	return cloneModels(payload.Models), nil
+	return cloneModels(payload.Models), changed, nil
 }
 
 func cloneModels(models []ModelOption) []ModelOption {
@@ -13,6 +13,7 @@ import (
 
 	"gotui/internal/components/chattemplates"
 	"gotui/internal/logging"
+	"gotui/internal/transport"
 )
 
 // ConversationOptions captures configurable per-conversation settings such as the selected model or agent.
@@ -43,6 +44,8 @@ type Conversation struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Options   ConversationOptions
+	// Draft is the unsent input kept while another conversation is active.
+	Draft string
 }
 
 // Clone returns a defensive copy of the conversation and all of its fields.
@@ -119,6 +122,17 @@ func (s *ConversationStore) ConfigureRemoteSync(protocol, host string, port int,
 	s.mu.Unlock()
 }
 
+// SetHTTPClient replaces the client used for remote sync, e.g. to add
+// credentials or TLS settings for a different server.
+func (s *ConversationStore) SetHTTPClient(client *http.Client) {
+	if s == nil || client == nil {
+		return
+	}
+	s.mu.Lock()
+	s.httpClient = client
+	s.mu.Unlock()
+}
+
 // SyncConversation pushes the specified conversation to the remote server.
 func (s *ConversationStore) SyncConversation(id string) {
 	if s == nil || strings.TrimSpace(id) == "" {
@@ -140,7 +154,7 @@ func (s *ConversationStore) SyncConversation(id string) {
 	go func(copy *Conversation) {
 		defer s.setSyncing(copy.ID, false)
 		if err := s.postConversation(cfg, client, copy); err != nil {
-			logging.Printf("conversation sync failed: %v", err)
+			logging.Warn("conversation sync failed", "conversation", copy.ID, "err", err)
 		}
 	}(conv)
 }
@@ -297,6 +311,52 @@ func (s *ConversationStore) AppendMessage(id string, message chattemplates.Messa
 	return clone, true
 }
 
+// PutMessage appends message to the specified conversation, or replaces the
+// last message when both carry the same message_id.
+func (s *ConversationStore) PutMessage(id string, message chattemplates.MessageTemplateData) (*Conversation, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.Lock()
+	conv := s.findByIDLocked(id)
+	if conv == nil {
+		s.mu.Unlock()
+		return nil, false
+	}
+	if n := len(conv.Messages); n > 0 && sameMessage(conv.Messages[n-1], message) {
+		conv.Messages[n-1] = message
+	} else {
+		conv.Messages = append(conv.Messages, message)
+	}
+	conv.UpdatedAt = time.Now()
+	s.sortLocked()
+	clone := conv.Clone()
+	s.mu.Unlock()
+	return clone, true
+}
+
+func sameMessage(a, b chattemplates.MessageTemplateData) bool {
+	idA, _ := a.Metadata["message_id"].(string)
+	idB, _ := b.Metadata["message_id"].(string)
+	return idA != "" && idA == idB && a.Type == b.Type
+}
+
+// SetDraft stores the unsent input of the specified conversation. Drafts are
+// local and do not mark the conversation as updated.
+func (s *ConversationStore) SetDraft(id, draft string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conv := s.findByIDLocked(id)
+	if conv == nil {
+		return false
+	}
+	conv.Draft = draft
+	return true
+}
+
 // UpdateOptions replaces the options of the specified conversation.
 func (s *ConversationStore) UpdateOptions(id string, opts ConversationOptions) bool {
 	if s == nil {
@@ -480,6 +540,9 @@ func (s *ConversationStore) postConversation(cfg remoteSyncConfig, client *http.
 		if data, readErr := io.ReadAll(io.LimitReader(resp.Body, 2048)); readErr == nil {
 			snippet = strings.TrimSpace(string(data))
 		}
+		if transport.IsAuthStatus(resp.StatusCode) {
+			return &transport.AuthError{StatusCode: resp.StatusCode, Detail: snippet}
+		}
 		if snippet != "" {
 			return fmt.Errorf("conversation sync failed: %d %s", resp.StatusCode, snippet)
 		}
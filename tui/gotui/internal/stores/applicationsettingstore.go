@@ -9,8 +9,15 @@ import (
 type ApplicationSettings struct {
 	DefaultModel *ModelOption
 	DefaultAgent *AgentSelection
+	// FavoriteModels and RecentModels are pinned to the top of the model
+	// picker. Recent models are most recent first.
+	FavoriteModels []ModelOption
+	RecentModels   []ModelOption
 }
 
+// MaxRecentModels bounds the recently used models that are remembered.
+const MaxRecentModels = 5
+
 // Clone returns a deep copy of the settings structure to avoid external mutation.
 func (s ApplicationSettings) Clone() ApplicationSettings {
 	copy := ApplicationSettings{}
@@ -22,9 +29,30 @@ func (s ApplicationSettings) Clone() ApplicationSettings {
 		agentCopy := *s.DefaultAgent
 		copy.DefaultAgent = &agentCopy
 	}
+	copy.FavoriteModels = append([]ModelOption(nil), s.FavoriteModels...)
+	copy.RecentModels = append([]ModelOption(nil), s.RecentModels...)
 	return copy
 }
 
+// IsFavoriteModel reports whether model is one of the favourites.
+func (s ApplicationSettings) IsFavoriteModel(model ModelOption) bool {
+	return indexOfModel(s.FavoriteModels, model) >= 0
+}
+
+// SameModel reports whether a and b name the same model.
+func SameModel(a, b ModelOption) bool {
+	return a.Name == b.Name && a.Provider == b.Provider
+}
+
+func indexOfModel(models []ModelOption, model ModelOption) int {
+	for i, other := range models {
+		if SameModel(other, model) {
+			return i
+		}
+	}
+	return -1
+}
+
 type settingsListener func(ApplicationSettings)
 
 // ApplicationSettingsStore provides thread-safe access to global application preferences.
@@ -102,6 +130,59 @@ func (s *ApplicationSettingsStore) SetDefaultModel(model *ModelOption) {
 	}
 }
 
+// ToggleFavoriteModel adds model to the favourites or removes it, reports
+// whether it is now a favourite and notifies listeners.
+func (s *ApplicationSettingsStore) ToggleFavoriteModel(model ModelOption) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	favorite := false
+	if i := indexOfModel(s.settings.FavoriteModels, model); i >= 0 {
+		s.settings.FavoriteModels = append(s.settings.FavoriteModels[:i:i], s.settings.FavoriteModels[i+1:]...)
+	} else {
+		s.settings.FavoriteModels = append(s.settings.FavoriteModels, model)
+		favorite = true
+	}
+	listeners := s.snapshotListenersLocked()
+	current := s.settings.Clone()
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(current)
+	}
+	return favorite
+}
+
+// RecordRecentModel moves model to the front of the recently used models
+// and notifies listeners.
+func (s *ApplicationSettingsStore) RecordRecentModel(model ModelOption) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	recent := s.settings.RecentModels
+	if len(recent) > 0 && SameModel(recent[0], model) {
+		s.mu.Unlock()
+		return
+	}
+	if i := indexOfModel(recent, model); i >= 0 {
+		recent = append(recent[:i:i], recent[i+1:]...)
+	}
+	recent = append([]ModelOption{model}, recent...)
+	if len(recent) > MaxRecentModels {
+		recent = recent[:MaxRecentModels]
+	}
+	s.settings.RecentModels = recent
+	listeners := s.snapshotListenersLocked()
+	current := s.settings.Clone()
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(current)
+	}
+}
+
 // SetDefaultAgent updates the globally configured default agent and notifies listeners.
 func (s *ApplicationSettingsStore) SetDefaultAgent(agent *AgentSelection) {
 	if s == nil {
@@ -0,0 +1,255 @@
+package stores
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Baseline sources for FileChange.Before, in order of trust.
+const (
+	// BaselineEvent means the server sent the previous content with the edit.
+	BaselineEvent = "event"
+	// BaselineDisk means the file was read from disk before the edit landed.
+	BaselineDisk = "disk"
+)
+
+// FileEdit is a single agent write to a file as reported by the server.
+type FileEdit struct {
+	// Path is the absolute path of the file.
+	Path string
+	// Display is the path as reported by the agent.
+	Display   string
+	Operation string
+	// Before is the content prior to this edit when it could be captured;
+	// Existed reports whether the file existed at all.
+	Before   *string
+	Existed  bool
+	Baseline string
+	// After is the full content written, if the event carried it.
+	After *string
+	// Diff is the unified diff of the edit, if the event carried one.
+	Diff string
+	At   time.Time
+}
+
+// FileChange accumulates every edit the agent made to one file during a
+// conversation.
+type FileChange struct {
+	Path      string
+	Display   string
+	Operation string
+	Edits     int
+	// Before and Existed describe the file ahead of the first edit; Baseline
+	// records where they came from and is empty when nothing was captured.
+	Before   *string
+	Existed  bool
+	Baseline string
+	After    *string
+	// Diffs holds one entry per edit that carried a diff, oldest first.
+	Diffs     []string
+	UpdatedAt time.Time
+}
+
+// Clone returns a defensive copy of the change.
+func (c FileChange) Clone() FileChange {
+	copy := c
+	if c.Before != nil {
+		before := *c.Before
+		copy.Before = &before
+	}
+	if c.After != nil {
+		after := *c.After
+		copy.After = &after
+	}
+	copy.Diffs = append([]string(nil), c.Diffs...)
+	return copy
+}
+
+// DiffStat counts added and removed lines across the recorded diffs.
+func (c FileChange) DiffStat() (added, removed int) {
+	for _, diff := range c.Diffs {
+		for _, line := range strings.Split(diff, "\n") {
+			switch {
+			case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			case strings.HasPrefix(line, "+"):
+				added++
+			case strings.HasPrefix(line, "-"):
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+type changeListener func(string)
+
+// ChangeStore tracks the files the agent changed in each conversation.
+type ChangeStore struct {
+	mu        sync.RWMutex
+	changes   map[string]map[string]*FileChange
+	version   uint64
+	listeners map[int64]changeListener
+	nextID    int64
+}
+
+var (
+	sharedChangeStore     *ChangeStore
+	sharedChangeStoreOnce sync.Once
+)
+
+// SharedChangeStore returns the singleton change store.
+func SharedChangeStore() *ChangeStore {
+	sharedChangeStoreOnce.Do(func() {
+		sharedChangeStore = &ChangeStore{
+			changes:   make(map[string]map[string]*FileChange),
+			listeners: make(map[int64]changeListener),
+		}
+	})
+	return sharedChangeStore
+}
+
+// Record merges edit into the conversation's change for the file. The first
+// edit's baseline is kept so a revert restores the pre-conversation content.
+func (s *ChangeStore) Record(conversationID string, edit FileEdit) {
+	if s == nil || strings.TrimSpace(conversationID) == "" || edit.Path == "" {
+		return
+	}
+	s.mu.Lock()
+	files := s.changes[conversationID]
+	if files == nil {
+		files = make(map[string]*FileChange)
+		s.changes[conversationID] = files
+	}
+	change := files[edit.Path]
+	if change == nil {
+		change = &FileChange{Path: edit.Path}
+		files[edit.Path] = change
+	}
+	if change.Edits == 0 || (change.Baseline == "" && edit.Baseline != "") {
+		change.Before = edit.Before
+		change.Existed = edit.Existed
+		change.Baseline = edit.Baseline
+	}
+	change.Display = edit.Display
+	if change.Display == "" {
+		change.Display = edit.Path
+	}
+	change.Operation = edit.Operation
+	change.Edits++
+	if edit.After != nil {
+		change.After = edit.After
+	} else if edit.Operation == "append" {
+		// An append only carries the new text, so the full content is unknown.
+		change.After = nil
+	}
+	if strings.TrimSpace(edit.Diff) != "" {
+		change.Diffs = append(change.Diffs, edit.Diff)
+	}
+	change.UpdatedAt = edit.At
+	if change.UpdatedAt.IsZero() {
+		change.UpdatedAt = time.Now()
+	}
+	s.version++
+	listeners := s.snapshotListenersLocked()
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(conversationID)
+	}
+}
+
+// Changes returns the conversation's changed files, most recent first.
+func (s *ChangeStore) Changes(conversationID string) []FileChange {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	files := s.changes[conversationID]
+	out := make([]FileChange, 0, len(files))
+	for _, change := range files {
+		out = append(out, change.Clone())
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+// Change returns the conversation's change for path.
+func (s *ChangeStore) Change(conversationID, path string) (FileChange, bool) {
+	if s == nil {
+		return FileChange{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	change, ok := s.changes[conversationID][path]
+	if !ok {
+		return FileChange{}, false
+	}
+	return change.Clone(), true
+}
+
+// Remove forgets the change to path, e.g. after it was reverted.
+func (s *ChangeStore) Remove(conversationID, path string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	files := s.changes[conversationID]
+	if _, ok := files[path]; !ok {
+		s.mu.Unlock()
+		return
+	}
+	delete(files, path)
+	if len(files) == 0 {
+		delete(s.changes, conversationID)
+	}
+	s.version++
+	listeners := s.snapshotListenersLocked()
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(conversationID)
+	}
+}
+
+// Version increments on every change so views can cheaply detect updates.
+func (s *ChangeStore) Version() uint64 {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
+
+// Subscribe registers a listener called with the affected conversation id.
+func (s *ChangeStore) Subscribe(listener changeListener) func() {
+	if s == nil || listener == nil {
+		return func() {}
+	}
+	id := atomic.AddInt64(&s.nextID, 1)
+	s.mu.Lock()
+	s.listeners[id] = listener
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		delete(s.listeners, id)
+		s.mu.Unlock()
+	}
+}
+
+func (s *ChangeStore) snapshotListenersLocked() []changeListener {
+	listeners := make([]changeListener, 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
@@ -0,0 +1,35 @@
+package stores
+
+import "net/http"
+
+// validators remembers the ETag and Last-Modified of the last response for a
+// URL so the next request for it can be conditional. The owning store's lock
+// guards it.
+type validators struct {
+	url          string
+	etag         string
+	lastModified string
+}
+
+// apply makes req conditional when the validators belong to its URL.
+func (v *validators) apply(req *http.Request) {
+	if v.url != req.URL.String() {
+		return
+	}
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+}
+
+// update records the validators of a successful response to a request for
+// url.
+func (v *validators) update(url string, header http.Header) {
+	*v = validators{
+		url:          url,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+	}
+}
@@ -0,0 +1,184 @@
+package stores
+
+import (
+	"slices"
+	"sync"
+
+	"gotui/internal/agentcontext"
+)
+
+// ContextStore remembers which context items the user dropped from the
+// requests of each conversation, and the files attached to its next message.
+type ContextStore struct {
+	mu          sync.RWMutex
+	dropped     map[string]map[string]bool
+	attachments map[string][]agentcontext.Attachment
+	version     uint64
+}
+
+var (
+	sharedContextStore     *ContextStore
+	sharedContextStoreOnce sync.Once
+)
+
+// SharedContextStore returns the singleton context store.
+func SharedContextStore() *ContextStore {
+	sharedContextStoreOnce.Do(func() {
+		sharedContextStore = &ContextStore{
+			dropped:     make(map[string]map[string]bool),
+			attachments: make(map[string][]agentcontext.Attachment),
+		}
+	})
+	return sharedContextStore
+}
+
+// SetDropped drops the item with key from, or restores it to, the
+// conversation's requests.
+func (s *ContextStore) SetDropped(conversationID, key string, dropped bool) {
+	if s == nil || key == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := s.dropped[conversationID]
+	if dropped == keys[key] {
+		return
+	}
+	if dropped {
+		if keys == nil {
+			keys = make(map[string]bool)
+			s.dropped[conversationID] = keys
+		}
+		keys[key] = true
+	} else {
+		delete(keys, key)
+	}
+	s.version++
+}
+
+// IsDropped reports whether the item with key is left out of requests.
+func (s *ContextStore) IsDropped(conversationID, key string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dropped[conversationID][key]
+}
+
+// RestoreAll puts every dropped item of the conversation back.
+func (s *ContextStore) RestoreAll(conversationID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.dropped[conversationID]) == 0 {
+		return
+	}
+	delete(s.dropped, conversationID)
+	s.version++
+}
+
+// Attach adds attachment to the conversation's next message. It reports
+// false if the file is already attached.
+func (s *ContextStore) Attach(conversationID string, attachment agentcontext.Attachment) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.attachments[conversationID] {
+		if existing.Path == attachment.Path {
+			return false
+		}
+	}
+	s.attachments[conversationID] = append(s.attachments[conversationID], attachment)
+	s.version++
+	return true
+}
+
+// Attachments returns the files attached to the conversation's next message.
+func (s *ContextStore) Attachments(conversationID string) []agentcontext.Attachment {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]agentcontext.Attachment(nil), s.attachments[conversationID]...)
+}
+
+// Detach removes the attachment with path, deleting it if it is temporary.
+// It reports whether one was attached.
+func (s *ContextStore) Detach(conversationID, path string) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attachments := s.attachments[conversationID]
+	for i, existing := range attachments {
+		if existing.Path == path {
+			s.attachments[conversationID] = append(attachments[:i:i], attachments[i+1:]...)
+			s.version++
+			existing.Release()
+			return true
+		}
+	}
+	return false
+}
+
+// DetachSent removes (and releases) the attachments at paths once the
+// message they went out with was sent, keeping any attached in the meantime.
+func (s *ContextStore) DetachSent(conversationID string, paths []string) {
+	if s == nil || len(paths) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attachments := s.attachments[conversationID]
+	kept := attachments[:0:0]
+	for _, existing := range attachments {
+		if !slices.Contains(paths, existing.Path) {
+			kept = append(kept, existing)
+			continue
+		}
+		existing.Release()
+	}
+	if len(kept) == len(attachments) {
+		return
+	}
+	if len(kept) == 0 {
+		delete(s.attachments, conversationID)
+	} else {
+		s.attachments[conversationID] = kept
+	}
+	s.version++
+}
+
+// ClearAttachments removes every attachment of the conversation.
+func (s *ContextStore) ClearAttachments(conversationID string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.attachments[conversationID]) == 0 {
+		return
+	}
+	for _, attachment := range s.attachments[conversationID] {
+		attachment.Release()
+	}
+	delete(s.attachments, conversationID)
+	s.version++
+}
+
+// Version increments on every change so views can cheaply detect updates.
+func (s *ContextStore) Version() uint64 {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
@@ -0,0 +1,159 @@
+package stores
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NotificationSeverity ranks notifications for display.
+type NotificationSeverity int
+
+const (
+	SeverityInfo NotificationSeverity = iota
+	SeveritySuccess
+	SeverityWarning
+	SeverityError
+)
+
+// maxNotifications bounds the notification history.
+const maxNotifications = 200
+
+// Notification is one entry in the notification centre.
+type Notification struct {
+	ID int64
+	// Event is the notify event type, e.g. notify.EventRunComplete.
+	Event          string
+	Severity       NotificationSeverity
+	Title          string
+	Body           string
+	ConversationID string
+	At             time.Time
+	Read           bool
+}
+
+type notificationListener func(Notification)
+
+// NotificationStore keeps the notification history and its read state.
+type NotificationStore struct {
+	mu         sync.RWMutex
+	items      []Notification
+	nextID     int64
+	listeners  map[int64]notificationListener
+	nextListen int64
+}
+
+var (
+	sharedNotificationStore     *NotificationStore
+	sharedNotificationStoreOnce sync.Once
+)
+
+// SharedNotificationStore returns the singleton notification store.
+func SharedNotificationStore() *NotificationStore {
+	sharedNotificationStoreOnce.Do(func() {
+		sharedNotificationStore = &NotificationStore{listeners: make(map[int64]notificationListener)}
+	})
+	return sharedNotificationStore
+}
+
+// Add records n and notifies subscribers. The stored copy, with its ID and
+// time filled in, is returned.
+func (s *NotificationStore) Add(n Notification) Notification {
+	if s == nil {
+		return n
+	}
+	s.mu.Lock()
+	s.nextID++
+	n.ID = s.nextID
+	if n.At.IsZero() {
+		n.At = time.Now()
+	}
+	s.items = append(s.items, n)
+	if len(s.items) > maxNotifications {
+		s.items = append([]Notification(nil), s.items[len(s.items)-maxNotifications:]...)
+	}
+	listeners := s.snapshotListenersLocked()
+	s.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(n)
+	}
+	return n
+}
+
+// Notifications returns the history, newest first.
+func (s *NotificationStore) Notifications() []Notification {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Notification, len(s.items))
+	for i, n := range s.items {
+		out[len(s.items)-1-i] = n
+	}
+	return out
+}
+
+// Unread counts the notifications not yet seen.
+func (s *NotificationStore) Unread() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, n := range s.items {
+		if !n.Read {
+			count++
+		}
+	}
+	return count
+}
+
+// MarkAllRead marks every notification as seen.
+func (s *NotificationStore) MarkAllRead() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.items {
+		s.items[i].Read = true
+	}
+}
+
+// Clear removes every notification.
+func (s *NotificationStore) Clear() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = nil
+}
+
+// Subscribe registers a listener called with each new notification. Listeners
+// run on the goroutine that added it.
+func (s *NotificationStore) Subscribe(listener notificationListener) func() {
+	if s == nil || listener == nil {
+		return func() {}
+	}
+	id := atomic.AddInt64(&s.nextListen, 1)
+	s.mu.Lock()
+	s.listeners[id] = listener
+	s.mu.Unlock()
+	return func() {
+		s.mu.Lock()
+		delete(s.listeners, id)
+		s.mu.Unlock()
+	}
+}
+
+func (s *NotificationStore) snapshotListenersLocked() []notificationListener {
+	listeners := make([]notificationListener, 0, len(s.listeners))
+	for _, listener := range s.listeners {
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
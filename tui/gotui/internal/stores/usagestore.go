@@ -0,0 +1,169 @@
+package stores
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"gotui/internal/usage"
+)
+
+// maxUsageRecords bounds the per-message history kept for each conversation.
+const maxUsageRecords = 500
+
+// UsageRecord is the usage of one agent reply.
+type UsageRecord struct {
+	ConversationID string
+	Model          string
+	usage.Usage
+	At time.Time
+}
+
+// UsageBudgets are the soft limits warned about once crossed.
+type UsageBudgets struct {
+	Conversation usage.Budget
+	Session      usage.Budget
+}
+
+// BudgetWarning reports that a record took usage past a budget threshold.
+type BudgetWarning struct {
+	// Scope is "conversation" or "session".
+	Scope  string
+	Budget usage.Budget
+	// Threshold is usage.WarnFraction or 1.
+	Threshold float64
+	Usage     usage.Usage
+}
+
+// UsageStore aggregates token usage and cost per message, per conversation
+// and for the session.
+type UsageStore struct {
+	mu            sync.RWMutex
+	prices        usage.Prices
+	budgets       UsageBudgets
+	session       usage.Usage
+	conversations map[string]usage.Usage
+	records       map[string][]UsageRecord
+	version       uint64
+}
+
+var (
+	sharedUsageStore     *UsageStore
+	sharedUsageStoreOnce sync.Once
+)
+
+// SharedUsageStore returns the singleton usage store.
+func SharedUsageStore() *UsageStore {
+	sharedUsageStoreOnce.Do(func() {
+		sharedUsageStore = &UsageStore{
+			prices:        usage.DefaultPrices,
+			conversations: make(map[string]usage.Usage),
+			records:       make(map[string][]UsageRecord),
+		}
+	})
+	return sharedUsageStore
+}
+
+// Configure sets the price table used for estimates and the soft budgets.
+func (s *UsageStore) Configure(prices usage.Prices, budgets UsageBudgets) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices = prices
+	s.budgets = budgets
+	s.version++
+}
+
+// Budgets returns the configured soft budgets.
+func (s *UsageStore) Budgets() UsageBudgets {
+	if s == nil {
+		return UsageBudgets{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.budgets
+}
+
+// Record adds a reply's usage, estimating its cost from model when the
+// server did not price it, and returns the budgets it pushed past a warning
+// threshold.
+func (s *UsageStore) Record(conversationID, model string, u usage.Usage) []BudgetWarning {
+	if s == nil || u.IsZero() {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u = s.prices.Estimate(model, u)
+
+	var warnings []BudgetWarning
+	check := func(scope string, budget usage.Budget, before, after usage.Usage) {
+		if threshold := budget.Crossed(before, after); threshold > 0 {
+			warnings = append(warnings, BudgetWarning{Scope: scope, Budget: budget, Threshold: threshold, Usage: after})
+		}
+	}
+
+	conversationID = strings.TrimSpace(conversationID)
+	before := s.conversations[conversationID]
+	after := before.Add(u)
+	s.conversations[conversationID] = after
+	check("conversation", s.budgets.Conversation, before, after)
+
+	sessionBefore := s.session
+	s.session = s.session.Add(u)
+	check("session", s.budgets.Session, sessionBefore, s.session)
+
+	records := append(s.records[conversationID], UsageRecord{
+		ConversationID: conversationID,
+		Model:          model,
+		Usage:          u,
+		At:             time.Now(),
+	})
+	if len(records) > maxUsageRecords {
+		records = append([]UsageRecord(nil), records[len(records)-maxUsageRecords:]...)
+	}
+	s.records[conversationID] = records
+	s.version++
+	return warnings
+}
+
+// Session returns the usage since the TUI started.
+func (s *UsageStore) Session() usage.Usage {
+	if s == nil {
+		return usage.Usage{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.session
+}
+
+// Conversation returns the conversation's accumulated usage.
+func (s *UsageStore) Conversation(conversationID string) usage.Usage {
+	if s == nil {
+		return usage.Usage{}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.conversations[conversationID]
+}
+
+// Records returns the conversation's per-reply usage, oldest first.
+func (s *UsageStore) Records(conversationID string) []UsageRecord {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]UsageRecord(nil), s.records[conversationID]...)
+}
+
+// Version increments on every change so views can cheaply detect updates.
+func (s *UsageStore) Version() uint64 {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version
+}
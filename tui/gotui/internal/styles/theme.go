@@ -33,6 +33,8 @@ type ThemePreset struct {
 	Name        string
 	Description string
 	Theme       Theme
+	// Source is the file a user-defined theme was loaded from; empty for built-ins.
+	Source string
 }
 
 var themePresets = []ThemePreset{
@@ -96,6 +98,26 @@ var themePresets = []ThemePreset{
 			SurfaceHighest: mustParse("#1b3a28"),
 		},
 	},
+	{
+		Name:        "Paper Dawn",
+		Description: "Soft ink tones on a warm paper background",
+		Theme: Theme{
+			Primary:        mustParse("#2563eb"),
+			Secondary:      mustParse("#7c3aed"),
+			Accent:         mustParse("#c2410c"),
+			Background:     mustParse("#faf8f5"),
+			Foreground:     mustParse("#1f2937"),
+			Muted:          mustParse("#6b7280"),
+			Border:         mustParse("#d6d3d1"),
+			Success:        mustParse("#15803d"),
+			Warning:        mustParse("#b45309"),
+			Error:          mustParse("#b91c1c"),
+			Info:           mustParse("#0369a1"),
+			Surface:        mustParse("#f3f0eb"),
+			SurfaceHigh:    mustParse("#ebe6df"),
+			SurfaceHighest: mustParse("#e1dbd2"),
+		},
+	},
 }
 
 var currentThemeName = themePresets[0].Name
@@ -105,13 +127,26 @@ func DefaultTheme() Theme {
 	return themePresets[0].Theme
 }
 
-// PresetThemes returns all available theme presets.
+// PresetThemes returns all available theme presets, built-in ones first followed
+// by user-defined themes. A user theme with a built-in name replaces the built-in.
 func PresetThemes() []ThemePreset {
-	out := make([]ThemePreset, len(themePresets))
-	copy(out, themePresets)
+	out := make([]ThemePreset, 0, len(themePresets)+len(customThemes))
+	for _, preset := range themePresets {
+		if _, overridden := findPreset(customThemes, preset.Name); overridden {
+			continue
+		}
+		out = append(out, preset)
+	}
+	out = append(out, customThemes...)
 	return out
 }
 
+// IsDark reports whether the theme is designed for a dark terminal background.
+func (t Theme) IsDark() bool {
+	_, _, l := t.Background.Hsl()
+	return l < 0.5
+}
+
 // CurrentThemeName exposes the active theme preset name, if any.
 func CurrentThemeName() string {
 	return currentThemeName
@@ -263,14 +298,39 @@ func SetTheme(theme Theme) {
 
 // SetThemeByName applies a theme preset by name (case-insensitive).
 func SetThemeByName(name string) bool {
-	for _, preset := range themePresets {
-		if strings.EqualFold(preset.Name, name) {
+	preset, ok := findPreset(PresetThemes(), name)
+	if !ok {
+		return false
+	}
+	SetTheme(preset.Theme)
+	currentThemeName = preset.Name
+	return true
+}
+
+// SelectThemeForBackground switches to the first preset matching the terminal
+// background when the current theme does not. It returns the applied preset
+// name, or an empty string when nothing changed.
+func SelectThemeForBackground(dark bool) string {
+	if currentTheme.IsDark() == dark {
+		return ""
+	}
+	for _, preset := range PresetThemes() {
+		if preset.Theme.IsDark() == dark {
 			SetTheme(preset.Theme)
 			currentThemeName = preset.Name
-			return true
+			return preset.Name
+		}
+	}
+	return ""
+}
+
+func findPreset(presets []ThemePreset, name string) (ThemePreset, bool) {
+	for _, preset := range presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
 		}
 	}
-	return false
+	return ThemePreset{}, false
 }
 
 func mustParse(hex string) colorful.Color {
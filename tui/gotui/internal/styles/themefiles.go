@@ -0,0 +1,166 @@
+package styles
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+
+	"gotui/internal/configfile"
+)
+
+// themeFile is the on-disk representation of a user-defined theme, e.g.
+//
+//	name = "Solarized Light"
+//	description = "Precision colors"
+//	[colors]
+//	primary = "#268bd2"
+//	...
+type themeFile struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Colors      map[string]string `json:"colors"`
+}
+
+// themeColorFields maps file keys to the Theme field they populate. Every key is
+// required so a theme file can never leave part of the UI uncoloured.
+var themeColorFields = []struct {
+	key string
+	ptr func(*Theme) *colorful.Color
+}{
+	{"primary", func(t *Theme) *colorful.Color { return &t.Primary }},
+	{"secondary", func(t *Theme) *colorful.Color { return &t.Secondary }},
+	{"accent", func(t *Theme) *colorful.Color { return &t.Accent }},
+	{"background", func(t *Theme) *colorful.Color { return &t.Background }},
+	{"foreground", func(t *Theme) *colorful.Color { return &t.Foreground }},
+	{"muted", func(t *Theme) *colorful.Color { return &t.Muted }},
+	{"border", func(t *Theme) *colorful.Color { return &t.Border }},
+	{"success", func(t *Theme) *colorful.Color { return &t.Success }},
+	{"warning", func(t *Theme) *colorful.Color { return &t.Warning }},
+	{"error", func(t *Theme) *colorful.Color { return &t.Error }},
+	{"info", func(t *Theme) *colorful.Color { return &t.Info }},
+	{"surface", func(t *Theme) *colorful.Color { return &t.Surface }},
+	{"surface_high", func(t *Theme) *colorful.Color { return &t.SurfaceHigh }},
+	{"surface_highest", func(t *Theme) *colorful.Color { return &t.SurfaceHighest }},
+}
+
+var customThemes []ThemePreset
+
+// ThemeDirs returns the directories searched for theme files: the user config
+// directory first, then the project, so project themes win on name clashes.
+func ThemeDirs(projectPath string) []string {
+	var dirs []string
+	if dir := configfile.UserDir(); dir != "" {
+		dirs = append(dirs, filepath.Join(dir, "themes"))
+	}
+	if dir := configfile.ProjectDir(projectPath); dir != "" {
+		dirs = append(dirs, filepath.Join(dir, "themes"))
+	}
+	return dirs
+}
+
+// LoadThemeFiles reads every theme file in dirs. Invalid files are skipped and
+// reported in the returned errors; later directories override earlier ones.
+func LoadThemeFiles(dirs ...string) ([]ThemePreset, []error) {
+	var (
+		presets []ThemePreset
+		errs    []error
+	)
+	for _, dir := range dirs {
+		for _, path := range configfile.ListFiles(dir) {
+			preset, err := LoadThemeFile(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			replaced := false
+			for i := range presets {
+				if strings.EqualFold(presets[i].Name, preset.Name) {
+					presets[i] = preset
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				presets = append(presets, preset)
+			}
+		}
+	}
+	return presets, errs
+}
+
+// LoadThemeFile parses and validates a single JSON or TOML theme file.
+func LoadThemeFile(path string) (ThemePreset, error) {
+	var file themeFile
+	if err := configfile.Decode(path, &file); err != nil {
+		return ThemePreset{}, err
+	}
+
+	name := strings.TrimSpace(file.Name)
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	theme, err := parseThemeColors(file.Colors)
+	if err != nil {
+		return ThemePreset{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return ThemePreset{
+		Name:        name,
+		Description: strings.TrimSpace(file.Description),
+		Theme:       theme,
+		Source:      path,
+	}, nil
+}
+
+func parseThemeColors(colors map[string]string) (Theme, error) {
+	var (
+		theme Theme
+		errs  []error
+	)
+	known := make(map[string]bool, len(themeColorFields))
+	for _, field := range themeColorFields {
+		known[field.key] = true
+		raw, ok := colors[field.key]
+		if !ok || strings.TrimSpace(raw) == "" {
+			errs = append(errs, fmt.Errorf("missing color %q", field.key))
+			continue
+		}
+		parsed, err := colorful.Hex(strings.TrimSpace(raw))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("color %q: invalid hex value %q", field.key, raw))
+			continue
+		}
+		*field.ptr(&theme) = parsed
+	}
+
+	var unknown []string
+	for key := range colors {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		errs = append(errs, fmt.Errorf("unknown color %q", key))
+	}
+
+	return theme, errors.Join(errs...)
+}
+
+// SetCustomThemes replaces the set of user-defined themes. If the active theme
+// came from a file it is re-applied so edits take effect immediately.
+func SetCustomThemes(presets []ThemePreset) {
+	customThemes = append([]ThemePreset(nil), presets...)
+	if currentThemeName == "" {
+		return
+	}
+	if preset, ok := findPreset(customThemes, currentThemeName); ok {
+		SetTheme(preset.Theme)
+		currentThemeName = preset.Name
+	}
+}
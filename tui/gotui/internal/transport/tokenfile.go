@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// ReadTokenFile reads a token from path. The first non-empty line that is not
+// a "#" comment is the token. Like ssh private keys, the file must not be
+// readable by other users.
+func ReadTokenFile(path string) (string, error) {
+	if err := CheckPrivate(path); err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return line, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s: no token found", path)
+}
+
+// CheckPrivate returns an error if path is accessible by group or others.
+// Permission bits are not meaningful on Windows, where the check is skipped.
+func CheckPrivate(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Errorf("%s: permissions %#o are too open; run chmod 600 %s", path, perm, path)
+	}
+	return nil
+}
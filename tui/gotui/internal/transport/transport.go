@@ -0,0 +1,184 @@
+// Package transport builds the authenticated, TLS-configured HTTP and websocket
+// settings used to talk to the agent server.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Authentication schemes accepted by Credentials.Scheme.
+const (
+	SchemeBearer = "bearer"
+	SchemeAPIKey = "api-key"
+)
+
+// APIKeyHeader carries the token when the api-key scheme is used.
+const APIKeyHeader = "X-API-Key"
+
+// Credentials authenticate requests to the agent server.
+type Credentials struct {
+	Scheme string
+	Token  string
+}
+
+// IsZero reports whether no token is configured.
+func (c Credentials) IsZero() bool {
+	return strings.TrimSpace(c.Token) == ""
+}
+
+// Describe returns a short label for the scheme, e.g. "bearer token".
+func (c Credentials) Describe() string {
+	if c.IsZero() {
+		return "none"
+	}
+	if c.scheme() == SchemeAPIKey {
+		return "API key"
+	}
+	return "bearer token"
+}
+
+func (c Credentials) scheme() string {
+	if strings.EqualFold(strings.TrimSpace(c.Scheme), SchemeAPIKey) {
+		return SchemeAPIKey
+	}
+	return SchemeBearer
+}
+
+// Apply sets the authentication header on h.
+func (c Credentials) Apply(h http.Header) {
+	if c.IsZero() {
+		return
+	}
+	token := strings.TrimSpace(c.Token)
+	if c.scheme() == SchemeAPIKey {
+		h.Set(APIKeyHeader, token)
+		return
+	}
+	h.Set("Authorization", "Bearer "+token)
+}
+
+// Header returns a new header carrying the credentials.
+func (c Credentials) Header() http.Header {
+	h := http.Header{}
+	c.Apply(h)
+	return h
+}
+
+// TLSOptions locates certificate material for wss/https connections.
+type TLSOptions struct {
+	CAFile             string
+	CertFile           string
+	KeyFile            string
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+// IsZero reports whether the system defaults should be used.
+func (o TLSOptions) IsZero() bool {
+	return o == TLSOptions{}
+}
+
+// Config builds a tls.Config from the options. A nil config means the Go
+// defaults apply.
+func (o TLSOptions) Config() (*tls.Config, error) {
+	if o.IsZero() {
+		return nil, nil
+	}
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         strings.TrimSpace(o.ServerName),
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+
+	if o.CAFile != "" {
+		pem, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls ca_file: no certificates found in %s", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	switch {
+	case o.CertFile != "" && o.KeyFile != "":
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	case o.CertFile != "" || o.KeyFile != "":
+		return nil, errors.New("tls: cert_file and key_file must be set together")
+	}
+	return cfg, nil
+}
+
+// Settings bundles everything needed to reach one agent server.
+type Settings struct {
+	Credentials Credentials
+	TLS         *tls.Config
+}
+
+// HTTPClient returns a client that authenticates every request and verifies
+// TLS according to the settings.
+func (s Settings) HTTPClient(timeout time.Duration) *http.Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if s.TLS != nil {
+		base.TLSClientConfig = s.TLS.Clone()
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &authTransport{base: base, credentials: s.Credentials},
+	}
+}
+
+type authTransport struct {
+	base        http.RoundTripper
+	credentials Credentials
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.credentials.IsZero() {
+		return t.base.RoundTrip(req)
+	}
+	clone := req.Clone(req.Context())
+	t.credentials.Apply(clone.Header)
+	return t.base.RoundTrip(clone)
+}
+
+// AuthError reports that the server rejected the credentials.
+type AuthError struct {
+	StatusCode int
+	Detail     string
+}
+
+func (e *AuthError) Error() string {
+	msg := fmt.Sprintf("authentication failed (%d %s)", e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+	return msg
+}
+
+// IsAuthStatus reports whether an HTTP status code signals rejected credentials.
+func IsAuthStatus(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
+// IsAuthError reports whether err, or an error it wraps, is an AuthError.
+func IsAuthError(err error) bool {
+	var authErr *AuthError
+	return errors.As(err, &authErr)
+}
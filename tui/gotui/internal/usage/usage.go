@@ -0,0 +1,344 @@
+// Package usage extracts token usage reported by the server, estimates its
+// cost and checks it against soft budgets.
+package usage
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Usage counts the tokens of one or more model calls.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	// CachedTokens is the part of PromptTokens served from the prompt cache.
+	CachedTokens int
+	// Cost is in US dollars. CostKnown is false when no price was available,
+	// in which case Cost only covers the calls that could be priced.
+	Cost      float64
+	CostKnown bool
+	// CostEstimated is set when part of Cost came from a price table rather
+	// than the server.
+	CostEstimated bool
+}
+
+// Total returns prompt plus completion tokens.
+func (u Usage) Total() int { return u.PromptTokens + u.CompletionTokens }
+
+// IsZero reports whether no tokens were counted.
+func (u Usage) IsZero() bool { return u.Total() == 0 && u.Cost == 0 }
+
+// Add returns the sum of u and other. The cost stays known only if both were.
+func (u Usage) Add(other Usage) Usage {
+	known := other.CostKnown
+	if !u.IsZero() {
+		known = u.CostKnown && other.CostKnown
+	}
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		CachedTokens:     u.CachedTokens + other.CachedTokens,
+		Cost:             u.Cost + other.Cost,
+		CostKnown:        known,
+		CostEstimated:    u.CostEstimated || other.CostEstimated,
+	}
+}
+
+// Report is usage found in a server message.
+type Report struct {
+	Usage
+	// Model is the model the server says it used, if any.
+	Model string
+}
+
+// usageKeys are the envelope paths that may hold a usage object.
+var usageKeys = [][]string{
+	{"usage"},
+	{"data", "usage"},
+	{"payload", "usage"},
+	{"message", "usage"},
+	{"response", "usage"},
+	{"metadata", "usage"},
+}
+
+// FromEnvelope finds a usage object in a decoded server message. OpenAI
+// (prompt_tokens), Anthropic (input_tokens) and camelCase spellings are
+// understood; a "cost" field is taken as the server's own price.
+func FromEnvelope(envelope map[string]any) (Report, bool) {
+	for _, path := range usageKeys {
+		raw, ok := lookup(envelope, path).(map[string]any)
+		if !ok {
+			continue
+		}
+		report := Report{}
+		report.PromptTokens = firstInt(raw, "prompt_tokens", "promptTokens", "input_tokens", "inputTokens")
+		report.CompletionTokens = firstInt(raw, "completion_tokens", "completionTokens", "output_tokens", "outputTokens")
+		report.CachedTokens = firstInt(raw, "cached_tokens", "cachedTokens", "cache_read_input_tokens", "cacheReadInputTokens")
+		if details, ok := raw["prompt_tokens_details"].(map[string]any); ok && report.CachedTokens == 0 {
+			report.CachedTokens = firstInt(details, "cached_tokens")
+		}
+		if report.PromptTokens == 0 && report.CompletionTokens == 0 {
+			// Only a total was reported; count it as prompt tokens.
+			report.PromptTokens = firstInt(raw, "total_tokens", "totalTokens")
+		}
+		if cost, ok := number(firstOf(raw, "cost", "total_cost", "totalCost")); ok {
+			report.Cost, report.CostKnown = cost, true
+		}
+		if report.IsZero() {
+			continue
+		}
+		report.Model = firstString(
+			lookup(envelope, path[:len(path)-1]), "model", "modelName",
+		)
+		if report.Model == "" {
+			report.Model = firstString(envelope, "model", "modelName")
+		}
+		return report, true
+	}
+	return Report{}, false
+}
+
+// Price is a model's price in US dollars per million tokens.
+type Price struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+	// Cached is the price of cached prompt tokens; zero uses Input.
+	Cached float64 `json:"cached,omitempty"`
+}
+
+// Prices maps model name prefixes, lower case, to their price.
+type Prices map[string]Price
+
+// DefaultPrices are estimated list prices of common models, used when
+// neither the server nor the config file supplies one. They are not kept up
+// to date; costs priced from them are shown as estimates.
+var DefaultPrices = Prices{
+	"gpt-4.1":           {Input: 2, Output: 8, Cached: 0.5},
+	"gpt-4.1-mini":      {Input: 0.4, Output: 1.6, Cached: 0.1},
+	"gpt-4.1-nano":      {Input: 0.1, Output: 0.4, Cached: 0.025},
+	"gpt-4o":            {Input: 2.5, Output: 10, Cached: 1.25},
+	"gpt-4o-mini":       {Input: 0.15, Output: 0.6, Cached: 0.075},
+	"o3":                {Input: 2, Output: 8, Cached: 0.5},
+	"o4-mini":           {Input: 1.1, Output: 4.4, Cached: 0.275},
+	"claude-opus-4":     {Input: 15, Output: 75, Cached: 1.5},
+	"claude-sonnet-4":   {Input: 3, Output: 15, Cached: 0.3},
+	"claude-3-7-sonnet": {Input: 3, Output: 15, Cached: 0.3},
+	"claude-3-5-haiku":  {Input: 0.8, Output: 4, Cached: 0.08},
+	"gemini-2.5-pro":    {Input: 1.25, Output: 10},
+	"gemini-2.5-flash":  {Input: 0.3, Output: 2.5},
+}
+
+// Merge returns p with overrides applied on top.
+func (p Prices) Merge(overrides Prices) Prices {
+	merged := make(Prices, len(p)+len(overrides))
+	for model, price := range p {
+		merged[model] = price
+	}
+	for model, price := range overrides {
+		merged[strings.ToLower(strings.TrimSpace(model))] = price
+	}
+	return merged
+}
+
+// Lookup returns the price of the longest prefix matching model. Provider
+// prefixes such as "openai/" are ignored.
+func (p Prices) Lookup(model string) (Price, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if i := strings.LastIndex(model, "/"); i >= 0 {
+		if price, ok := p[model]; ok {
+			return price, true
+		}
+		model = model[i+1:]
+	}
+	best, found := "", false
+	for prefix := range p {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(best) {
+			best, found = prefix, true
+		}
+	}
+	if !found {
+		return Price{}, false
+	}
+	return p[best], true
+}
+
+// Estimate prices u for model unless the server already did.
+func (p Prices) Estimate(model string, u Usage) Usage {
+	if u.CostKnown {
+		return u
+	}
+	price, ok := p.Lookup(model)
+	if !ok {
+		return u
+	}
+	cached := min(u.CachedTokens, u.PromptTokens)
+	cachedPrice := price.Cached
+	if cachedPrice == 0 {
+		cachedPrice = price.Input
+	}
+	u.Cost = (float64(u.PromptTokens-cached)*price.Input +
+		float64(cached)*cachedPrice +
+		float64(u.CompletionTokens)*price.Output) / 1e6
+	u.CostKnown = true
+	u.CostEstimated = true
+	return u
+}
+
+// Budget is a soft limit; zero fields are unlimited.
+type Budget struct {
+	Tokens int     `json:"tokens,omitempty"`
+	Cost   float64 `json:"cost,omitempty"`
+}
+
+// IsZero reports whether the budget sets no limit.
+func (b Budget) IsZero() bool { return b.Tokens <= 0 && b.Cost <= 0 }
+
+// WarnFraction is the share of a budget at which a first warning is raised.
+const WarnFraction = 0.8
+
+// Fraction returns how much of the budget u uses, taking the larger of the
+// token and cost shares.
+func (b Budget) Fraction(u Usage) float64 {
+	fraction := 0.0
+	if b.Tokens > 0 {
+		fraction = float64(u.Total()) / float64(b.Tokens)
+	}
+	if b.Cost > 0 {
+		fraction = math.Max(fraction, u.Cost/b.Cost)
+	}
+	return fraction
+}
+
+// Crossed returns the threshold (WarnFraction or 1) that going from before to
+// after passed, or 0 if none was.
+func (b Budget) Crossed(before, after Usage) float64 {
+	if b.IsZero() {
+		return 0
+	}
+	was, now := b.Fraction(before), b.Fraction(after)
+	switch {
+	case was < 1 && now >= 1:
+		return 1
+	case was < WarnFraction && now >= WarnFraction:
+		return WarnFraction
+	}
+	return 0
+}
+
+// String describes the budget, e.g. "50k tokens / $2.00".
+func (b Budget) String() string {
+	var parts []string
+	if b.Tokens > 0 {
+		parts = append(parts, FormatTokens(b.Tokens)+" tokens")
+	}
+	if b.Cost > 0 {
+		parts = append(parts, FormatCost(b.Cost))
+	}
+	return strings.Join(parts, " / ")
+}
+
+// FormatTokens abbreviates a token count, e.g. 12345 as "12.3k".
+func FormatTokens(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 10_000:
+		return fmt.Sprintf("%.0fk", float64(n)/1e3)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return strconv.Itoa(n)
+}
+
+// FormatCost formats dollars with enough precision for small amounts.
+func FormatCost(cost float64) string {
+	switch {
+	case cost == 0:
+		return "$0"
+	case cost < 0.01:
+		return fmt.Sprintf("$%.4f", cost)
+	case cost < 1:
+		return fmt.Sprintf("$%.3f", cost)
+	}
+	return fmt.Sprintf("$%.2f", cost)
+}
+
+// Describe summarizes u, e.g. "12.3k tokens (9.1k in · 3.2k out) · ~$0.042".
+// The cost is formatted by DescribeCost.
+func Describe(u Usage) string {
+	text := fmt.Sprintf("%s tokens (%s in · %s out)", FormatTokens(u.Total()), FormatTokens(u.PromptTokens), FormatTokens(u.CompletionTokens))
+	if cost := DescribeCost(u); cost != "" {
+		text += " · " + cost
+	}
+	return text
+}
+
+// DescribeCost formats the cost of u: as is when the server reported it,
+// marked with "~" when it was estimated and with "≥" when some calls could
+// not be priced. It is "" when nothing was priced.
+func DescribeCost(u Usage) string {
+	if u.Cost <= 0 {
+		return ""
+	}
+	switch {
+	case !u.CostKnown:
+		return "≥" + FormatCost(u.Cost)
+	case u.CostEstimated:
+		return "~" + FormatCost(u.Cost)
+	}
+	return FormatCost(u.Cost)
+}
+
+func lookup(m map[string]any, path []string) any {
+	var current any = m
+	for _, key := range path {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = obj[key]
+	}
+	return current
+}
+
+func firstOf(m map[string]any, keys ...string) any {
+	for _, key := range keys {
+		if value, ok := m[key]; ok && value != nil {
+			return value
+		}
+	}
+	return nil
+}
+
+func firstInt(m map[string]any, keys ...string) int {
+	value, _ := number(firstOf(m, keys...))
+	return int(value)
+}
+
+func firstString(v any, keys ...string) string {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return ""
+	}
+	for _, key := range keys {
+		if s, ok := m[key].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,182 @@
+// Package windowlayout persists the window-mode layouts of a project: the
+// layout of the last session, restored at startup, and layouts saved by name.
+package windowlayout
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gotui/internal/configfile"
+)
+
+// Window is the position and size of one conversation window. Windows are
+// identified by slot, the order they were opened in, because conversation
+// IDs do not survive a restart.
+type Window struct {
+	Slot   int `json:"slot"`
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Layout is a snapshot of window mode.
+type Layout struct {
+	// Windows are ordered back to front.
+	Windows []Window `json:"windows"`
+	// Focused is the slot of the focused window, or -1.
+	Focused  int  `json:"focusedSlot"`
+	AutoTile bool `json:"autoTile,omitempty"`
+	// Hidden are the slots of the windows closed from the keyboard.
+	Hidden []int `json:"hiddenSlots,omitempty"`
+}
+
+type file struct {
+	Last  *Layout           `json:"last,omitempty"`
+	Named map[string]Layout `json:"named,omitempty"`
+}
+
+// Store holds the layouts of one project.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data file
+}
+
+// PathFor returns the layout file of projectPath inside the user config
+// directory.
+func PathFor(projectPath string) string {
+	return configfile.UserProjectFile("layouts", projectPath, ".json")
+}
+
+// Open loads the layouts stored at path. A missing file has no layouts; an
+// empty path keeps them in memory only.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path}
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s.data); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// Last returns the layout the previous session ended with.
+func (s *Store) Last() (Layout, bool) {
+	if s == nil {
+		return Layout{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data.Last == nil {
+		return Layout{}, false
+	}
+	return *s.data.Last, true
+}
+
+// SetLast records the current layout for the next session.
+func (s *Store) SetLast(layout Layout) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Last = &layout
+	return s.write()
+}
+
+// Names returns the names of the saved layouts, sorted.
+func (s *Store) Names() []string {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.data.Named))
+	for name := range s.data.Named {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// Named returns the layout saved as name, ignoring case, and its stored name.
+func (s *Store) Named(name string) (Layout, string, bool) {
+	if s == nil {
+		return Layout{}, "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := s.keyLocked(name)
+	if key == "" {
+		return Layout{}, "", false
+	}
+	return s.data.Named[key], key, true
+}
+
+// Save stores layout as name, replacing a layout of the same name in any case.
+func (s *Store) Save(name string, layout Layout) error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key := s.keyLocked(name); key != "" {
+		delete(s.data.Named, key)
+	}
+	if s.data.Named == nil {
+		s.data.Named = make(map[string]Layout)
+	}
+	s.data.Named[name] = layout
+	return s.write()
+}
+
+// Delete removes the layout saved as name and reports whether there was one.
+func (s *Store) Delete(name string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := s.keyLocked(name)
+	if key == "" {
+		return false, nil
+	}
+	delete(s.data.Named, key)
+	return true, s.write()
+}
+
+func (s *Store) keyLocked(name string) string {
+	name = strings.TrimSpace(name)
+	for key := range s.data.Named {
+		if strings.EqualFold(key, name) {
+			return key
+		}
+	}
+	return ""
+}
+
+// write replaces the file with the in-memory layouts. Callers hold mu.
+func (s *Store) write() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	return configfile.WriteFile(s.path, append(data, '\n'))
+}
@@ -2,9 +2,12 @@ package wsclient
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
 	"strings"
 	"sync"
@@ -14,6 +17,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"gotui/internal/logging"
+	"gotui/internal/transport"
 )
 
 type Message struct {
@@ -40,6 +44,32 @@ type Notification struct {
 	Timestamp int64       `json:"timestamp"`
 }
 
+// LogRecord is a server log line streamed after SubscribeLogs.
+type LogRecord struct {
+	Type    string `json:"type"`
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	Source  string `json:"source,omitempty"`
+	// Timestamp is epoch milliseconds or an RFC 3339 string.
+	Timestamp json.RawMessage `json:"timestamp,omitempty"`
+	Data      map[string]any  `json:"data,omitempty"`
+}
+
+// Time returns when the server logged the record, or the zero time.
+func (r LogRecord) Time() time.Time {
+	var millis int64
+	if err := json.Unmarshal(r.Timestamp, &millis); err == nil && millis > 0 {
+		return time.UnixMilli(millis)
+	}
+	var text string
+	if err := json.Unmarshal(r.Timestamp, &text); err == nil {
+		if t, err := time.Parse(time.RFC3339Nano, text); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
 type Config struct {
 	Host        string
 	Port        int
@@ -48,6 +78,10 @@ type Config struct {
 	ProjectPath string
 	ProjectName string
 	ProjectType string
+	// Header is sent with the websocket handshake, e.g. for authentication.
+	Header http.Header
+	// TLS configures certificate verification for wss connections.
+	TLS *tls.Config
 }
 
 type Client struct {
@@ -59,15 +93,21 @@ type Client struct {
 	logf      func(string)
 	onNotif   func(Notification)
 	onMessage func([]byte)
+	onLog     func(LogRecord)
 	config    Config
 	tuiID     string
 	writeMu   sync.Mutex
 }
 
 func New(cfg Config) *Client {
-	protocol := strings.TrimSpace(cfg.Protocol)
-	if protocol == "" {
+	// Profiles may name the server's http(s) address; the websocket runs on
+	// the same host and port.
+	protocol := strings.ToLower(strings.TrimSpace(cfg.Protocol))
+	switch protocol {
+	case "", "http":
 		protocol = "ws"
+	case "https":
+		protocol = "wss"
 	}
 
 	tuiID := strings.TrimSpace(cfg.TuiID)
@@ -97,9 +137,10 @@ func New(cfg Config) *Client {
 	return &Client{
 		url:       u.String(),
 		pending:   make(map[string]chan Response),
-		logf:      func(msg string) { logging.Printf("%s", msg) },
+		logf:      func(msg string) { logging.Info(msg, "source", "ws") },
 		onNotif:   func(Notification) {},
 		onMessage: func([]byte) {},
+		onLog:     func(LogRecord) {},
 		config:    cfg,
 		tuiID:     tuiID,
 	}
@@ -108,6 +149,12 @@ func New(cfg Config) *Client {
 func (c *Client) SetLogger(logf func(string))          { c.logf = logf }
 func (c *Client) OnNotification(fn func(Notification)) { c.onNotif = fn }
 func (c *Client) OnMessage(fn func([]byte))            { c.onMessage = fn }
+func (c *Client) OnLog(fn func(LogRecord))             { c.onLog = fn }
+
+// SubscribeLogs asks the server to stream its log records at or above level.
+func (c *Client) SubscribeLogs(level string) error {
+	return c.Send("subscribeLogs", map[string]any{"level": level})
+}
 
 func (c *Client) Connect(ctx context.Context) error {
 	c.mu.RLock()
@@ -122,9 +169,17 @@ func (c *Client) Connect(ctx context.Context) error {
 		return err
 	}
 
-	d := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
-	conn, _, err := d.DialContext(ctx, u.String(), nil)
+	d := websocket.Dialer{HandshakeTimeout: 10 * time.Second, TLSClientConfig: c.config.TLS}
+	conn, resp, err := d.DialContext(ctx, u.String(), c.config.Header.Clone())
 	if err != nil {
+		if resp != nil && transport.IsAuthStatus(resp.StatusCode) {
+			detail := ""
+			if resp.Body != nil {
+				body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+				detail = strings.TrimSpace(string(body))
+			}
+			return &transport.AuthError{StatusCode: resp.StatusCode, Detail: detail}
+		}
 		return err
 	}
 
@@ -138,7 +193,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.connected = true
 	c.mu.Unlock()
 
-	logging.Printf("Connected to %s", c.url)
+	logging.Info("websocket connected", "url", c.url)
 
 	if err := c.sendRaw(map[string]any{
 		"id":         uuid.NewString(),
@@ -146,7 +201,7 @@ func (c *Client) Connect(ctx context.Context) error {
 		"clientType": "tui",
 		"clientId":   c.tuiID,
 	}); err != nil {
-		logging.Printf("Failed to send registration message: %v", err)
+		logging.Error("websocket registration failed", "err", err)
 		c.mu.Lock()
 		if c.conn != nil {
 			_ = c.conn.Close()
@@ -182,7 +237,7 @@ func (c *Client) readLoop() {
 	for {
 		_, data, err := c.conn.ReadMessage()
 		if err != nil {
-			logging.Printf("WebSocket read error: %v", err)
+			logging.Warn("websocket read failed", "err", err)
 			c.mu.Lock()
 			c.connected = false
 			c.mu.Unlock()
@@ -196,12 +251,17 @@ func (c *Client) readLoop() {
 				continue
 			}
 		}
+		var record LogRecord
+		if err := json.Unmarshal(data, &record); err == nil && (record.Type == "serverLog" || record.Type == "logEntry") {
+			c.onLog(record)
+			continue
+		}
 		var notif Notification
 		if err := json.Unmarshal(data, &notif); err == nil && (notif.Type == "notification" || notif.Type == "fsnotify") {
 			c.onNotif(notif)
 			continue
 		}
-		logging.Printf("WS recv: %s", string(data))
+		logging.Debug("websocket message", "body", string(data))
 		c.onMessage(data)
 	}
 }